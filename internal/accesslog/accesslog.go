@@ -0,0 +1,218 @@
+// Package accesslog records logins and authenticated requests in the access log. Entries are
+// written in the background, in batches, so that recording them does not slow down requests.
+package accesslog
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"apm/internal/auth"
+	"apm/internal/db/repository"
+	"apm/internal/models"
+	"apm/internal/tenant"
+)
+
+// writeTimeout bounds the time spent writing a batch of entries
+const writeTimeout = 10 * time.Second
+
+// Client is the client a request is made from
+type Client struct {
+	IPAddress string
+	UserAgent string
+}
+
+// clientKey is the context key of the client of a request
+type clientKey struct{}
+
+// WithClient returns a copy of the context carrying the client of the request
+func WithClient(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// ClientFromContext returns the client of the request of the context, if there is one
+func ClientFromContext(ctx context.Context) (Client, bool) {
+	client, ok := ctx.Value(clientKey{}).(Client)
+	return client, ok
+}
+
+// Options configure the batching of a Recorder
+type Options struct {
+	BufferSize    int           // Entries waiting to be written; further entries are dropped
+	BatchSize     int           // Entries written at once
+	FlushInterval time.Duration // Time after which waiting entries are written, however few
+}
+
+// Recorder writes entries to the access log in the background. A nil Recorder records nothing.
+type Recorder struct {
+	repo    repository.AccessLogRepository
+	options Options
+	logger  *log.Logger
+
+	entries chan models.AccessLog
+	done    chan struct{}
+	dropped atomic.Int64
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewRecorder creates a new recorder writing to the repository and starts writing entries
+// in the background until it is closed
+func NewRecorder(repo repository.AccessLogRepository, options Options, logger *log.Logger) *Recorder {
+	if options.BufferSize < 1 {
+		options.BufferSize = 1000
+	}
+	if options.BatchSize < 1 {
+		options.BatchSize = 100
+	}
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = 5 * time.Second
+	}
+
+	r := &Recorder{
+		repo:    repo,
+		options: options,
+		logger:  logger,
+		entries: make(chan models.AccessLog, options.BufferSize),
+		done:    make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// RecordLogin records a login attempt with an email. The user is the one the email belongs
+// to, if known; logins at the subdomain of an organization are recorded for it otherwise.
+func (r *Recorder) RecordLogin(ctx context.Context, email string, user *models.UserResponse, success bool) {
+	entry := newEntry(ctx, models.AccessEventLogin)
+	entry.Email = email
+	entry.Success = success
+	if user != nil {
+		entry.OrganizationID = user.OrganizationID
+		entry.UserID = user.ID
+		entry.Role = user.Role
+	} else if organizationID, err := tenant.OrganizationID(ctx); err == nil {
+		entry.OrganizationID = organizationID
+	}
+
+	r.record(entry)
+}
+
+// RecordRequest records a request of the authenticated user of the context; requests of
+// anyone else are not recorded
+func (r *Recorder) RecordRequest(ctx context.Context, method, path string, status int) {
+	user, ok := auth.UserFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	entry := newEntry(ctx, models.AccessEventRequest)
+	entry.OrganizationID = user.OrganizationID
+	entry.UserID = user.ID
+	entry.Role = user.Role
+	entry.Success = status < 400
+	entry.Method = method
+	entry.Path = path
+	entry.Status = status
+
+	r.record(entry)
+}
+
+// Close stops recording and writes the entries still waiting, unless the context ends first
+func (r *Recorder) Close(ctx context.Context) error {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.entries)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// record queues an entry for writing without waiting; when the buffer is full the entry is
+// dropped, as requests must not wait for the access log
+func (r *Recorder) record(entry models.AccessLog) {
+	if r == nil {
+		return
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return
+	}
+
+	select {
+	case r.entries <- entry:
+	default:
+		r.dropped.Add(1)
+	}
+}
+
+// run writes the queued entries in batches, once a batch is full or the flush interval has
+// passed, until the recorder is closed
+func (r *Recorder) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.options.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]models.AccessLog, 0, r.options.BatchSize)
+	for {
+		select {
+		case entry, ok := <-r.entries:
+			if !ok {
+				r.write(batch)
+				return
+			}
+			batch = append(batch, entry)
+			if len(batch) >= r.options.BatchSize {
+				r.write(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			r.write(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+// write writes a batch of entries; failing to write them loses them, but does not stop recording
+func (r *Recorder) write(batch []models.AccessLog) {
+	if dropped := r.dropped.Swap(0); dropped > 0 {
+		r.logger.Printf("Dropped %d access logs, the access log buffer was full", dropped)
+	}
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+
+	if err := r.repo.CreateBatch(ctx, batch); err != nil {
+		r.logger.Printf("Error writing %d access logs: %v", len(batch), err)
+	}
+}
+
+// newEntry creates an entry of an event, made by the client of the context now
+func newEntry(ctx context.Context, event models.AccessEvent) models.AccessLog {
+	client, _ := ClientFromContext(ctx)
+	return models.AccessLog{
+		Event:     event,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+		Timestamp: time.Now().UTC(),
+	}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"apm/internal/models"
+	"apm/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccessLogHandler handles HTTP requests for the access history
+type AccessLogHandler struct {
+	service services.AccessLogService
+}
+
+// NewAccessLogHandler creates a new access log handler
+func NewAccessLogHandler(service services.AccessLogService) *AccessLogHandler {
+	return &AccessLogHandler{
+		service: service,
+	}
+}
+
+// Register registers the routes for the access history
+func (h *AccessLogHandler) Register(router *gin.RouterGroup) {
+	router.GET("/access-logs", h.List)
+}
+
+// List handles the retrieval of the access history, narrowed by the user_id, ip_address,
+// from and to query parameters; times are given in RFC 3339 format
+func (h *AccessLogHandler) List(c *gin.Context) {
+	filter := models.AccessLogFilter{
+		UserID:    strings.TrimSpace(c.Query("user_id")),
+		IPAddress: strings.TrimSpace(c.Query("ip_address")),
+	}
+
+	var err error
+	if filter.From, err = queryTime(c, "from"); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid from time")
+		return
+	}
+	if filter.To, err = queryTime(c, "to"); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid to time")
+		return
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		RespondWithError(c, http.StatusBadRequest, errors.New("from must be before to"), "Invalid time range")
+		return
+	}
+
+	page, err := PageRequest(c)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid cursor")
+		return
+	}
+
+	resp, err := h.service.List(c.Request.Context(), filter, page)
+	if err != nil {
+		RespondWithServiceError(c, err, "Failed to retrieve access logs")
+		return
+	}
+
+	RespondWithPage(c, page, resp)
+}
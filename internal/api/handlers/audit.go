@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"apm/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler handles HTTP requests for the audit trail
+type AuditHandler struct {
+	service services.AuditService
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(service services.AuditService) *AuditHandler {
+	return &AuditHandler{
+		service: service,
+	}
+}
+
+// Register registers the routes for the audit trail
+func (h *AuditHandler) Register(router *gin.RouterGroup) {
+	router.GET("/history/:entityType/:id", h.ListByEntity)
+	router.GET("/users/:id/changes", h.ListByUser)
+}
+
+// ListByEntity handles the retrieval of the history of a record
+func (h *AuditHandler) ListByEntity(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+	page, err := PageRequest(c)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid cursor")
+		return
+	}
+
+	resp, err := h.service.ListByEntity(c.Request.Context(), c.Param("entityType"), id, page)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownEntityType) {
+			RespondWithError(c, http.StatusBadRequest, err, "Unknown entity type")
+			return
+		}
+		RespondWithServiceError(c, err, "Failed to retrieve history")
+		return
+	}
+
+	RespondWithPage(c, page, resp)
+}
+
+// ListByUser handles the retrieval of the changes made by a user
+func (h *AuditHandler) ListByUser(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+	page, err := PageRequest(c)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid cursor")
+		return
+	}
+
+	resp, err := h.service.ListByUser(c.Request.Context(), id, page)
+	if err != nil {
+		RespondWithServiceError(c, err, "Failed to retrieve changes")
+		return
+	}
+
+	RespondWithPage(c, page, resp)
+}
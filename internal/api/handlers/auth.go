@@ -1,79 +1,277 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
-	"time"
 
 	"apm/internal/models"
 	"apm/internal/services"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
 // AuthHandler handles authentication-related requests
 type AuthHandler struct {
-	userService services.UserService
-	jwtSecret   []byte
+	authService    services.AuthService
+	accountService services.AccountService
+	userService    services.UserService
+	mfaService     services.MFAService
 }
 
 // NewAuthHandler creates a new auth handler
-func NewAuthHandler(userService services.UserService, jwtSecret string) *AuthHandler {
+func NewAuthHandler(
+	authService services.AuthService,
+	accountService services.AccountService,
+	userService services.UserService,
+	mfaService services.MFAService,
+) *AuthHandler {
 	return &AuthHandler{
-		userService: userService,
-		jwtSecret:   []byte(jwtSecret),
+		authService:    authService,
+		accountService: accountService,
+		userService:    userService,
+		mfaService:     mfaService,
 	}
 }
 
-// Login handles user login
+// Login handles user login, verifying the credentials and starting a session
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req models.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
-		return
-	}
-
-	// TODO: Implement actual user authentication
-	// For now, we'll use a mock user for testing
-	if req.Email == "admin@example.com" && req.Password == "password123" {
-		// Create a mock user response
-		user := models.UserResponse{
-			ID:         "1",
-			Email:      req.Email,
-			FirstName:  "Admin",
-			LastName:   "User",
-			Role:       models.RoleOrganizationAdmin,
-			MFAEnabled: false,
-			CreatedAt:  time.Now(),
-			UpdatedAt:  time.Now(),
-		}
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+	if req.Email == "" || req.Password == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("email and password are required"), "Invalid request body")
+		return
+	}
 
-		// Generate JWT token
-		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-			"sub": user.ID,
-			"exp": time.Now().Add(time.Hour * 24).Unix(),
-		})
+	resp, err := h.authService.Login(c.Request.Context(), req)
+	if err != nil {
+		RespondWithServiceError(c, err, "Failed to log in")
+		return
+	}
 
-		tokenString, err := token.SignedString(h.jwtSecret)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
-			return
-		}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Refresh handles exchanging a refresh token for new tokens
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req models.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.RefreshToken == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("refresh_token is required"), "Invalid request body")
+		return
+	}
+
+	resp, err := h.authService.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		RespondWithServiceError(c, err, "Failed to refresh session")
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Logout handles ending the session of a refresh token
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req models.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.RefreshToken == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("refresh_token is required"), "Invalid request body")
+		return
+	}
+
+	if err := h.authService.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+		RespondWithServiceError(c, err, "Failed to log out")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ChangePassword handles the authenticated user changing their password, which ends all
+// their sessions
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	var req models.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	if err := h.userService.ChangePassword(c.Request.Context(), currentUserID(c), req); err != nil {
+		RespondWithServiceError(c, err, "Failed to change password")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Register handles signing up a new user and their organization
+func (h *AuthHandler) Register(c *gin.Context) {
+	var req models.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	user, err := h.accountService.Register(c.Request.Context(), req)
+	if err != nil {
+		h.respondWithAccountError(c, err, "Failed to register")
+		return
+	}
+
+	c.JSON(http.StatusCreated, user)
+}
 
-		c.JSON(http.StatusOK, models.LoginResponse{
-			User:        user,
-			AccessToken: tokenString,
-		})
+// ForgotPassword handles requesting a password reset link; it succeeds for unknown emails too
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req models.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
 		return
 	}
 
-	c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+	if err := h.accountService.RequestPasswordReset(c.Request.Context(), req); err != nil {
+		h.respondWithAccountError(c, err, "Failed to send password reset email")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
 }
 
-// RegisterRoutes registers the auth routes
-func (h *AuthHandler) RegisterRoutes(router *gin.RouterGroup) {
-	auth := router.Group("/auth")
+// ResetPassword handles setting a new password with the token of a reset link
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req models.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	if err := h.accountService.ResetPassword(c.Request.Context(), req); err != nil {
+		h.respondWithAccountError(c, err, "Failed to reset password")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteAccount handles the authenticated user asking to delete their account; the deletion
+// happens once they confirm it through the link emailed to them
+func (h *AuthHandler) DeleteAccount(c *gin.Context) {
+	var req models.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	if err := h.accountService.RequestAccountDeletion(c.Request.Context(), currentUserID(c), req); err != nil {
+		h.respondWithAccountError(c, err, "Failed to request account deletion")
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// ConfirmAccountDeletion handles deleting an account with the token of a deletion link
+func (h *AuthHandler) ConfirmAccountDeletion(c *gin.Context) {
+	var req models.ConfirmAccountDeletionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	if err := h.accountService.ConfirmAccountDeletion(c.Request.Context(), req); err != nil {
+		h.respondWithAccountError(c, err, "Failed to delete account")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondWithAccountError responds to a failed registration, password reset or account deletion
+func (h *AuthHandler) respondWithAccountError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrEmailTaken):
+		RespondWithError(c, http.StatusConflict, err, "Email is already registered")
+	case errors.Is(err, services.ErrInvalidEmailToken):
+		RespondWithError(c, http.StatusBadRequest, err, "Link is invalid or has expired")
+	case errors.Is(err, services.ErrLastAdmin):
+		RespondWithError(c, http.StatusConflict, err, "Another administrator must be appointed first")
+	case errors.Is(err, services.ErrSoleBusinessOwner):
+		RespondWithError(c, http.StatusConflict, err, "Another business owner must be assigned to your active software first")
+	default:
+		RespondWithServiceError(c, err, message)
+	}
+}
+
+// EnrollMFA handles starting MFA enrolment of the authenticated user, returning the secret
+// to add to an authenticator app
+func (h *AuthHandler) EnrollMFA(c *gin.Context) {
+	enrollment, err := h.mfaService.Enroll(c.Request.Context(), currentUserID(c))
+	if err != nil {
+		RespondWithServiceError(c, err, "Failed to start MFA enrolment")
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// ConfirmMFA handles enabling MFA with a code from the authenticator app, returning the
+// recovery codes
+func (h *AuthHandler) ConfirmMFA(c *gin.Context) {
+	var req models.ConfirmMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Code == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("code is required"), "Invalid request body")
+		return
+	}
+
+	resp, err := h.mfaService.Confirm(c.Request.Context(), currentUserID(c), req)
+	if err != nil {
+		RespondWithServiceError(c, err, "Failed to enable MFA")
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DisableMFA handles turning MFA off for the authenticated user
+func (h *AuthHandler) DisableMFA(c *gin.Context) {
+	var req models.DisableMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	if err := h.mfaService.Disable(c.Request.Context(), currentUserID(c), req); err != nil {
+		RespondWithServiceError(c, err, "Failed to disable MFA")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RegisterRoutes registers the auth routes; routes acting on the logged in user run the
+// authentication middleware first
+func (h *AuthHandler) RegisterRoutes(router *gin.RouterGroup, authenticate gin.HandlerFunc) {
+	auth := router.Group("/v1/auth")
 	{
 		auth.POST("/login", h.Login)
+		auth.POST("/refresh", h.Refresh)
+		auth.POST("/logout", h.Logout)
+		auth.PUT("/password", authenticate, h.ChangePassword)
+
+		auth.POST("/register", h.Register)
+		auth.POST("/forgot-password", h.ForgotPassword)
+		auth.POST("/reset-password", h.ResetPassword)
+		auth.POST("/delete-account", authenticate, h.DeleteAccount)
+		auth.POST("/delete-account/confirm", h.ConfirmAccountDeletion)
+
+		mfa := auth.Group("/mfa", authenticate)
+		{
+			mfa.POST("/enroll", h.EnrollMFA)
+			mfa.POST("/confirm", h.ConfirmMFA)
+			mfa.POST("/disable", h.DisableMFA)
+		}
 	}
+
+	// Kept for clients logging in at the path used before the API was versioned
+	router.POST("/auth/login", h.Login)
 }
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"apm/internal/models"
+	"apm/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClassificationHandler handles HTTP requests for software classification and suggestion review
+type ClassificationHandler struct {
+	service services.ClassificationService
+}
+
+// NewClassificationHandler creates a new classification handler
+func NewClassificationHandler(service services.ClassificationService) *ClassificationHandler {
+	return &ClassificationHandler{
+		service: service,
+	}
+}
+
+// Register registers the routes for classification
+func (h *ClassificationHandler) Register(router *gin.RouterGroup) {
+	software := router.Group("/software")
+	{
+		software.POST("/:id/classify", h.Classify)
+		software.GET("/:id/suggestions", h.ListBySoftware)
+	}
+
+	suggestions := router.Group("/classification-suggestions")
+	{
+		suggestions.GET("", h.List)
+		suggestions.POST("/:id/accept", h.Accept)
+		suggestions.POST("/:id/reject", h.Reject)
+	}
+}
+
+// Classify handles running the classifiers on a software record
+func (h *ClassificationHandler) Classify(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	resp, err := h.service.Classify(c.Request.Context(), id)
+	if err != nil {
+		RespondWithServiceError(c, err, "Failed to classify software")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  resp,
+		"count": len(resp),
+	})
+}
+
+// ListBySoftware handles the retrieval of the classification suggestions of a software record
+func (h *ClassificationHandler) ListBySoftware(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	resp, err := h.service.ListBySoftware(c.Request.Context(), id)
+	if err != nil {
+		RespondWithServiceError(c, err, "Failed to retrieve classification suggestions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  resp,
+		"count": len(resp),
+	})
+}
+
+// List handles the retrieval of classification suggestions by review status, pending by default
+func (h *ClassificationHandler) List(c *gin.Context) {
+	page, err := PageRequest(c)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid cursor")
+		return
+	}
+	status := models.SuggestionStatus(QueryParam(c, "status", string(models.SuggestionPending)))
+
+	resp, err := h.service.ListByStatus(c.Request.Context(), status, page)
+	if err != nil {
+		RespondWithServiceError(c, err, "Failed to retrieve classification suggestions")
+		return
+	}
+
+	RespondWithPage(c, page, resp)
+}
+
+// Accept handles accepting a classification suggestion
+func (h *ClassificationHandler) Accept(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	if err := h.service.Accept(c.Request.Context(), id, currentUserID(c)); err != nil {
+		RespondWithServiceError(c, err, "Failed to accept classification suggestion")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Reject handles rejecting a classification suggestion
+func (h *ClassificationHandler) Reject(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	if err := h.service.Reject(c.Request.Context(), id, currentUserID(c)); err != nil {
+		RespondWithServiceError(c, err, "Failed to reject classification suggestion")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
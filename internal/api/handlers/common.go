@@ -1,28 +1,121 @@
 package handlers
 
 import (
+	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"apm/internal/apperr"
+	"apm/internal/auth"
+	"apm/internal/pagination"
 
 	"github.com/gin-gonic/gin"
 )
 
 // ErrorResponse represents a standard error response
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message,omitempty"`
-	Code    int    `json:"code"`
+	Error   string `json:"error"`             // What went wrong; in production only errors of a kind are detailed
+	Message string `json:"message,omitempty"` // What could not be done, for people
+	Code    int    `json:"code"`              // HTTP status of the response
+	Kind    string `json:"kind"`              // Kind of the error, for programs
+}
+
+// kindStatuses maps the kinds of errors onto the statuses of their responses; other errors
+// are failures of the server
+var kindStatuses = map[apperr.Kind]int{
+	apperr.KindNotFound:     http.StatusNotFound,
+	apperr.KindConflict:     http.StatusConflict,
+	apperr.KindValidation:   http.StatusBadRequest,
+	apperr.KindUnauthorized: http.StatusUnauthorized,
+	apperr.KindForbidden:    http.StatusForbidden,
+	apperr.KindRateLimited:  http.StatusTooManyRequests,
+}
+
+// statusKinds maps statuses onto the kinds of errors of their responses; other statuses are
+// failures of the server, or invalid requests if the client is at fault
+var statusKinds = map[int]apperr.Kind{
+	http.StatusNotFound:            apperr.KindNotFound,
+	http.StatusConflict:            apperr.KindConflict,
+	http.StatusBadRequest:          apperr.KindValidation,
+	http.StatusUnauthorized:        apperr.KindUnauthorized,
+	http.StatusForbidden:           apperr.KindForbidden,
+	http.StatusUnprocessableEntity: apperr.KindValidation,
+	http.StatusTooManyRequests:     apperr.KindRateLimited,
 }
 
-// RespondWithError sends a JSON error response
+// RespondWithError sends a JSON error response with a status. Outside production the error is
+// sent as it is. In production, where gin runs in release mode, errors may reveal the inner
+// workings of the server, so only the message of an error of a kind is sent, and otherwise the
+// text of the status.
 func RespondWithError(c *gin.Context, status int, err error, message string) {
+	kind, ok := statusKinds[status]
+	if !ok {
+		kind = apperr.KindInternal
+		if status < http.StatusInternalServerError {
+			kind = apperr.KindValidation
+		}
+	}
+
+	detail := err.Error()
+	if gin.Mode() == gin.ReleaseMode {
+		if detail = apperr.MessageOf(err); detail == "" {
+			detail = http.StatusText(status)
+		}
+	}
+
 	c.JSON(status, ErrorResponse{
-		Error:   err.Error(),
+		Error:   detail,
 		Message: message,
 		Code:    status,
+		Kind:    string(kind),
 	})
 }
 
+// RespondWithServiceError sends the error response of a service call, with the status of the
+// kind of the error. Errors of no kind are failures of the server, answered with the message.
+func RespondWithServiceError(c *gin.Context, err error, message string) {
+	status, ok := kindStatuses[apperr.KindOf(err)]
+	if !ok {
+		RespondWithError(c, http.StatusInternalServerError, err, message)
+		return
+	}
+	RespondWithError(c, status, err, sentence(apperr.MessageOf(err)))
+}
+
+// respondWithGetError sends the error response of a record that could not be retrieved, with
+// the message for a record that does not exist or the message for a failure
+func respondWithGetError(c *gin.Context, err error, notFoundMessage, failureMessage string) {
+	if apperr.KindOf(err) == apperr.KindNotFound {
+		RespondWithError(c, http.StatusNotFound, err, notFoundMessage)
+		return
+	}
+	RespondWithServiceError(c, err, failureMessage)
+}
+
+// sentence capitalizes the first letter of an error message, to send it as a message for people
+func sentence(message string) string {
+	if message == "" {
+		return message
+	}
+	first, size := utf8.DecodeRuneInString(message)
+	return string(unicode.ToUpper(first)) + message[size:]
+}
+
+// CurrentUser returns the authenticated user of the request
+func CurrentUser(c *gin.Context) (auth.User, bool) {
+	return auth.UserFromContext(c.Request.Context())
+}
+
+// currentUserID returns the ID of the authenticated user of the request, or an empty string
+func currentUserID(c *gin.Context) string {
+	user, _ := CurrentUser(c)
+	return user.ID
+}
+
 // ExtractIDParam extracts an ID parameter from the request URL
 func ExtractIDParam(c *gin.Context) string {
 	return strings.TrimSpace(c.Param("id"))
@@ -37,35 +130,78 @@ func QueryParam(c *gin.Context, key string, defaultValue string) string {
 	return value
 }
 
-// SetPagination prepares pagination parameters from the request
-func SetPagination(c *gin.Context) (limit, offset int) {
-	limitStr := c.DefaultQuery("limit", "10")
-	offsetStr := c.DefaultQuery("offset", "0")
+// includeArchived reports whether the request asks for archived records to be included, with
+// the include_archived query parameter
+func includeArchived(c *gin.Context) bool {
+	include, _ := strconv.ParseBool(c.Query("include_archived"))
+	return include
+}
+
+// queryTime parses an optional query parameter holding an RFC 3339 time
+func queryTime(c *gin.Context, key string) (*time.Time, error) {
+	value := strings.TrimSpace(c.Query(key))
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an RFC 3339 time: %w", key, err)
+	}
+	return &parsed, nil
+}
 
-	// Parse values with defaults
-	limit = 10
-	offset = 0
+// OffsetPageRequest prepares the request for a page of a list paged by offset only, from the
+// limit and offset query parameters
+func OffsetPageRequest(c *gin.Context) pagination.Request {
+	page := pagination.Request{Limit: pagination.DefaultLimit}
 
-	// Try to parse limit
-	if val, err := strconv.Atoi(limitStr); err == nil {
-		limit = val
+	if val, err := strconv.Atoi(c.Query("limit")); err == nil {
+		page.Limit = val
+	}
+	if val, err := strconv.Atoi(c.Query("offset")); err == nil {
+		page.Offset = val
 	}
 
-	// Try to parse offset
-	if val, err := strconv.Atoi(offsetStr); err == nil {
-		offset = val
+	return page.Normalized()
+}
+
+// PageRequest prepares the request for a page of a list from the limit, offset, cursor and
+// include_total query parameters. The cursor is the next_cursor or prev_cursor of an earlier
+// page and takes the place of the offset.
+func PageRequest(c *gin.Context) (pagination.Request, error) {
+	page := OffsetPageRequest(c)
+
+	if token := strings.TrimSpace(c.Query("cursor")); token != "" {
+		cursor, err := pagination.DecodeCursor(token)
+		if err != nil {
+			return pagination.Request{}, err
+		}
+		page.Cursor = cursor
 	}
+	page.IncludeTotal, _ = strconv.ParseBool(c.Query("include_total"))
+
+	return page.Normalized(), nil
+}
 
-	// Ensure reasonable defaults
-	if limit < 1 {
-		limit = 10
+// RespondWithPage sends a page of a list requested with PageRequest. The cursors of the pages
+// before and after it are only sent when there are such pages, and the total when requested.
+func RespondWithPage[T any](c *gin.Context, req pagination.Request, page pagination.Page[T]) {
+	body := gin.H{
+		"data":   page.Items,
+		"limit":  req.Limit,
+		"offset": req.Offset,
+		"count":  len(page.Items),
+	}
+	if page.NextCursor != "" {
+		body["next_cursor"] = page.NextCursor
 	}
-	if limit > 100 {
-		limit = 100
+	if page.PrevCursor != "" {
+		body["prev_cursor"] = page.PrevCursor
 	}
-	if offset < 0 {
-		offset = 0
+	if page.Total != nil {
+		body["total"] = *page.Total
 	}
 
-	return limit, offset
+	c.JSON(http.StatusOK, body)
 }
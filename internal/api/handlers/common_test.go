@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"apm/internal/apperr"
+	"apm/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRespondWithServiceError(t *testing.T) {
+	tests := []struct {
+		name   string
+		mode   string
+		err    error
+		status int
+		kind   string
+		detail string
+	}{
+		{"not found", gin.TestMode, apperr.NotFound("software not found"), http.StatusNotFound, "not_found", "software not found"},
+		{"conflict", gin.TestMode, apperr.Conflict("email taken"), http.StatusConflict, "conflict", "email taken"},
+		{"unauthorized", gin.TestMode, services.ErrInvalidMFACode, http.StatusUnauthorized, "unauthorized", "invalid authentication code"},
+		{"rate limited", gin.TestMode, apperr.RateLimited("too many attempts"), http.StatusTooManyRequests, "rate_limited", "too many attempts"},
+		{"internal", gin.TestMode, errors.New("connection refused"), http.StatusInternalServerError, "internal", "connection refused"},
+		{"internal in production", gin.ReleaseMode, errors.New("connection refused"), http.StatusInternalServerError, "internal", "Internal Server Error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(tt.mode)
+			defer gin.SetMode(gin.TestMode)
+
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			RespondWithServiceError(c, tt.err, "Failed")
+
+			var resp ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if rec.Code != tt.status || resp.Code != tt.status {
+				t.Errorf("status = %d, code = %d, want %d", rec.Code, resp.Code, tt.status)
+			}
+			if resp.Kind != tt.kind {
+				t.Errorf("kind = %q, want %q", resp.Kind, tt.kind)
+			}
+			if resp.Error != tt.detail {
+				t.Errorf("error = %q, want %q", resp.Error, tt.detail)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"apm/internal/auth"
 	"apm/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -10,6 +11,10 @@ import (
 type Factory struct {
 	// Services
 	userService                 services.UserService
+	accountService              services.AccountService
+	auditService                services.AuditService
+	accessLogService            services.AccessLogService
+	searchService               services.SearchService
 	userGroupService            services.UserGroupService
 	stakeholderService          services.StakeholderService
 	entityService               services.EntityService
@@ -23,8 +28,16 @@ type Factory struct {
 	mediaService                services.MediaService
 	productDocumentationService services.ProductDocumentationService
 	logService                  services.LogService
+	importService               services.ImportService
+	importMappingProfileService services.ImportMappingProfileService
+	classificationService       services.ClassificationService
+	organizationService         services.OrganizationService
 
 	// Handlers
+	userHandler                 *UserHandler
+	auditHandler                *AuditHandler
+	accessLogHandler            *AccessLogHandler
+	searchHandler               *SearchHandler
 	userGroupHandler            *UserGroupHandler
 	stakeholderHandler          *StakeholderHandler
 	entityHandler               *EntityHandler
@@ -38,6 +51,10 @@ type Factory struct {
 	mediaHandler                *MediaHandler
 	productDocumentationHandler *ProductDocumentationHandler
 	logHandler                  *LogHandler
+	importHandler               *ImportHandler
+	importMappingProfileHandler *ImportMappingProfileHandler
+	classificationHandler       *ClassificationHandler
+	organizationHandler         *OrganizationHandler
 }
 
 // NewFactory creates a new handler factory
@@ -56,6 +73,14 @@ func NewFactory(
 	mediaService services.MediaService,
 	productDocumentationService services.ProductDocumentationService,
 	logService services.LogService,
+	importService services.ImportService,
+	importMappingProfileService services.ImportMappingProfileService,
+	classificationService services.ClassificationService,
+	organizationService services.OrganizationService,
+	accountService services.AccountService,
+	auditService services.AuditService,
+	accessLogService services.AccessLogService,
+	searchService services.SearchService,
 ) *Factory {
 	f := &Factory{
 		userService:                 userService,
@@ -72,6 +97,14 @@ func NewFactory(
 		mediaService:                mediaService,
 		productDocumentationService: productDocumentationService,
 		logService:                  logService,
+		importService:               importService,
+		importMappingProfileService: importMappingProfileService,
+		classificationService:       classificationService,
+		organizationService:         organizationService,
+		accountService:              accountService,
+		auditService:                auditService,
+		accessLogService:            accessLogService,
+		searchService:               searchService,
 	}
 
 	f.initHandlers()
@@ -80,6 +113,7 @@ func NewFactory(
 
 // initHandlers initializes all handler instances
 func (f *Factory) initHandlers() {
+	f.userHandler = NewUserHandler(f.userService, f.accountService, f.userGroupService)
 	f.userGroupHandler = NewUserGroupHandler(f.userGroupService)
 	f.stakeholderHandler = NewStakeholderHandler(f.stakeholderService)
 	f.entityHandler = NewEntityHandler(f.entityService)
@@ -93,13 +127,23 @@ func (f *Factory) initHandlers() {
 	f.mediaHandler = NewMediaHandler(f.mediaService)
 	f.productDocumentationHandler = NewProductDocumentationHandler(f.productDocumentationService)
 	f.logHandler = NewLogHandler(f.logService)
+	f.importHandler = NewImportHandler(f.importService)
+	f.importMappingProfileHandler = NewImportMappingProfileHandler(f.importMappingProfileService)
+	f.classificationHandler = NewClassificationHandler(f.classificationService)
+	f.organizationHandler = NewOrganizationHandler(f.organizationService)
+	f.auditHandler = NewAuditHandler(f.auditService)
+	f.accessLogHandler = NewAccessLogHandler(f.accessLogService)
+	f.searchHandler = NewSearchHandler(f.searchService)
 }
 
-// RegisterRoutes registers all API routes
-func (f *Factory) RegisterRoutes(router *gin.RouterGroup) {
-	apiV1 := router.Group("/v1")
+// RegisterRoutes registers all API routes, running the middleware before each of them.
+// Every route is then authorized against the role of the authenticated user.
+func (f *Factory) RegisterRoutes(router *gin.RouterGroup, middleware ...gin.HandlerFunc) {
+	apiV1 := router.Group("/v1", middleware...)
+	apiV1.Use(Authorize(auth.DefaultPolicy, apiV1.BasePath()))
 
 	// Register routes for each handler
+	f.userHandler.Register(apiV1)
 	f.userGroupHandler.Register(apiV1)
 	f.stakeholderHandler.Register(apiV1)
 	f.entityHandler.Register(apiV1)
@@ -113,4 +157,11 @@ func (f *Factory) RegisterRoutes(router *gin.RouterGroup) {
 	f.mediaHandler.Register(apiV1)
 	f.productDocumentationHandler.Register(apiV1)
 	f.logHandler.Register(apiV1)
+	f.importHandler.Register(apiV1)
+	f.importMappingProfileHandler.Register(apiV1)
+	f.classificationHandler.Register(apiV1)
+	f.organizationHandler.Register(apiV1)
+	f.auditHandler.Register(apiV1)
+	f.accessLogHandler.Register(apiV1)
+	f.searchHandler.Register(apiV1)
 }
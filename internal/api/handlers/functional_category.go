@@ -44,7 +44,7 @@ func (h *FunctionalCategoryHandler) Create(c *gin.Context) {
 
 	resp, err := h.service.Create(c.Request.Context(), req)
 	if err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to create functional category")
+		RespondWithServiceError(c, err, "Failed to create functional category")
 		return
 	}
 
@@ -61,7 +61,7 @@ func (h *FunctionalCategoryHandler) GetByID(c *gin.Context) {
 
 	resp, err := h.service.GetByID(c.Request.Context(), id)
 	if err != nil {
-		RespondWithError(c, http.StatusNotFound, err, "Functional category not found")
+		respondWithGetError(c, err, "Functional category not found", "Failed to retrieve functional category")
 		return
 	}
 
@@ -70,20 +70,19 @@ func (h *FunctionalCategoryHandler) GetByID(c *gin.Context) {
 
 // List handles the retrieval of a list of functional categories
 func (h *FunctionalCategoryHandler) List(c *gin.Context) {
-	limit, offset := SetPagination(c)
+	page, err := PageRequest(c)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid cursor")
+		return
+	}
 
-	resp, err := h.service.List(c.Request.Context(), limit, offset)
+	resp, err := h.service.List(c.Request.Context(), page)
 	if err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to retrieve functional categories")
+		RespondWithServiceError(c, err, "Failed to retrieve functional categories")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":   resp,
-		"limit":  limit,
-		"offset": offset,
-		"count":  len(resp),
-	})
+	RespondWithPage(c, page, resp)
 }
 
 // Update handles the update of a functional category
@@ -101,7 +100,7 @@ func (h *FunctionalCategoryHandler) Update(c *gin.Context) {
 	}
 
 	if err := h.service.Update(c.Request.Context(), id, req); err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to update functional category")
+		RespondWithServiceError(c, err, "Failed to update functional category")
 		return
 	}
 
@@ -117,7 +116,7 @@ func (h *FunctionalCategoryHandler) Delete(c *gin.Context) {
 	}
 
 	if err := h.service.Delete(c.Request.Context(), id); err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to delete functional category")
+		RespondWithServiceError(c, err, "Failed to delete functional category")
 		return
 	}
 
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"apm/internal/models"
+	"apm/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportFileSize is the largest CSV file accepted for an import (10 MB)
+const maxImportFileSize = 10 << 20
+
+// ImportHandler handles HTTP requests for bulk imports
+type ImportHandler struct {
+	service services.ImportService
+}
+
+// NewImportHandler creates a new import handler
+func NewImportHandler(service services.ImportService) *ImportHandler {
+	return &ImportHandler{
+		service: service,
+	}
+}
+
+// Register registers the routes for imports
+func (h *ImportHandler) Register(router *gin.RouterGroup) {
+	imports := router.Group("/imports")
+	{
+		imports.POST("", h.Create)
+		imports.GET("", h.List)
+		imports.POST("/preview", h.Preview)
+		imports.GET("/:id", h.GetByID)
+		imports.GET("/:id/file", h.DownloadFile)
+		imports.POST("/:id/rerun", h.Rerun)
+	}
+}
+
+// Create handles the upload of a CSV file and imports the software it contains
+func (h *ImportHandler) Create(c *gin.Context) {
+	fileName, content, ok := h.readUploadedFile(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.CreateJob(c.Request.Context(), models.CreateImportJobRequest{
+		FileName:         fileName,
+		FileContent:      content,
+		UploadedBy:       currentUserID(c),
+		MappingProfileID: c.PostForm("mapping_profile_id"),
+		DuplicateAction:  models.DuplicateAction(c.PostForm("duplicate_action")),
+	})
+	if err != nil {
+		RespondWithServiceError(c, err, "Failed to import software")
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// Preview handles a dry run of an uploaded CSV file, returning its first mapped rows
+func (h *ImportHandler) Preview(c *gin.Context) {
+	_, content, ok := h.readUploadedFile(c)
+	if !ok {
+		return
+	}
+
+	limit := 10
+	if val, err := strconv.Atoi(c.DefaultQuery("limit", "10")); err == nil {
+		limit = val
+	}
+
+	resp, err := h.service.PreviewImport(c.Request.Context(), bytes.NewReader(content), c.PostForm("mapping_profile_id"), limit)
+	if err != nil {
+		RespondWithError(c, http.StatusUnprocessableEntity, err, "Failed to preview import")
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetByID handles the retrieval of an import job and its report by ID
+func (h *ImportHandler) GetByID(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	resp, err := h.service.GetJob(c.Request.Context(), id)
+	if err != nil {
+		respondWithGetError(c, err, "Import not found", "Failed to retrieve import")
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// List handles the retrieval of a list of import jobs
+func (h *ImportHandler) List(c *gin.Context) {
+	page, err := PageRequest(c)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid cursor")
+		return
+	}
+
+	resp, err := h.service.ListJobs(c.Request.Context(), page)
+	if err != nil {
+		RespondWithServiceError(c, err, "Failed to retrieve import list")
+		return
+	}
+
+	RespondWithPage(c, page, resp)
+}
+
+// DownloadFile handles the download of the original file of an import job
+func (h *ImportHandler) DownloadFile(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	file, err := h.service.GetJobFile(c.Request.Context(), id)
+	if err != nil {
+		respondWithGetError(c, err, "Import not found", "Failed to retrieve import")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.FileName))
+	c.Data(http.StatusOK, "text/csv", file.Content)
+}
+
+// Rerun handles importing the file of an earlier import job again
+func (h *ImportHandler) Rerun(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	resp, err := h.service.RerunJob(c.Request.Context(), id, currentUserID(c), models.DuplicateAction(c.Query("duplicate_action")))
+	if err != nil {
+		RespondWithServiceError(c, err, "Failed to re-run import")
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// readUploadedFile reads the CSV file uploaded in the 'file' form field, responding with an error if it cannot
+func (h *ImportHandler) readUploadedFile(c *gin.Context) (string, []byte, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "A CSV file must be uploaded in the 'file' field")
+		return "", nil, false
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Failed to read uploaded file")
+		return "", nil, false
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Failed to read uploaded file")
+		return "", nil, false
+	}
+
+	return fileHeader.Filename, content, true
+}
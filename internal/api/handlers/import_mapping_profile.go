@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"apm/internal/models"
+	"apm/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImportMappingProfileHandler handles HTTP requests for import mapping profiles
+type ImportMappingProfileHandler struct {
+	service services.ImportMappingProfileService
+}
+
+// NewImportMappingProfileHandler creates a new import mapping profile handler
+func NewImportMappingProfileHandler(service services.ImportMappingProfileService) *ImportMappingProfileHandler {
+	return &ImportMappingProfileHandler{
+		service: service,
+	}
+}
+
+// Register registers the routes for import mapping profiles
+func (h *ImportMappingProfileHandler) Register(router *gin.RouterGroup) {
+	profiles := router.Group("/import-profiles")
+	{
+		profiles.POST("", h.Create)
+		profiles.GET("", h.List)
+		profiles.GET("/:id", h.GetByID)
+		profiles.PUT("/:id", h.Update)
+		profiles.DELETE("/:id", h.Delete)
+	}
+}
+
+// Create handles the creation of a new import mapping profile
+func (h *ImportMappingProfileHandler) Create(c *gin.Context) {
+	var req models.CreateImportMappingProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	resp, err := h.service.Create(c.Request.Context(), req)
+	if err != nil {
+		RespondWithServiceError(c, err, "Failed to create import mapping profile")
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// GetByID handles the retrieval of an import mapping profile by ID
+func (h *ImportMappingProfileHandler) GetByID(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	resp, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		respondWithGetError(c, err, "Import mapping profile not found", "Failed to retrieve import mapping profile")
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// List handles the retrieval of a list of import mapping profiles
+func (h *ImportMappingProfileHandler) List(c *gin.Context) {
+	page, err := PageRequest(c)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid cursor")
+		return
+	}
+
+	resp, err := h.service.List(c.Request.Context(), page)
+	if err != nil {
+		RespondWithServiceError(c, err, "Failed to retrieve import mapping profiles")
+		return
+	}
+
+	RespondWithPage(c, page, resp)
+}
+
+// Update handles the update of an import mapping profile
+func (h *ImportMappingProfileHandler) Update(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	var req models.UpdateImportMappingProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	if err := h.service.Update(c.Request.Context(), id, req); err != nil {
+		RespondWithServiceError(c, err, "Failed to update import mapping profile")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Delete handles the deletion of an import mapping profile
+func (h *ImportMappingProfileHandler) Delete(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		RespondWithServiceError(c, err, "Failed to delete import mapping profile")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
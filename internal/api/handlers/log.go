@@ -43,7 +43,7 @@ func (h *LogHandler) Create(c *gin.Context) {
 
 	resp, err := h.service.Create(c.Request.Context(), req)
 	if err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to create log")
+		RespondWithServiceError(c, err, "Failed to create log")
 		return
 	}
 
@@ -60,7 +60,7 @@ func (h *LogHandler) GetByID(c *gin.Context) {
 
 	resp, err := h.service.GetByID(c.Request.Context(), id)
 	if err != nil {
-		RespondWithError(c, http.StatusNotFound, err, "Log not found")
+		respondWithGetError(c, err, "Log not found", "Failed to retrieve log")
 		return
 	}
 
@@ -69,20 +69,19 @@ func (h *LogHandler) GetByID(c *gin.Context) {
 
 // List handles the retrieval of a list of logs
 func (h *LogHandler) List(c *gin.Context) {
-	limit, offset := SetPagination(c)
+	page, err := PageRequest(c)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid cursor")
+		return
+	}
 
-	resp, err := h.service.List(c.Request.Context(), limit, offset)
+	resp, err := h.service.List(c.Request.Context(), page)
 	if err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to retrieve logs")
+		RespondWithServiceError(c, err, "Failed to retrieve logs")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":   resp,
-		"limit":  limit,
-		"offset": offset,
-		"count":  len(resp),
-	})
+	RespondWithPage(c, page, resp)
 }
 
 // Delete handles the deletion of a log
@@ -94,7 +93,7 @@ func (h *LogHandler) Delete(c *gin.Context) {
 	}
 
 	if err := h.service.Delete(c.Request.Context(), id); err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to delete log")
+		RespondWithServiceError(c, err, "Failed to delete log")
 		return
 	}
 
@@ -44,7 +44,7 @@ func (h *NewsArticleHandler) Create(c *gin.Context) {
 
 	resp, err := h.service.Create(c.Request.Context(), req)
 	if err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to create news article")
+		RespondWithServiceError(c, err, "Failed to create news article")
 		return
 	}
 
@@ -61,7 +61,7 @@ func (h *NewsArticleHandler) GetByID(c *gin.Context) {
 
 	resp, err := h.service.GetByID(c.Request.Context(), id)
 	if err != nil {
-		RespondWithError(c, http.StatusNotFound, err, "News article not found")
+		respondWithGetError(c, err, "News article not found", "Failed to retrieve news article")
 		return
 	}
 
@@ -70,20 +70,19 @@ func (h *NewsArticleHandler) GetByID(c *gin.Context) {
 
 // List handles the retrieval of a list of news articles
 func (h *NewsArticleHandler) List(c *gin.Context) {
-	limit, offset := SetPagination(c)
+	page, err := PageRequest(c)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid cursor")
+		return
+	}
 
-	resp, err := h.service.List(c.Request.Context(), limit, offset)
+	resp, err := h.service.List(c.Request.Context(), page)
 	if err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to retrieve news articles")
+		RespondWithServiceError(c, err, "Failed to retrieve news articles")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":   resp,
-		"limit":  limit,
-		"offset": offset,
-		"count":  len(resp),
-	})
+	RespondWithPage(c, page, resp)
 }
 
 // Update handles the update of a news article
@@ -101,7 +100,7 @@ func (h *NewsArticleHandler) Update(c *gin.Context) {
 	}
 
 	if err := h.service.Update(c.Request.Context(), id, req); err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to update news article")
+		RespondWithServiceError(c, err, "Failed to update news article")
 		return
 	}
 
@@ -117,7 +116,7 @@ func (h *NewsArticleHandler) Delete(c *gin.Context) {
 	}
 
 	if err := h.service.Delete(c.Request.Context(), id); err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to delete news article")
+		RespondWithServiceError(c, err, "Failed to delete news article")
 		return
 	}
 
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"apm/internal/models"
+	"apm/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OrganizationHandler handles HTTP requests for organizations
+type OrganizationHandler struct {
+	service services.OrganizationService
+}
+
+// NewOrganizationHandler creates a new organization handler
+func NewOrganizationHandler(service services.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{
+		service: service,
+	}
+}
+
+// Register registers the routes for organizations
+func (h *OrganizationHandler) Register(router *gin.RouterGroup) {
+	organizations := router.Group("/organizations")
+	{
+		organizations.POST("", h.Create)
+		organizations.GET("", h.List)
+		organizations.GET("/:id", h.GetByID)
+		organizations.PUT("/:id", h.Update)
+		organizations.DELETE("/:id", h.Delete)
+	}
+}
+
+// Create handles the creation of a new organization
+func (h *OrganizationHandler) Create(c *gin.Context) {
+	var req models.CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	resp, err := h.service.Create(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, services.ErrSubdomainTaken) {
+			RespondWithError(c, http.StatusConflict, err, "Subdomain is already in use")
+			return
+		}
+		RespondWithServiceError(c, err, "Failed to create organization")
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// GetByID handles the retrieval of an organization by ID
+func (h *OrganizationHandler) GetByID(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	resp, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		respondWithGetError(c, err, "Organization not found", "Failed to retrieve organization")
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// List handles the retrieval of a list of organizations
+func (h *OrganizationHandler) List(c *gin.Context) {
+	page, err := PageRequest(c)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid cursor")
+		return
+	}
+
+	resp, err := h.service.List(c.Request.Context(), page)
+	if err != nil {
+		RespondWithServiceError(c, err, "Failed to retrieve organizations")
+		return
+	}
+
+	RespondWithPage(c, page, resp)
+}
+
+// Update handles the update of an organization
+func (h *OrganizationHandler) Update(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	var req models.UpdateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	if err := h.service.Update(c.Request.Context(), id, req); err != nil {
+		RespondWithServiceError(c, err, "Failed to update organization")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Delete handles the deletion of an organization and all of its data
+func (h *OrganizationHandler) Delete(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		if errors.Is(err, services.ErrDeleteOwnOrganization) {
+			RespondWithError(c, http.StatusConflict, err, "You cannot delete your own organization")
+			return
+		}
+		RespondWithServiceError(c, err, "Failed to delete organization")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"apm/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// routePermission is the permission a route requires
+type routePermission struct {
+	resource auth.Resource
+	action   auth.Action
+}
+
+// routePermissions declares the permissions of routes that are not plain CRUD routes, by method
+// and path relative to the API version. Other routes act on the resource named by the first
+// segment of their path, with the action following from their method.
+var routePermissions = map[string]routePermission{
+	"POST /imports/preview":                        {auth.ResourceImports, auth.ActionRead},
+	"POST /imports/:id/rerun":                      {auth.ResourceImports, auth.ActionCreate},
+	"POST /software/:id/classify":                  {auth.ResourceClassificationSuggestions, auth.ActionCreate},
+	"GET /software/:id/suggestions":                {auth.ResourceClassificationSuggestions, auth.ActionRead},
+	"POST /software/:id/versions/:version/restore": {auth.ResourceSoftware, auth.ActionUpdate},
+	"POST /software/:id/undelete":                  {auth.ResourceSoftware, auth.ActionUpdate},
+	"POST /software/:id/restore":                   {auth.ResourceSoftware, auth.ActionUpdate},
+	"POST /classification-suggestions/:id/accept":  {auth.ResourceClassificationSuggestions, auth.ActionUpdate},
+	"POST /classification-suggestions/:id/reject":  {auth.ResourceClassificationSuggestions, auth.ActionUpdate},
+	"POST /users/:id/deactivate":                   {auth.ResourceUsers, auth.ActionUpdate},
+	"POST /users/:id/activate":                     {auth.ResourceUsers, auth.ActionUpdate},
+	"GET /users/:id/groups":                        {auth.ResourceUserGroups, auth.ActionRead},
+	"GET /users/:id/applications":                  {auth.ResourceStakeholders, auth.ActionRead},
+	"GET /users/:id/changes":                       {auth.ResourceHistory, auth.ActionRead},
+	"GET /software/:id/stakeholders":               {auth.ResourceStakeholders, auth.ActionRead},
+	"POST /software/:id/stakeholders":              {auth.ResourceStakeholders, auth.ActionCreate},
+	"POST /user-groups/:id/members":                {auth.ResourceUserGroups, auth.ActionUpdate},
+	"DELETE /user-groups/:id/members/:userId":      {auth.ResourceUserGroups, auth.ActionUpdate},
+}
+
+// methodActions maps HTTP methods onto the action they perform on a resource
+var methodActions = map[string]auth.Action{
+	http.MethodGet:    auth.ActionRead,
+	http.MethodHead:   auth.ActionRead,
+	http.MethodPost:   auth.ActionCreate,
+	http.MethodPut:    auth.ActionUpdate,
+	http.MethodPatch:  auth.ActionUpdate,
+	http.MethodDelete: auth.ActionDelete,
+}
+
+// permissionFor returns the permission required by a route, given its method and path
+// relative to the API version
+func permissionFor(method, path string) routePermission {
+	if permission, ok := routePermissions[method+" "+path]; ok {
+		return permission
+	}
+
+	segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return routePermission{
+		resource: auth.Resource(segment),
+		action:   methodActions[method],
+	}
+}
+
+// Authorize creates a middleware that lets a request through only if the role of the
+// authenticated user may perform the action of its route under the policy. Routes are
+// matched relative to basePath. Requests without an authenticated user are unauthorized.
+func Authorize(policy auth.Policy, basePath string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		permission := permissionFor(c.Request.Method, strings.TrimPrefix(c.FullPath(), basePath))
+
+		user, ok := CurrentUser(c)
+		if !ok {
+			RespondWithError(c, http.StatusUnauthorized, auth.ErrNotAuthenticated, "Authentication required")
+			c.Abort()
+			return
+		}
+		if !policy.Allows(user.Role, permission.resource, permission.action) {
+			RespondWithError(c, http.StatusForbidden, auth.ErrForbidden, "You do not have permission to perform this action")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
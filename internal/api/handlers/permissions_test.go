@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"apm/internal/auth"
+	"apm/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPermissionFor(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   routePermission
+	}{
+		// Plain CRUD routes follow from their first segment and method
+		{http.MethodGet, "/software", routePermission{auth.ResourceSoftware, auth.ActionRead}},
+		{http.MethodPost, "/software", routePermission{auth.ResourceSoftware, auth.ActionCreate}},
+		{http.MethodPut, "/software/:id", routePermission{auth.ResourceSoftware, auth.ActionUpdate}},
+		{http.MethodDelete, "/software/:id", routePermission{auth.ResourceSoftware, auth.ActionDelete}},
+		{http.MethodGet, "/software/:id/versions", routePermission{auth.ResourceSoftware, auth.ActionRead}},
+		{http.MethodPatch, "/users/:id", routePermission{auth.ResourceUsers, auth.ActionUpdate}},
+		{http.MethodHead, "/user-groups", routePermission{auth.ResourceUserGroups, auth.ActionRead}},
+
+		// Overrides
+		{http.MethodPost, "/imports/preview", routePermission{auth.ResourceImports, auth.ActionRead}},
+		{http.MethodPost, "/imports/:id/rerun", routePermission{auth.ResourceImports, auth.ActionCreate}},
+		{http.MethodPost, "/software/:id/classify", routePermission{auth.ResourceClassificationSuggestions, auth.ActionCreate}},
+		{http.MethodGet, "/software/:id/suggestions", routePermission{auth.ResourceClassificationSuggestions, auth.ActionRead}},
+		{http.MethodPost, "/software/:id/versions/:version/restore", routePermission{auth.ResourceSoftware, auth.ActionUpdate}},
+		{http.MethodPost, "/software/:id/undelete", routePermission{auth.ResourceSoftware, auth.ActionUpdate}},
+		{http.MethodPost, "/software/:id/restore", routePermission{auth.ResourceSoftware, auth.ActionUpdate}},
+		{http.MethodPost, "/classification-suggestions/:id/accept", routePermission{auth.ResourceClassificationSuggestions, auth.ActionUpdate}},
+		{http.MethodPost, "/classification-suggestions/:id/reject", routePermission{auth.ResourceClassificationSuggestions, auth.ActionUpdate}},
+		{http.MethodPost, "/users/:id/deactivate", routePermission{auth.ResourceUsers, auth.ActionUpdate}},
+		{http.MethodPost, "/users/:id/activate", routePermission{auth.ResourceUsers, auth.ActionUpdate}},
+		{http.MethodGet, "/users/:id/groups", routePermission{auth.ResourceUserGroups, auth.ActionRead}},
+		{http.MethodGet, "/users/:id/applications", routePermission{auth.ResourceStakeholders, auth.ActionRead}},
+		{http.MethodGet, "/users/:id/changes", routePermission{auth.ResourceHistory, auth.ActionRead}},
+		{http.MethodGet, "/software/:id/stakeholders", routePermission{auth.ResourceStakeholders, auth.ActionRead}},
+		{http.MethodPost, "/software/:id/stakeholders", routePermission{auth.ResourceStakeholders, auth.ActionCreate}},
+		{http.MethodPost, "/user-groups/:id/members", routePermission{auth.ResourceUserGroups, auth.ActionUpdate}},
+		{http.MethodDelete, "/user-groups/:id/members/:userId", routePermission{auth.ResourceUserGroups, auth.ActionUpdate}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			if got := permissionFor(tt.method, tt.path); got != tt.want {
+				t.Errorf("permissionFor(%s, %s) = %+v, want %+v", tt.method, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		role   models.UserRole // No authenticated user if empty
+		method string
+		path   string
+		want   int
+	}{
+		{"unauthenticated", "", http.MethodGet, "/api/v1/software", http.StatusUnauthorized},
+		{"portfolio manager reads software", models.RoleApplicationPortfolioManager, http.MethodGet, "/api/v1/software", http.StatusOK},
+		{"portfolio manager creates software", models.RoleApplicationPortfolioManager, http.MethodPost, "/api/v1/software", http.StatusOK},
+		{"portfolio manager deactivates user", models.RoleApplicationPortfolioManager, http.MethodPost, "/api/v1/users/u2/deactivate", http.StatusForbidden},
+		{"organization admin deactivates user", models.RoleOrganizationAdmin, http.MethodPost, "/api/v1/users/u2/deactivate", http.StatusOK},
+		{"organization admin reads organizations", models.RoleOrganizationAdmin, http.MethodGet, "/api/v1/organizations", http.StatusForbidden},
+		{"platform admin reads organizations", models.RolePlatformAdmin, http.MethodGet, "/api/v1/organizations", http.StatusOK},
+		{"stakeholder reads software", models.RoleStakeholder, http.MethodGet, "/api/v1/software", http.StatusForbidden},
+		{"portfolio manager reads user groups of user", models.RoleApplicationPortfolioManager, http.MethodGet, "/api/v1/users/u2/groups", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			api := router.Group("/api/v1")
+			api.Use(func(c *gin.Context) {
+				if tt.role != "" {
+					user := auth.User{ID: "u1", OrganizationID: "org1", Role: tt.role}
+					c.Request = c.Request.WithContext(auth.WithUser(c.Request.Context(), user))
+				}
+			}, Authorize(auth.DefaultPolicy, "/api/v1"))
+
+			ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+			api.GET("/software", ok)
+			api.POST("/software", ok)
+			api.POST("/users/:id/deactivate", ok)
+			api.GET("/users/:id/groups", ok)
+			api.GET("/organizations", ok)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.want {
+				t.Errorf("%s %s as %q = %d, want %d", tt.method, tt.path, tt.role, rec.Code, tt.want)
+			}
+		})
+	}
+}
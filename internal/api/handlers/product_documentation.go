@@ -44,7 +44,7 @@ func (h *ProductDocumentationHandler) Create(c *gin.Context) {
 
 	resp, err := h.service.Create(c.Request.Context(), req)
 	if err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to create product documentation")
+		RespondWithServiceError(c, err, "Failed to create product documentation")
 		return
 	}
 
@@ -61,7 +61,7 @@ func (h *ProductDocumentationHandler) GetByID(c *gin.Context) {
 
 	resp, err := h.service.GetByID(c.Request.Context(), id)
 	if err != nil {
-		RespondWithError(c, http.StatusNotFound, err, "Product documentation not found")
+		respondWithGetError(c, err, "Product documentation not found", "Failed to retrieve product documentation")
 		return
 	}
 
@@ -70,20 +70,19 @@ func (h *ProductDocumentationHandler) GetByID(c *gin.Context) {
 
 // List handles the retrieval of a list of product documentation
 func (h *ProductDocumentationHandler) List(c *gin.Context) {
-	limit, offset := SetPagination(c)
+	page, err := PageRequest(c)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid cursor")
+		return
+	}
 
-	resp, err := h.service.List(c.Request.Context(), limit, offset)
+	resp, err := h.service.List(c.Request.Context(), page)
 	if err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to retrieve product documentation list")
+		RespondWithServiceError(c, err, "Failed to retrieve product documentation list")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":   resp,
-		"limit":  limit,
-		"offset": offset,
-		"count":  len(resp),
-	})
+	RespondWithPage(c, page, resp)
 }
 
 // Update handles the update of product documentation
@@ -101,7 +100,7 @@ func (h *ProductDocumentationHandler) Update(c *gin.Context) {
 	}
 
 	if err := h.service.Update(c.Request.Context(), id, req); err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to update product documentation")
+		RespondWithServiceError(c, err, "Failed to update product documentation")
 		return
 	}
 
@@ -117,7 +116,7 @@ func (h *ProductDocumentationHandler) Delete(c *gin.Context) {
 	}
 
 	if err := h.service.Delete(c.Request.Context(), id); err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to delete product documentation")
+		RespondWithServiceError(c, err, "Failed to delete product documentation")
 		return
 	}
 
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"apm/internal/models"
+	"apm/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchHandler handles HTTP requests for searching software, entities and categories
+type SearchHandler struct {
+	service services.SearchService
+}
+
+// NewSearchHandler creates a new search handler
+func NewSearchHandler(service services.SearchService) *SearchHandler {
+	return &SearchHandler{
+		service: service,
+	}
+}
+
+// Register registers the routes for searching
+func (h *SearchHandler) Register(router *gin.RouterGroup) {
+	router.GET("/search", h.Search)
+}
+
+// Search handles a search for the text of the q query parameter, narrowed to the kinds of
+// records of the repeated type query parameter. Results are ranked, so they are paged by
+// limit and offset only.
+func (h *SearchHandler) Search(c *gin.Context) {
+	query := models.SearchQuery{Text: c.Query("q")}
+	for _, resultType := range c.QueryArray("type") {
+		query.Types = append(query.Types, models.SearchResultType(resultType))
+	}
+
+	page := OffsetPageRequest(c)
+
+	resp, err := h.service.Search(c.Request.Context(), query, page)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidSearch) {
+			RespondWithError(c, http.StatusBadRequest, err, "Invalid search")
+			return
+		}
+		RespondWithServiceError(c, err, "Failed to search")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"query":  resp.Query,
+		"data":   resp.Results,
+		"facets": resp.Facets,
+		"total":  resp.Total,
+		"limit":  page.Limit,
+		"offset": page.Offset,
+		"count":  len(resp.Results),
+	})
+}
@@ -2,7 +2,10 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"apm/internal/models"
 	"apm/internal/services"
@@ -31,6 +34,15 @@ func (h *SoftwareHandler) Register(router *gin.RouterGroup) {
 		software.GET("/:id", h.GetByID)
 		software.PUT("/:id", h.Update)
 		software.DELETE("/:id", h.Delete)
+		software.POST("/:id/undelete", h.Undelete)
+
+		// Point-in-time history, kept for deleted software too
+		software.GET("/:id/versions", h.ListVersions)
+		software.GET("/:id/versions/:version", h.GetVersion)
+		software.POST("/:id/versions/:version/restore", h.RestoreVersion)
+		software.GET("/:id/diff", h.DiffVersions)
+		software.GET("/:id/as-of", h.GetAsOf)
+		software.POST("/:id/restore", h.Restore)
 	}
 }
 
@@ -44,14 +56,15 @@ func (h *SoftwareHandler) Create(c *gin.Context) {
 
 	resp, err := h.service.Create(c.Request.Context(), req)
 	if err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to create software")
+		RespondWithServiceError(c, err, "Failed to create software")
 		return
 	}
 
 	c.JSON(http.StatusCreated, resp)
 }
 
-// GetByID handles the retrieval of software by ID
+// GetByID handles the retrieval of software by ID; archived software is only returned with
+// include_archived=true
 func (h *SoftwareHandler) GetByID(c *gin.Context) {
 	id := ExtractIDParam(c)
 	if id == "" {
@@ -59,31 +72,53 @@ func (h *SoftwareHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.service.GetByID(c.Request.Context(), id)
+	get := h.service.GetByID
+	if includeArchived(c) {
+		get = h.service.GetByIDIncludingArchived
+	}
+
+	resp, err := get(c.Request.Context(), id)
 	if err != nil {
-		RespondWithError(c, http.StatusNotFound, err, "Software not found")
+		respondWithGetError(c, err, "Software not found", "Failed to retrieve software")
 		return
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
 
-// List handles the retrieval of a list of software
+// List handles the retrieval of a list of software, leaving out archived software unless
+// include_archived=true. The display_name, software_type, vendor and lifecycle_status query
+// parameters filter by the values of the fields; they may be repeated to match any of the
+// values, and values prefixed with ! are excluded instead. The q parameter searches the text
+// of the software, and sort orders it by comma-separated fields, descending if prefixed with -.
 func (h *SoftwareHandler) List(c *gin.Context) {
-	limit, offset := SetPagination(c)
+	page, err := PageRequest(c)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid cursor")
+		return
+	}
+
+	filter := models.SoftwareFilter{
+		IncludeArchived: includeArchived(c),
+		DisplayName:     valueFilter(c, "display_name"),
+		SoftwareType:    valueFilter(c, "software_type"),
+		Vendor:          valueFilter(c, "vendor"),
+		LifecycleStatus: valueFilter(c, "lifecycle_status"),
+		Query:           strings.TrimSpace(c.Query("q")),
+		Sort:            softwareSort(c.Query("sort")),
+	}
 
-	resp, err := h.service.List(c.Request.Context(), limit, offset)
+	resp, err := h.service.List(c.Request.Context(), filter, page)
 	if err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to retrieve software list")
+		if errors.Is(err, services.ErrInvalidSoftwareFilter) {
+			RespondWithError(c, http.StatusBadRequest, err, "Invalid filter or sort")
+			return
+		}
+		RespondWithServiceError(c, err, "Failed to retrieve software list")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":   resp,
-		"limit":  limit,
-		"offset": offset,
-		"count":  len(resp),
-	})
+	RespondWithPage(c, page, resp)
 }
 
 // Update handles the update of software
@@ -101,7 +136,11 @@ func (h *SoftwareHandler) Update(c *gin.Context) {
 	}
 
 	if err := h.service.Update(c.Request.Context(), id, req); err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to update software")
+		if errors.Is(err, services.ErrBusinessOwnerRequired) {
+			RespondWithError(c, http.StatusConflict, err, "Assign a business owner before activating the software")
+			return
+		}
+		RespondWithServiceError(c, err, "Failed to update software")
 		return
 	}
 
@@ -117,9 +156,219 @@ func (h *SoftwareHandler) Delete(c *gin.Context) {
 	}
 
 	if err := h.service.Delete(c.Request.Context(), id); err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to delete software")
+		RespondWithServiceError(c, err, "Failed to delete software")
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
+
+// Undelete handles restoring archived software
+func (h *SoftwareHandler) Undelete(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	resp, err := h.service.Undelete(c.Request.Context(), id)
+	if err != nil {
+		h.respondWithVersionError(c, err, "Failed to undelete software")
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListVersions handles the retrieval of the versions of software
+func (h *SoftwareHandler) ListVersions(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+	page, err := PageRequest(c)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid cursor")
+		return
+	}
+
+	resp, err := h.service.ListVersions(c.Request.Context(), id, page)
+	if err != nil {
+		RespondWithServiceError(c, err, "Failed to retrieve software versions")
+		return
+	}
+
+	RespondWithPage(c, page, resp)
+}
+
+// GetVersion handles the retrieval of a version of software
+func (h *SoftwareHandler) GetVersion(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+	version, err := versionNumber(c.Param("version"))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid version")
+		return
+	}
+
+	resp, err := h.service.GetVersion(c.Request.Context(), id, version)
+	if err != nil {
+		h.respondWithVersionError(c, err, "Failed to retrieve software version")
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RestoreVersion handles bringing software back to the state of a previous version
+func (h *SoftwareHandler) RestoreVersion(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+	version, err := versionNumber(c.Param("version"))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid version")
+		return
+	}
+
+	resp, err := h.service.RestoreVersion(c.Request.Context(), id, version)
+	if err != nil {
+		h.respondWithVersionError(c, err, "Failed to restore software version")
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DiffVersions handles comparing the versions of software given by the from and to query parameters
+func (h *SoftwareHandler) DiffVersions(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+	from, err := versionNumber(c.Query("from"))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid from version")
+		return
+	}
+	to, err := versionNumber(c.Query("to"))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid to version")
+		return
+	}
+
+	resp, err := h.service.DiffVersions(c.Request.Context(), id, from, to)
+	if err != nil {
+		h.respondWithVersionError(c, err, "Failed to compare software versions")
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetAsOf handles the retrieval of software as it was at the time given by the timestamp
+// query parameter, in RFC 3339 format
+func (h *SoftwareHandler) GetAsOf(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+	at, err := queryTime(c, "timestamp")
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid timestamp")
+		return
+	}
+	if at == nil {
+		RespondWithError(c, http.StatusBadRequest, errors.New("timestamp is required"), "Invalid timestamp")
+		return
+	}
+
+	resp, err := h.service.GetAsOf(c.Request.Context(), id, *at)
+	if err != nil {
+		h.respondWithVersionError(c, err, "Failed to retrieve software version")
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Restore handles recreating deleted software as it was when it was deleted
+func (h *SoftwareHandler) Restore(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	resp, err := h.service.Restore(c.Request.Context(), id)
+	if err != nil {
+		h.respondWithVersionError(c, err, "Failed to restore software")
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// respondWithVersionError maps errors of the software history to responses
+func (h *SoftwareHandler) respondWithVersionError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrSoftwareNotFound):
+		RespondWithError(c, http.StatusNotFound, err, "Software not found")
+	case errors.Is(err, services.ErrSoftwareVersionNotFound):
+		RespondWithError(c, http.StatusNotFound, err, "Software version not found")
+	case errors.Is(err, services.ErrSoftwareNotDeleted):
+		RespondWithError(c, http.StatusConflict, err, "Software is not deleted")
+	case errors.Is(err, services.ErrBusinessOwnerRequired):
+		RespondWithError(c, http.StatusConflict, err, "Assign a business owner before activating the software")
+	default:
+		RespondWithServiceError(c, err, message)
+	}
+}
+
+// valueFilter reads the values of a repeatable query parameter into a filter; values prefixed
+// with ! are excluded
+func valueFilter(c *gin.Context, key string) models.ValueFilter {
+	var filter models.ValueFilter
+	for _, value := range c.QueryArray(key) {
+		if excluded, ok := strings.CutPrefix(value, "!"); ok {
+			filter.Exclude = append(filter.Exclude, excluded)
+		} else {
+			filter.Include = append(filter.Include, value)
+		}
+	}
+	return filter
+}
+
+// softwareSort parses a comma-separated list of sort fields, each descending if prefixed with -
+func softwareSort(value string) []models.SoftwareSort {
+	var sort []models.SoftwareSort
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		descending := strings.HasPrefix(field, "-")
+		sort = append(sort, models.SoftwareSort{
+			Field:      strings.TrimPrefix(field, "-"),
+			Descending: descending,
+		})
+	}
+	return sort
+}
+
+// versionNumber parses the number of a version, which starts at 1
+func versionNumber(value string) (int, error) {
+	version, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid version %q", value)
+	}
+	return version, nil
+}
@@ -32,6 +32,10 @@ func (h *StakeholderHandler) Register(router *gin.RouterGroup) {
 		stakeholders.PUT("/:id", h.Update)
 		stakeholders.DELETE("/:id", h.Delete)
 	}
+
+	router.GET("/software/:id/stakeholders", h.ListBySoftware)
+	router.POST("/software/:id/stakeholders", h.CreateForSoftware)
+	router.GET("/users/:id/applications", h.ListByUser)
 }
 
 // Create handles the creation of a new stakeholder
@@ -44,7 +48,31 @@ func (h *StakeholderHandler) Create(c *gin.Context) {
 
 	resp, err := h.service.Create(c.Request.Context(), req)
 	if err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to create stakeholder")
+		h.respondWithError(c, err, "Failed to create stakeholder")
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// CreateForSoftware handles assigning a stakeholder to the software in the path
+func (h *StakeholderHandler) CreateForSoftware(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	var req models.CreateStakeholderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+	req.SoftwareID = id
+
+	resp, err := h.service.Create(c.Request.Context(), req)
+	if err != nil {
+		h.respondWithError(c, err, "Failed to create stakeholder")
 		return
 	}
 
@@ -61,29 +89,29 @@ func (h *StakeholderHandler) GetByID(c *gin.Context) {
 
 	resp, err := h.service.GetByID(c.Request.Context(), id)
 	if err != nil {
-		RespondWithError(c, http.StatusNotFound, err, "Stakeholder not found")
+		respondWithGetError(c, err, "Stakeholder not found", "Failed to retrieve stakeholder")
 		return
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
 
-// List handles the retrieval of a list of stakeholders
+// List handles the retrieval of a list of stakeholders, leaving out archived ones unless
+// include_archived=true
 func (h *StakeholderHandler) List(c *gin.Context) {
-	limit, offset := SetPagination(c)
+	page, err := PageRequest(c)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid cursor")
+		return
+	}
 
-	resp, err := h.service.List(c.Request.Context(), limit, offset)
+	resp, err := h.service.List(c.Request.Context(), includeArchived(c), page)
 	if err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to retrieve stakeholders")
+		RespondWithServiceError(c, err, "Failed to retrieve stakeholders")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":   resp,
-		"limit":  limit,
-		"offset": offset,
-		"count":  len(resp),
-	})
+	RespondWithPage(c, page, resp)
 }
 
 // Update handles the update of a stakeholder
@@ -101,7 +129,7 @@ func (h *StakeholderHandler) Update(c *gin.Context) {
 	}
 
 	if err := h.service.Update(c.Request.Context(), id, req); err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to update stakeholder")
+		h.respondWithError(c, err, "Failed to update stakeholder")
 		return
 	}
 
@@ -117,9 +145,67 @@ func (h *StakeholderHandler) Delete(c *gin.Context) {
 	}
 
 	if err := h.service.Delete(c.Request.Context(), id); err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to delete stakeholder")
+		h.respondWithError(c, err, "Failed to delete stakeholder")
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
+
+// ListBySoftware handles the retrieval of the stakeholders of software
+func (h *StakeholderHandler) ListBySoftware(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	resp, err := h.service.ListBySoftware(c.Request.Context(), id)
+	if err != nil {
+		h.respondWithError(c, err, "Failed to retrieve stakeholders")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  resp,
+		"count": len(resp),
+	})
+}
+
+// ListByUser handles the retrieval of the software a user is a stakeholder of
+func (h *StakeholderHandler) ListByUser(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	resp, err := h.service.ListByUser(c.Request.Context(), id)
+	if err != nil {
+		h.respondWithError(c, err, "Failed to retrieve applications")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  resp,
+		"count": len(resp),
+	})
+}
+
+// respondWithError responds to a failed stakeholder operation
+func (h *StakeholderHandler) respondWithError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrStakeholderNotFound):
+		RespondWithError(c, http.StatusNotFound, err, "Stakeholder not found")
+	case errors.Is(err, services.ErrSoftwareNotFound):
+		RespondWithError(c, http.StatusNotFound, err, "Software not found")
+	case errors.Is(err, services.ErrUserNotFound):
+		RespondWithError(c, http.StatusNotFound, err, "User not found")
+	case errors.Is(err, services.ErrStakeholderExists):
+		RespondWithError(c, http.StatusConflict, err, "User already has this role for the software")
+	case errors.Is(err, services.ErrBusinessOwnerRequired):
+		RespondWithError(c, http.StatusConflict, err, "Active software must keep a business owner")
+	default:
+		RespondWithServiceError(c, err, message)
+	}
+}
@@ -44,7 +44,7 @@ func (h *StatusHandler) Create(c *gin.Context) {
 
 	resp, err := h.service.Create(c.Request.Context(), req)
 	if err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to create status")
+		RespondWithServiceError(c, err, "Failed to create status")
 		return
 	}
 
@@ -61,7 +61,7 @@ func (h *StatusHandler) GetByID(c *gin.Context) {
 
 	resp, err := h.service.GetByID(c.Request.Context(), id)
 	if err != nil {
-		RespondWithError(c, http.StatusNotFound, err, "Status not found")
+		respondWithGetError(c, err, "Status not found", "Failed to retrieve status")
 		return
 	}
 
@@ -70,20 +70,19 @@ func (h *StatusHandler) GetByID(c *gin.Context) {
 
 // List handles the retrieval of a list of statuses
 func (h *StatusHandler) List(c *gin.Context) {
-	limit, offset := SetPagination(c)
+	page, err := PageRequest(c)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid cursor")
+		return
+	}
 
-	resp, err := h.service.List(c.Request.Context(), limit, offset)
+	resp, err := h.service.List(c.Request.Context(), page)
 	if err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to retrieve statuses")
+		RespondWithServiceError(c, err, "Failed to retrieve statuses")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":   resp,
-		"limit":  limit,
-		"offset": offset,
-		"count":  len(resp),
-	})
+	RespondWithPage(c, page, resp)
 }
 
 // Update handles the update of a status
@@ -101,7 +100,7 @@ func (h *StatusHandler) Update(c *gin.Context) {
 	}
 
 	if err := h.service.Update(c.Request.Context(), id, req); err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to update status")
+		RespondWithServiceError(c, err, "Failed to update status")
 		return
 	}
 
@@ -117,7 +116,7 @@ func (h *StatusHandler) Delete(c *gin.Context) {
 	}
 
 	if err := h.service.Delete(c.Request.Context(), id); err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to delete status")
+		RespondWithServiceError(c, err, "Failed to delete status")
 		return
 	}
 
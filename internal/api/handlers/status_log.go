@@ -44,7 +44,7 @@ func (h *StatusLogHandler) Create(c *gin.Context) {
 
 	resp, err := h.service.Create(c.Request.Context(), req)
 	if err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to create status log")
+		RespondWithServiceError(c, err, "Failed to create status log")
 		return
 	}
 
@@ -61,7 +61,7 @@ func (h *StatusLogHandler) GetByID(c *gin.Context) {
 
 	resp, err := h.service.GetByID(c.Request.Context(), id)
 	if err != nil {
-		RespondWithError(c, http.StatusNotFound, err, "Status log not found")
+		respondWithGetError(c, err, "Status log not found", "Failed to retrieve status log")
 		return
 	}
 
@@ -70,20 +70,19 @@ func (h *StatusLogHandler) GetByID(c *gin.Context) {
 
 // List handles the retrieval of a list of status logs
 func (h *StatusLogHandler) List(c *gin.Context) {
-	limit, offset := SetPagination(c)
+	page, err := PageRequest(c)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid cursor")
+		return
+	}
 
-	resp, err := h.service.List(c.Request.Context(), limit, offset)
+	resp, err := h.service.List(c.Request.Context(), page)
 	if err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to retrieve status logs")
+		RespondWithServiceError(c, err, "Failed to retrieve status logs")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":   resp,
-		"limit":  limit,
-		"offset": offset,
-		"count":  len(resp),
-	})
+	RespondWithPage(c, page, resp)
 }
 
 // Update handles the update of a status log
@@ -101,7 +100,7 @@ func (h *StatusLogHandler) Update(c *gin.Context) {
 	}
 
 	if err := h.service.Update(c.Request.Context(), id, req); err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to update status log")
+		RespondWithServiceError(c, err, "Failed to update status log")
 		return
 	}
 
@@ -117,7 +116,7 @@ func (h *StatusLogHandler) Delete(c *gin.Context) {
 	}
 
 	if err := h.service.Delete(c.Request.Context(), id); err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to delete status log")
+		RespondWithServiceError(c, err, "Failed to delete status log")
 		return
 	}
 
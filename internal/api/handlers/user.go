@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"apm/internal/models"
+	"apm/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserHandler handles HTTP requests for the users of an organization
+type UserHandler struct {
+	service          services.UserService
+	accountService   services.AccountService
+	userGroupService services.UserGroupService
+}
+
+// NewUserHandler creates a new user handler; new users are invited through the account service
+func NewUserHandler(
+	service services.UserService,
+	accountService services.AccountService,
+	userGroupService services.UserGroupService,
+) *UserHandler {
+	return &UserHandler{
+		service:          service,
+		accountService:   accountService,
+		userGroupService: userGroupService,
+	}
+}
+
+// Register registers the routes for users
+func (h *UserHandler) Register(router *gin.RouterGroup) {
+	users := router.Group("/users")
+	{
+		users.POST("", h.Invite)
+		users.GET("", h.List)
+		users.GET("/:id", h.GetByID)
+		users.PUT("/:id", h.Update)
+		users.POST("/:id/deactivate", h.Deactivate)
+		users.POST("/:id/activate", h.Activate)
+		users.GET("/:id/groups", h.ListGroups)
+	}
+}
+
+// Invite handles adding a user to the organization of the authenticated user
+func (h *UserHandler) Invite(c *gin.Context) {
+	var req models.CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	resp, err := h.accountService.Invite(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, services.ErrEmailTaken) {
+			RespondWithError(c, http.StatusConflict, err, "Email is already registered")
+			return
+		}
+		RespondWithServiceError(c, err, "Failed to invite user")
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// GetByID handles the retrieval of a user by ID
+func (h *UserHandler) GetByID(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	resp, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		respondWithGetError(c, err, "User not found", "Failed to retrieve user")
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// List handles the retrieval of a list of users
+func (h *UserHandler) List(c *gin.Context) {
+	page, err := PageRequest(c)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid cursor")
+		return
+	}
+
+	resp, err := h.service.List(c.Request.Context(), page)
+	if err != nil {
+		RespondWithServiceError(c, err, "Failed to retrieve users")
+		return
+	}
+
+	RespondWithPage(c, page, resp)
+}
+
+// Update handles the update of the name, role or avatar of a user
+func (h *UserHandler) Update(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	var req models.UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	if err := h.service.Update(c.Request.Context(), id, req); err != nil {
+		h.respondWithError(c, err, "Failed to update user")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Deactivate handles stopping a user from logging in
+func (h *UserHandler) Deactivate(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	if err := h.service.Deactivate(c.Request.Context(), id); err != nil {
+		h.respondWithError(c, err, "Failed to deactivate user")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Activate handles letting a deactivated user log in again
+func (h *UserHandler) Activate(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	if err := h.service.Activate(c.Request.Context(), id); err != nil {
+		h.respondWithError(c, err, "Failed to activate user")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListGroups handles the retrieval of the user groups a user is a member of
+func (h *UserHandler) ListGroups(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	resp, err := h.userGroupService.ListByUser(c.Request.Context(), id)
+	if err != nil {
+		h.respondWithError(c, err, "Failed to retrieve user groups")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  resp,
+		"count": len(resp),
+	})
+}
+
+// respondWithError responds to a failed change of a user
+func (h *UserHandler) respondWithError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrLastAdmin):
+		RespondWithError(c, http.StatusConflict, err, "Another administrator must be appointed first")
+	case errors.Is(err, services.ErrSoleBusinessOwner):
+		RespondWithError(c, http.StatusConflict, err, "Another business owner must be assigned to their active software first")
+	case errors.Is(err, services.ErrUserNotFound):
+		RespondWithError(c, http.StatusNotFound, err, "User not found")
+	default:
+		RespondWithServiceError(c, err, message)
+	}
+}
@@ -31,6 +31,9 @@ func (h *UserGroupHandler) Register(router *gin.RouterGroup) {
 		groups.GET("/:id", h.GetByID)
 		groups.PUT("/:id", h.Update)
 		groups.DELETE("/:id", h.Delete)
+		groups.GET("/:id/members", h.ListMembers)
+		groups.POST("/:id/members", h.AddMember)
+		groups.DELETE("/:id/members/:userId", h.RemoveMember)
 	}
 }
 
@@ -44,7 +47,7 @@ func (h *UserGroupHandler) Create(c *gin.Context) {
 
 	resp, err := h.service.Create(c.Request.Context(), req)
 	if err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to create user group")
+		RespondWithServiceError(c, err, "Failed to create user group")
 		return
 	}
 
@@ -61,7 +64,7 @@ func (h *UserGroupHandler) GetByID(c *gin.Context) {
 
 	resp, err := h.service.GetByID(c.Request.Context(), id)
 	if err != nil {
-		RespondWithError(c, http.StatusNotFound, err, "User group not found")
+		respondWithGetError(c, err, "User group not found", "Failed to retrieve user group")
 		return
 	}
 
@@ -70,20 +73,19 @@ func (h *UserGroupHandler) GetByID(c *gin.Context) {
 
 // List handles the retrieval of a list of user groups
 func (h *UserGroupHandler) List(c *gin.Context) {
-	limit, offset := SetPagination(c)
+	page, err := PageRequest(c)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid cursor")
+		return
+	}
 
-	resp, err := h.service.List(c.Request.Context(), limit, offset)
+	resp, err := h.service.List(c.Request.Context(), page)
 	if err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to retrieve user groups")
+		RespondWithServiceError(c, err, "Failed to retrieve user groups")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":   resp,
-		"limit":  limit,
-		"offset": offset,
-		"count":  len(resp),
-	})
+	RespondWithPage(c, page, resp)
 }
 
 // Update handles the update of a user group
@@ -101,7 +103,7 @@ func (h *UserGroupHandler) Update(c *gin.Context) {
 	}
 
 	if err := h.service.Update(c.Request.Context(), id, req); err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to update user group")
+		RespondWithServiceError(c, err, "Failed to update user group")
 		return
 	}
 
@@ -117,9 +119,84 @@ func (h *UserGroupHandler) Delete(c *gin.Context) {
 	}
 
 	if err := h.service.Delete(c.Request.Context(), id); err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err, "Failed to delete user group")
+		RespondWithServiceError(c, err, "Failed to delete user group")
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
+
+// ListMembers handles the retrieval of the members of a user group
+func (h *UserGroupHandler) ListMembers(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+	page, err := PageRequest(c)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid cursor")
+		return
+	}
+
+	resp, err := h.service.ListMembers(c.Request.Context(), id, page)
+	if err != nil {
+		h.respondWithMembershipError(c, err, "Failed to retrieve user group members")
+		return
+	}
+
+	RespondWithPage(c, page, resp)
+}
+
+// AddMember handles adding a user to a user group
+func (h *UserGroupHandler) AddMember(c *gin.Context) {
+	id := ExtractIDParam(c)
+	if id == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	var req models.AddUserGroupMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	if err := h.service.AddMember(c.Request.Context(), id, req); err != nil {
+		h.respondWithMembershipError(c, err, "Failed to add user group member")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RemoveMember handles removing a user from a user group
+func (h *UserGroupHandler) RemoveMember(c *gin.Context) {
+	id := ExtractIDParam(c)
+	userID := c.Param("userId")
+	if id == "" || userID == "" {
+		RespondWithError(c, http.StatusBadRequest, errors.New("missing or invalid ID"), "Missing or invalid ID")
+		return
+	}
+
+	if err := h.service.RemoveMember(c.Request.Context(), id, userID); err != nil {
+		h.respondWithMembershipError(c, err, "Failed to remove user group member")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondWithMembershipError responds to a failed change or lookup of group memberships
+func (h *UserGroupHandler) respondWithMembershipError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrUserGroupNotFound):
+		RespondWithError(c, http.StatusNotFound, err, "User group not found")
+	case errors.Is(err, services.ErrUserNotFound):
+		RespondWithError(c, http.StatusNotFound, err, "User not found")
+	case errors.Is(err, services.ErrNotGroupMember):
+		RespondWithError(c, http.StatusNotFound, err, "User is not a member of the group")
+	default:
+		RespondWithServiceError(c, err, message)
+	}
+}
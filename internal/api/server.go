@@ -2,16 +2,23 @@ package api
 
 import (
 	"context"
+	"errors"
 	"log"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 
+	"apm/internal/accesslog"
 	"apm/internal/api/handlers"
+	"apm/internal/auth"
 	"apm/internal/config"
 	"apm/internal/db"
 	"apm/internal/services"
+	"apm/internal/tenant"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 // Server represents the HTTP server
@@ -66,7 +73,7 @@ func NewServer(config config.Config, database *db.Database, logger *log.Logger)
 
 // initServices initializes all service instances
 func (s *Server) initServices() {
-	s.services = services.NewServices(s.db, s.logger)
+	s.services = services.NewServices(s.config, s.db, s.logger)
 }
 
 // initHandlers initializes all handler instances
@@ -86,10 +93,23 @@ func (s *Server) initHandlers() {
 		s.services.MediaService,
 		s.services.ProductDocumentationService,
 		s.services.LogService,
+		s.services.ImportService,
+		s.services.ImportMappingProfileService,
+		s.services.ClassificationService,
+		s.services.OrganizationService,
+		s.services.AccountService,
+		s.services.AuditService,
+		s.services.AccessLogService,
+		s.services.SearchService,
 	)
 
 	// Initialize auth handler
-	s.authHandler = handlers.NewAuthHandler(s.services.UserService, s.config.Server.JWTSecret)
+	s.authHandler = handlers.NewAuthHandler(
+		s.services.AuthService,
+		s.services.AccountService,
+		s.services.UserService,
+		s.services.MFAService,
+	)
 }
 
 // Start starts the HTTP server
@@ -101,7 +121,12 @@ func (s *Server) Start() error {
 // Shutdown gracefully shuts down the server
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.Println("Shutting down server...")
-	return s.server.Shutdown(ctx)
+	if err := s.server.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	// Stop the background work, writing the access logs of the last requests
+	return s.services.Close(ctx)
 }
 
 // setupRoutes sets up the HTTP routes
@@ -117,14 +142,16 @@ func (s *Server) setupRoutes() *gin.Engine {
 	// Health check endpoint
 	router.GET("/health", s.handleHealth)
 
-	// API routes
-	api := router.Group("/api")
+	// API routes, acting for the organization of the request
+	api := router.Group("/api", s.tenantMiddleware(), s.accessLogMiddleware())
 	{
+		authenticate := s.authMiddleware()
+
 		// Register auth routes
-		s.authHandler.RegisterRoutes(api)
+		s.authHandler.RegisterRoutes(api, authenticate)
 
-		// Register all API routes using the handler factory
-		s.handlers.RegisterRoutes(api)
+		// Register all API routes using the handler factory; they require an access token
+		s.handlers.RegisterRoutes(api, authenticate)
 	}
 
 	return router
@@ -162,6 +189,122 @@ func (s *Server) loggerMiddleware() gin.HandlerFunc {
 	}
 }
 
+// accessLogMiddleware places the client of a request into the request context, for recording
+// logins, and records the request in the access log if it was made by an authenticated user
+func (s *Server) accessLogMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := accesslog.WithClient(c.Request.Context(), accesslog.Client{
+			IPAddress: c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		// The authentication middleware has placed the user into the context by now, if any
+		s.services.AccessLog.RecordRequest(c.Request.Context(), c.Request.Method, c.Request.URL.Path, c.Writer.Status())
+	}
+}
+
+// authMiddleware rejects requests without a valid Bearer access token and places the
+// authenticated user, and the organization they act for, into the request context
+func (s *Server) authMiddleware() gin.HandlerFunc {
+	secret := []byte(s.config.Server.JWTSecret)
+
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		scheme, tokenString, found := strings.Cut(header, " ")
+		if header == "" || !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(tokenString) == "" {
+			s.abortUnauthorized(c, errors.New("missing bearer token"), "Authentication required")
+			return
+		}
+
+		claims, err := auth.ParseAccessToken(strings.TrimSpace(tokenString), secret)
+		if err != nil {
+			message := "Invalid access token"
+			if errors.Is(err, jwt.ErrTokenExpired) {
+				message = "Access token has expired"
+			}
+			s.abortUnauthorized(c, err, message)
+			return
+		}
+
+		user := auth.UserFromClaims(claims)
+		if organizationID, err := tenant.OrganizationID(c.Request.Context()); err == nil && organizationID != user.OrganizationID {
+			handlers.RespondWithError(c, http.StatusForbidden, errors.New("token was issued for a different organization"), "Access token is not valid for this organization")
+			c.Abort()
+			return
+		}
+
+		ctx := auth.WithUser(c.Request.Context(), user)
+		ctx = tenant.WithOrganizationID(ctx, user.OrganizationID)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// tenantMiddleware resolves the organization a request is for from the subdomain of its host
+// and places it into the request context; the authentication middleware then rejects access
+// tokens of other organizations. Requests to the base domain itself act for the organization
+// of their access token.
+func (s *Server) tenantMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		subdomain := s.requestSubdomain(c.Request)
+		if subdomain == "" {
+			c.Next()
+			return
+		}
+
+		organization, err := s.services.OrganizationService.GetBySubdomain(c.Request.Context(), subdomain)
+		if err != nil {
+			if errors.Is(err, services.ErrOrganizationNotFound) {
+				handlers.RespondWithError(c, http.StatusNotFound, err, "Unknown organization")
+			} else {
+				handlers.RespondWithError(c, http.StatusInternalServerError, err, "Failed to resolve organization")
+			}
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(tenant.WithOrganizationID(c.Request.Context(), organization.ID))
+		c.Next()
+	}
+}
+
+// requestSubdomain returns the subdomain of the base domain a request is for. Outside
+// production, where the UI usually runs on localhost, the X-Organization header can name
+// the subdomain instead.
+func (s *Server) requestSubdomain(r *http.Request) string {
+	if s.config.Server.Environment != "production" {
+		if subdomain := strings.TrimSpace(r.Header.Get("X-Organization")); subdomain != "" {
+			return strings.ToLower(subdomain)
+		}
+	}
+
+	baseDomain := strings.ToLower(strings.Trim(s.config.Server.BaseDomain, "."))
+	if baseDomain == "" {
+		return ""
+	}
+
+	host := strings.ToLower(r.Host)
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+
+	subdomain, found := strings.CutSuffix(host, "."+baseDomain)
+	if !found || subdomain == "" || strings.Contains(subdomain, ".") || subdomain == "www" {
+		return ""
+	}
+	return subdomain
+}
+
+// abortUnauthorized stops a request that is not authenticated
+func (s *Server) abortUnauthorized(c *gin.Context, err error, message string) {
+	c.Header("WWW-Authenticate", `Bearer realm="apm"`)
+	handlers.RespondWithError(c, http.StatusUnauthorized, err, message)
+	c.Abort()
+}
+
 // corsMiddleware adds CORS headers to responses
 func (s *Server) corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -173,7 +316,7 @@ func (s *Server) corsMiddleware() gin.HandlerFunc {
 
 		c.Writer.Header().Set("Access-Control-Allow-Origin", origins)
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Organization")
 
 		// Handle preflight requests
 		if c.Request.Method == "OPTIONS" {
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"apm/internal/auth"
+	"apm/internal/config"
+	"apm/internal/models"
+	"apm/internal/services"
+	"apm/internal/tenant"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testJWTSecret = "test-secret"
+
+// subdomainOrganizations resolves the subdomains of a fixed set of organizations
+type subdomainOrganizations struct {
+	services.OrganizationService
+	organizations map[string]string // Organization ID of each subdomain
+}
+
+func (o subdomainOrganizations) GetBySubdomain(ctx context.Context, subdomain string) (models.OrganizationResponse, error) {
+	id, ok := o.organizations[subdomain]
+	if !ok {
+		return models.OrganizationResponse{}, services.ErrOrganizationNotFound
+	}
+	return models.OrganizationResponse{ID: id, Subdomain: subdomain}, nil
+}
+
+func TestTenantAndAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	s := &Server{
+		config: config.Config{Server: config.ServerConfig{Environment: "test", JWTSecret: testJWTSecret}},
+		services: &services.Services{OrganizationService: subdomainOrganizations{organizations: map[string]string{
+			"a": "org-a",
+			"b": "org-b",
+		}}},
+	}
+
+	router := gin.New()
+	router.GET("/api/v1/software", s.tenantMiddleware(), s.authMiddleware(), func(c *gin.Context) {
+		organizationID, _ := tenant.OrganizationID(c.Request.Context())
+		c.JSON(http.StatusOK, gin.H{"organization_id": organizationID})
+	})
+
+	token, err := auth.NewAccessToken(models.UserResponse{
+		ID:             "user-a",
+		OrganizationID: "org-a",
+		Role:           models.RoleOrganizationAdmin,
+	}, []byte(testJWTSecret), time.Minute)
+	if err != nil {
+		t.Fatalf("NewAccessToken() = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		subdomain    string
+		token        string
+		want         int
+		organization string // Organization the request acts for, if let through
+	}{
+		{"own organization", "a", token, http.StatusOK, "org-a"},
+		{"other organization", "b", token, http.StatusForbidden, ""},
+		{"base domain acts for the token organization", "", token, http.StatusOK, "org-a"},
+		{"unknown organization", "c", token, http.StatusNotFound, ""},
+		{"no token", "a", "", http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/software", nil)
+			if tt.subdomain != "" {
+				req.Header.Set("X-Organization", tt.subdomain)
+			}
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				OrganizationID string `json:"organization_id"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if body.OrganizationID != tt.organization {
+				t.Errorf("request acted for %q, want %q", body.OrganizationID, tt.organization)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Package apperr defines the kinds of errors the domain reports, so that they can be told apart
+// from failures however deeply they are wrapped, and answered accordingly. An error carries a
+// message that is safe to show to clients, unlike the errors it wraps.
+package apperr
+
+import (
+	"errors"
+)
+
+// Kind is the kind of an error
+type Kind string
+
+const (
+	KindInternal     Kind = "internal"     // A failure, not caused by the request
+	KindNotFound     Kind = "not_found"    // The record acted on does not exist
+	KindConflict     Kind = "conflict"     // The request conflicts with the state of records
+	KindValidation   Kind = "validation"   // The request is malformed or its values are invalid
+	KindUnauthorized Kind = "unauthorized" // The client is not authenticated
+	KindForbidden    Kind = "forbidden"    // The client may not perform the request
+	KindRateLimited  Kind = "rate_limited" // The client made too many requests and must wait
+)
+
+// Error is an error of a kind
+type Error struct {
+	Kind    Kind
+	Message string // Safe to show to clients
+	Err     error  // Cause, if any; never shown to clients
+}
+
+// Error returns the message of the error, followed by its cause
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+// Unwrap returns the cause of the error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New creates an error of a kind. Sentinel errors made with it are matched with errors.Is as
+// usual, while their kind is found with KindOf.
+func New(kind Kind, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+// Wrap creates an error of a kind caused by another error
+func Wrap(kind Kind, message string, err error) *Error {
+	return &Error{Kind: kind, Message: message, Err: err}
+}
+
+// NotFound creates an error for a record that does not exist
+func NotFound(message string) *Error {
+	return New(KindNotFound, message)
+}
+
+// Conflict creates an error for a request conflicting with the state of records
+func Conflict(message string) *Error {
+	return New(KindConflict, message)
+}
+
+// Validation creates an error for a malformed request or invalid values
+func Validation(message string) *Error {
+	return New(KindValidation, message)
+}
+
+// Unauthorized creates an error for a client that is not authenticated
+func Unauthorized(message string) *Error {
+	return New(KindUnauthorized, message)
+}
+
+// Forbidden creates an error for a request the client may not perform
+func Forbidden(message string) *Error {
+	return New(KindForbidden, message)
+}
+
+// RateLimited creates an error for a client that made too many requests
+func RateLimited(message string) *Error {
+	return New(KindRateLimited, message)
+}
+
+// KindOf returns the kind of the outermost error of a kind in the chain of an error, or
+// KindInternal if there is none
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return KindInternal
+}
+
+// MessageOf returns the message of the outermost error of a kind in the chain of an error, or
+// an empty string if there is none
+func MessageOf(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Message
+	}
+	return ""
+}
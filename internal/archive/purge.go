@@ -0,0 +1,139 @@
+// Package archive purges archived portfolio records once their retention period has passed.
+// Until then, archived records are hidden but can be restored.
+package archive
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"apm/internal/db/repository"
+)
+
+// purgeTimeout bounds the time spent purging archived records at once
+const purgeTimeout = 5 * time.Minute
+
+// Options configure a Purger
+type Options struct {
+	Retention time.Duration // Time archived records are kept for
+	Interval  time.Duration // Time between purges
+}
+
+// Purger permanently deletes archived records of all organizations once they have been archived
+// for longer than the retention period, purging in the background at an interval. A nil Purger
+// purges nothing.
+type Purger struct {
+	software     repository.SoftwareRepository
+	stakeholders repository.StakeholderRepository
+	options      Options
+	logger       *log.Logger
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewPurger creates a new purger and starts purging in the background until it is closed.
+// Without a retention period archived records are kept forever, and no purger is created.
+func NewPurger(
+	software repository.SoftwareRepository,
+	stakeholders repository.StakeholderRepository,
+	options Options,
+	logger *log.Logger,
+) *Purger {
+	if options.Retention <= 0 {
+		logger.Println("Archived records are kept forever, no retention period is set")
+		return nil
+	}
+	if options.Interval <= 0 {
+		options.Interval = 24 * time.Hour
+	}
+
+	p := &Purger{
+		software:     software,
+		stakeholders: stakeholders,
+		options:      options,
+		logger:       logger,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Purge permanently deletes the records that were archived before the retention period
+func (p *Purger) Purge(ctx context.Context) error {
+	if p == nil {
+		return nil
+	}
+
+	before := time.Now().UTC().Add(-p.options.Retention)
+
+	software, softwareErr := p.software.PurgeArchived(ctx, before)
+	if softwareErr != nil {
+		p.logger.Printf("Error purging archived software: %v", softwareErr)
+	}
+
+	// Stakeholders of purged software are gone with it; these were archived on their own
+	stakeholders, stakeholdersErr := p.stakeholders.PurgeArchived(ctx, before)
+	if stakeholdersErr != nil {
+		p.logger.Printf("Error purging archived stakeholders: %v", stakeholdersErr)
+	}
+
+	if software > 0 || stakeholders > 0 {
+		p.logger.Printf("Purged %d software and %d stakeholders archived before %s", software, stakeholders, before.Format(time.RFC3339))
+	}
+	return errors.Join(softwareErr, stakeholdersErr)
+}
+
+// Close stops purging, waiting for a purge in progress unless the context ends first
+func (p *Purger) Close(ctx context.Context) error {
+	if p == nil {
+		return nil
+	}
+
+	p.closeOnce.Do(func() { close(p.stop) })
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run purges when started and then at every interval, until the purger is closed
+func (p *Purger) run() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		p.purge()
+
+		select {
+		case <-ticker.C:
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// purge purges once, giving up when the purger is closed; errors are logged by Purge
+func (p *Purger) purge() {
+	ctx, cancel := context.WithTimeout(context.Background(), purgeTimeout)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-p.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	_ = p.Purge(ctx)
+}
@@ -0,0 +1,243 @@
+// Package audit records the field-level changes made to the records of an organization in
+// its audit trail.
+package audit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"apm/internal/auth"
+	"apm/internal/db/repository"
+	"apm/internal/models"
+)
+
+// Entity types, naming the kinds of records in the audit trail
+const (
+	EntitySoftware             = "software"
+	EntityUser                 = "user"
+	EntityUserGroup            = "user_group"
+	EntityStakeholder          = "stakeholder"
+	EntityImportMappingProfile = "import_mapping_profile"
+)
+
+// entityTypes lists the entity types that are recorded
+var entityTypes = []string{
+	EntitySoftware, EntityUser, EntityUserGroup, EntityStakeholder, EntityImportMappingProfile,
+}
+
+// IsEntityType reports whether the audit trail records entities of the type
+func IsEntityType(entityType string) bool {
+	for _, known := range entityTypes {
+		if entityType == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoredFields are fields of records that change with every change, or never
+var ignoredFields = map[string]bool{
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+// Change is the change of a single field
+type Change struct {
+	Field    string
+	OldValue string
+	NewValue string
+}
+
+// Diff compares two versions of a record, returning the changes of its fields. Fields are
+// named after their JSON names; fields hidden from JSON, like password hashes, are left out.
+// Either version may be nil, for records that are created or deleted.
+func Diff(before, after interface{}) []Change {
+	oldValues := fieldValues(before)
+	newValues := fieldValues(after)
+
+	var changes []Change
+	for _, field := range fieldNames(before, after) {
+		if oldValues[field] != newValues[field] {
+			changes = append(changes, Change{Field: field, OldValue: oldValues[field], NewValue: newValues[field]})
+		}
+	}
+	return changes
+}
+
+// Trail writes changes to the audit trail. Changes are attributed to the authenticated user
+// of the context. A nil Trail records nothing.
+type Trail struct {
+	repo   repository.EditLogRepository
+	logger *log.Logger
+}
+
+// NewTrail creates a new audit trail stored in the repository
+func NewTrail(repo repository.EditLogRepository, logger *log.Logger) *Trail {
+	return &Trail{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// RecordCreate records the creation of a record
+func (t *Trail) RecordCreate(ctx context.Context, entityType, entityID string, record interface{}) error {
+	return t.Record(ctx, entityType, entityID, models.EditActionCreate, Diff(nil, record))
+}
+
+// RecordUpdate records the changes between two versions of a record, if there are any
+func (t *Trail) RecordUpdate(ctx context.Context, entityType, entityID string, before, after interface{}) error {
+	return t.Record(ctx, entityType, entityID, models.EditActionUpdate, Diff(before, after))
+}
+
+// RecordDelete records the deletion of a record, keeping its last values
+func (t *Trail) RecordDelete(ctx context.Context, entityType, entityID string, record interface{}) error {
+	return t.Record(ctx, entityType, entityID, models.EditActionDelete, Diff(record, nil))
+}
+
+// Record records changes of a record as a single change
+func (t *Trail) Record(ctx context.Context, entityType, entityID string, action models.EditAction, changes []Change) error {
+	if t == nil || len(changes) == 0 {
+		return nil
+	}
+
+	var userID string
+	if user, ok := auth.UserFromContext(ctx); ok {
+		userID = user.ID
+	}
+
+	changeID := newChangeID()
+	now := time.Now().UTC()
+	logs := make([]models.EditLog, 0, len(changes))
+	for _, change := range changes {
+		logs = append(logs, models.EditLog{
+			ChangeID:   changeID,
+			UserID:     userID,
+			EntityType: entityType,
+			EntityID:   entityID,
+			Action:     action,
+			FieldName:  change.Field,
+			OldValue:   change.OldValue,
+			NewValue:   change.NewValue,
+			Timestamp:  now,
+		})
+	}
+
+	if err := t.repo.Create(ctx, logs); err != nil {
+		t.logger.Printf("Error recording %s of %s %s: %v", action, entityType, entityID, err)
+		return fmt.Errorf("failed to record change: %w", err)
+	}
+	return nil
+}
+
+// fieldNames returns the names of the recorded fields of the records, in declaration order
+func fieldNames(records ...interface{}) []string {
+	for _, record := range records {
+		value := indirect(reflect.ValueOf(record))
+		if value.Kind() != reflect.Struct {
+			continue
+		}
+
+		var names []string
+		for i := 0; i < value.NumField(); i++ {
+			if name, ok := fieldName(value.Type().Field(i)); ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// fieldValues returns the recorded fields of a record by name, formatted as text
+func fieldValues(record interface{}) map[string]string {
+	values := map[string]string{}
+
+	value := indirect(reflect.ValueOf(record))
+	if value.Kind() != reflect.Struct {
+		return values
+	}
+
+	for i := 0; i < value.NumField(); i++ {
+		if name, ok := fieldName(value.Type().Field(i)); ok {
+			values[name] = format(value.Field(i))
+		}
+	}
+	return values
+}
+
+// fieldName returns the JSON name of an exported struct field, and whether it is recorded
+func fieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, !ignoredFields[name]
+}
+
+// format formats a field value as text; empty and zero values are formatted as ""
+func format(value reflect.Value) string {
+	value = indirect(value)
+	if !value.IsValid() || value.IsZero() {
+		return ""
+	}
+
+	switch v := value.Interface().(type) {
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case fmt.Stringer:
+		return v.String()
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return value.String()
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64)
+	default:
+		encoded, err := json.Marshal(value.Interface())
+		if err != nil {
+			return fmt.Sprint(value.Interface())
+		}
+		return string(encoded)
+	}
+}
+
+// indirect follows pointers and interfaces to the value they point to
+func indirect(value reflect.Value) reflect.Value {
+	for value.IsValid() && (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+// newChangeID creates a random ID grouping the fields of a change
+func newChangeID() string {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return fmt.Sprintf("change-%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(bytes)
+}
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"context"
+
+	"apm/internal/models"
+)
+
+// User identifies the authenticated user of a request
+type User struct {
+	ID             string
+	OrganizationID string
+	Role           models.UserRole
+}
+
+// contextKey is the type of the context key the user is stored under
+type contextKey struct{}
+
+// UserFromClaims returns the user an access token was issued to
+func UserFromClaims(claims Claims) User {
+	return User{
+		ID:             claims.Subject,
+		OrganizationID: claims.OrganizationID,
+		Role:           claims.Role,
+	}
+}
+
+// WithUser returns a copy of the context carrying the authenticated user
+func WithUser(ctx context.Context, user User) context.Context {
+	return context.WithValue(ctx, contextKey{}, user)
+}
+
+// UserFromContext returns the authenticated user carried by the context, if any
+func UserFromContext(ctx context.Context) (User, bool) {
+	user, ok := ctx.Value(contextKey{}).(User)
+	return user, ok
+}
@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"slices"
+
+	"apm/internal/apperr"
+	"apm/internal/models"
+)
+
+// Action is an operation performed on a resource
+type Action string
+
+// Actions
+const (
+	ActionRead   Action = "read"
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Resource is a kind of object users act on; resources are named after their API path
+type Resource string
+
+// Resources
+const (
+	ResourceSoftware                  Resource = "software"
+	ResourceEntities                  Resource = "entities"
+	ResourceFunctionalCategories      Resource = "functional-categories"
+	ResourceSoftwareGroups            Resource = "software-groups"
+	ResourceStatuses                  Resource = "statuses"
+	ResourceStatusLogs                Resource = "status-logs"
+	ResourceRanks                     Resource = "ranks"
+	ResourceNews                      Resource = "news"
+	ResourceMedia                     Resource = "media"
+	ResourceProductDocumentation      Resource = "product-documentation"
+	ResourceStakeholders              Resource = "stakeholders"
+	ResourceImports                   Resource = "imports"
+	ResourceImportProfiles            Resource = "import-profiles"
+	ResourceClassificationSuggestions Resource = "classification-suggestions"
+	ResourceUsers                     Resource = "users"
+	ResourceUserGroups                Resource = "user-groups"
+	ResourceLogs                      Resource = "logs"
+	ResourceOrganizations             Resource = "organizations"
+	ResourceHistory                   Resource = "history"
+	ResourceAccessLogs                Resource = "access-logs"
+	ResourceSearch                    Resource = "search"
+)
+
+// Errors returned when a request may not perform an action
+var (
+	ErrNotAuthenticated = apperr.Unauthorized("not authenticated")
+	ErrForbidden        = apperr.Forbidden("forbidden")
+)
+
+// Policy declares, per resource and action, the roles that are permitted to perform it.
+// Anything not declared is denied.
+type Policy map[Resource]map[Action][]models.UserRole
+
+var (
+	// platformAdmins may manage all organizations
+	platformAdmins = []models.UserRole{models.RolePlatformAdmin}
+
+	// administrators may manage the organization and its users
+	administrators = []models.UserRole{models.RolePlatformAdmin, models.RoleOrganizationAdmin}
+
+	// portfolioManagers may manage the application portfolio
+	portfolioManagers = []models.UserRole{
+		models.RolePlatformAdmin, models.RoleOrganizationAdmin, models.RoleApplicationPortfolioManager,
+	}
+)
+
+// allActions permits every action to the roles
+func allActions(roles []models.UserRole) map[Action][]models.UserRole {
+	return map[Action][]models.UserRole{
+		ActionRead:   roles,
+		ActionCreate: roles,
+		ActionUpdate: roles,
+		ActionDelete: roles,
+	}
+}
+
+// DefaultPolicy is the policy of the roles in docs/instructions.md. Organization admins may
+// do everything within their organization; application portfolio managers may manage the
+// portfolio but not the users of the organization; stakeholders may not access anything.
+// Platform admins may additionally manage the organizations themselves.
+var DefaultPolicy = Policy{
+	ResourceSoftware:                  allActions(portfolioManagers),
+	ResourceEntities:                  allActions(portfolioManagers),
+	ResourceFunctionalCategories:      allActions(portfolioManagers),
+	ResourceSoftwareGroups:            allActions(portfolioManagers),
+	ResourceStatuses:                  allActions(portfolioManagers),
+	ResourceStatusLogs:                allActions(portfolioManagers),
+	ResourceRanks:                     allActions(portfolioManagers),
+	ResourceNews:                      allActions(portfolioManagers),
+	ResourceMedia:                     allActions(portfolioManagers),
+	ResourceProductDocumentation:      allActions(portfolioManagers),
+	ResourceStakeholders:              allActions(portfolioManagers),
+	ResourceImports:                   allActions(portfolioManagers),
+	ResourceImportProfiles:            allActions(portfolioManagers),
+	ResourceClassificationSuggestions: allActions(portfolioManagers),
+	ResourceUsers:                     allActions(administrators),
+	ResourceUserGroups: {
+		ActionRead:   portfolioManagers,
+		ActionCreate: administrators,
+		ActionUpdate: administrators,
+		ActionDelete: administrators,
+	},
+	ResourceLogs:          allActions(administrators),
+	ResourceOrganizations: allActions(platformAdmins),
+	ResourceHistory:       {ActionRead: portfolioManagers},
+	ResourceAccessLogs:    {ActionRead: administrators},
+	ResourceSearch:        {ActionRead: portfolioManagers},
+}
+
+// Allows reports whether the role may perform the action on the resource
+func (p Policy) Allows(role models.UserRole, resource Resource, action Action) bool {
+	return slices.Contains(p[resource][action], role)
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"testing"
+
+	"apm/internal/models"
+)
+
+func TestDefaultPolicyAllows(t *testing.T) {
+	tests := []struct {
+		role     models.UserRole
+		resource Resource
+		action   Action
+		allowed  bool
+	}{
+		// Platform admins may do everything, including managing organizations
+		{models.RolePlatformAdmin, ResourceSoftware, ActionCreate, true},
+		{models.RolePlatformAdmin, ResourceUsers, ActionDelete, true},
+		{models.RolePlatformAdmin, ResourceUserGroups, ActionUpdate, true},
+		{models.RolePlatformAdmin, ResourceOrganizations, ActionCreate, true},
+		{models.RolePlatformAdmin, ResourceOrganizations, ActionDelete, true},
+		{models.RolePlatformAdmin, ResourceAccessLogs, ActionRead, true},
+		{models.RolePlatformAdmin, ResourceHistory, ActionDelete, false},
+
+		// Organization admins may do everything within their organization
+		{models.RoleOrganizationAdmin, ResourceSoftware, ActionRead, true},
+		{models.RoleOrganizationAdmin, ResourceSoftware, ActionDelete, true},
+		{models.RoleOrganizationAdmin, ResourceStakeholders, ActionCreate, true},
+		{models.RoleOrganizationAdmin, ResourceUsers, ActionCreate, true},
+		{models.RoleOrganizationAdmin, ResourceUsers, ActionUpdate, true},
+		{models.RoleOrganizationAdmin, ResourceUserGroups, ActionDelete, true},
+		{models.RoleOrganizationAdmin, ResourceLogs, ActionRead, true},
+		{models.RoleOrganizationAdmin, ResourceAccessLogs, ActionRead, true},
+		{models.RoleOrganizationAdmin, ResourceSearch, ActionRead, true},
+		{models.RoleOrganizationAdmin, ResourceOrganizations, ActionRead, false},
+		{models.RoleOrganizationAdmin, ResourceOrganizations, ActionUpdate, false},
+		{models.RoleOrganizationAdmin, ResourceAccessLogs, ActionDelete, false},
+
+		// Application portfolio managers may manage the portfolio, but not users
+		{models.RoleApplicationPortfolioManager, ResourceSoftware, ActionRead, true},
+		{models.RoleApplicationPortfolioManager, ResourceSoftware, ActionCreate, true},
+		{models.RoleApplicationPortfolioManager, ResourceSoftware, ActionUpdate, true},
+		{models.RoleApplicationPortfolioManager, ResourceSoftware, ActionDelete, true},
+		{models.RoleApplicationPortfolioManager, ResourceImports, ActionCreate, true},
+		{models.RoleApplicationPortfolioManager, ResourceClassificationSuggestions, ActionUpdate, true},
+		{models.RoleApplicationPortfolioManager, ResourceUserGroups, ActionRead, true},
+		{models.RoleApplicationPortfolioManager, ResourceHistory, ActionRead, true},
+		{models.RoleApplicationPortfolioManager, ResourceSearch, ActionRead, true},
+		{models.RoleApplicationPortfolioManager, ResourceUserGroups, ActionCreate, false},
+		{models.RoleApplicationPortfolioManager, ResourceUsers, ActionRead, false},
+		{models.RoleApplicationPortfolioManager, ResourceUsers, ActionUpdate, false},
+		{models.RoleApplicationPortfolioManager, ResourceLogs, ActionRead, false},
+		{models.RoleApplicationPortfolioManager, ResourceAccessLogs, ActionRead, false},
+		{models.RoleApplicationPortfolioManager, ResourceOrganizations, ActionRead, false},
+
+		// Stakeholders may not access anything
+		{models.RoleStakeholder, ResourceSoftware, ActionRead, false},
+		{models.RoleStakeholder, ResourceStakeholders, ActionRead, false},
+		{models.RoleStakeholder, ResourceUsers, ActionRead, false},
+		{models.RoleStakeholder, ResourceSearch, ActionRead, false},
+
+		// Undeclared resources and actions are denied
+		{models.RolePlatformAdmin, Resource("unknown"), ActionRead, false},
+		{models.RoleOrganizationAdmin, ResourceSoftware, Action("approve"), false},
+		{models.UserRole("unknown"), ResourceSoftware, ActionRead, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role)+" "+string(tt.action)+" "+string(tt.resource), func(t *testing.T) {
+			if got := DefaultPolicy.Allows(tt.role, tt.resource, tt.action); got != tt.allowed {
+				t.Errorf("Allows(%s, %s, %s) = %t, want %t", tt.role, tt.resource, tt.action, got, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestDefaultPolicyDeniesStakeholders(t *testing.T) {
+	for resource, actions := range DefaultPolicy {
+		for action := range actions {
+			if DefaultPolicy.Allows(models.RoleStakeholder, resource, action) {
+				t.Errorf("stakeholders may %s %s", action, resource)
+			}
+		}
+	}
+}
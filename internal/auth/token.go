@@ -0,0 +1,159 @@
+// Package auth issues and verifies the tokens that authenticate users of the API, and
+// carries the authenticated user through request contexts.
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"apm/internal/models"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims are the claims of an access token; the subject is the user ID
+type Claims struct {
+	OrganizationID string          `json:"org"`
+	Role           models.UserRole `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// NewAccessToken signs an access token for the user with the secret, valid for the ttl
+func NewAccessToken(user models.UserResponse, secret []byte, ttl time.Duration) (string, error) {
+	if len(secret) == 0 {
+		return "", errors.New("no JWT secret configured")
+	}
+
+	now := time.Now()
+	claims := Claims{
+		OrganizationID: user.OrganizationID,
+		Role:           user.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.ID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+}
+
+// ParseAccessToken verifies an access token signed with the secret and returns its claims.
+// Tokens that are expired, not signed with HS256 or lack a subject are rejected.
+func ParseAccessToken(tokenString string, secret []byte) (Claims, error) {
+	if len(secret) == 0 {
+		return Claims{}, errors.New("no JWT secret configured")
+	}
+
+	var claims Claims
+	_, err := jwt.ParseWithClaims(tokenString, &claims, func(*jwt.Token) (interface{}, error) {
+		return secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return Claims{}, err
+	}
+	if claims.Subject == "" {
+		return Claims{}, fmt.Errorf("%w: token has no subject", jwt.ErrTokenInvalidClaims)
+	}
+
+	return claims, nil
+}
+
+// Purposes of the tokens sent to users by email
+const (
+	PurposePasswordReset   = "password_reset"
+	PurposeAccountDeletion = "account_deletion"
+)
+
+// EmailTokenClaims are the claims of a token sent by email; the subject is the user ID. The
+// fingerprint ties the token to the state of the account it was issued for, so that it can
+// only be used once: using it changes the account, and with it the fingerprint.
+type EmailTokenClaims struct {
+	Fingerprint string `json:"fp"`
+	jwt.RegisteredClaims
+}
+
+// NewEmailToken signs a token for the purpose, valid for the ttl. Tokens of each purpose are
+// signed with their own key derived from the secret, so they cannot pass as access tokens or
+// tokens of another purpose.
+func NewEmailToken(purpose, userID, fingerprint string, secret []byte, ttl time.Duration) (string, error) {
+	if len(secret) == 0 {
+		return "", errors.New("no JWT secret configured")
+	}
+
+	now := time.Now()
+	claims := EmailTokenClaims{
+		Fingerprint: fingerprint,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			Audience:  jwt.ClaimStrings{purpose},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(purposeKey(secret, purpose))
+}
+
+// ParseEmailToken verifies a token for the purpose and returns its claims. The caller must
+// still compare the fingerprint with the current one of the account.
+func ParseEmailToken(tokenString, purpose string, secret []byte) (EmailTokenClaims, error) {
+	if len(secret) == 0 {
+		return EmailTokenClaims{}, errors.New("no JWT secret configured")
+	}
+
+	var claims EmailTokenClaims
+	_, err := jwt.ParseWithClaims(tokenString, &claims, func(*jwt.Token) (interface{}, error) {
+		return purposeKey(secret, purpose), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired(), jwt.WithAudience(purpose))
+	if err != nil {
+		return EmailTokenClaims{}, err
+	}
+	if claims.Subject == "" {
+		return EmailTokenClaims{}, fmt.Errorf("%w: token has no subject", jwt.ErrTokenInvalidClaims)
+	}
+
+	return claims, nil
+}
+
+// Fingerprint returns a short hash of values describing the state of an account, such as
+// its password hash, for use in email tokens
+func Fingerprint(values ...string) string {
+	return sha256Hex(strings.Join(values, "\x00"))[:32]
+}
+
+// purposeKey derives the signing key for tokens of a purpose from the secret
+func purposeKey(secret []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+// NewRefreshToken generates a random refresh token, returned with the hash to store for it
+func NewRefreshToken() (token string, hash string, err error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(bytes)
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken returns the hash under which a refresh token is stored
+func HashRefreshToken(token string) string {
+	return sha256Hex(token)
+}
+
+// sha256Hex returns the hex-encoded SHA-256 hash of a value
+func sha256Hex(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
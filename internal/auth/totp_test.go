@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateTOTP(t *testing.T) {
+	// The secret of the test vectors of RFC 6238
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("decoding secret: %v", err)
+	}
+
+	at := time.Unix(1_700_000_000, 0)
+	current := at.Unix() / int64(totpPeriod.Seconds())
+
+	tests := []struct {
+		name     string
+		code     string
+		lastStep int64
+		wantStep int64
+		wantOK   bool
+	}{
+		{"current code", totpCode(key, uint64(current)), 0, current, true},
+		{"previous code within skew", totpCode(key, uint64(current-1)), 0, current - 1, true},
+		{"code outside skew", totpCode(key, uint64(current-2)), 0, 0, false},
+		{"replayed code", totpCode(key, uint64(current)), current, 0, false},
+		{"code before the last step", totpCode(key, uint64(current-1)), current - 1, 0, false},
+		{"code after the last step", totpCode(key, uint64(current)), current - 1, current, true},
+		{"malformed code", "12345", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := ValidateTOTP(secret, tt.code, at, tt.lastStep)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("ValidateTOTP() = (%d, %t), want (%d, %t)", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
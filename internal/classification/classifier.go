@@ -0,0 +1,96 @@
+// Package classification suggests types, categories and vendors for software records.
+//
+// Classifiers are registered in a Registry, which runs them in order and collects
+// their suggestions so that a portfolio manager can accept or reject them.
+package classification
+
+import (
+	"context"
+
+	"apm/internal/models"
+)
+
+// Classifier suggests how a software record should be classified
+type Classifier interface {
+	// Name identifies the classifier in stored suggestions
+	Name() string
+
+	// Classify returns the classifier's suggestions for the software, most confident first;
+	// empty fields mean no suggestion
+	Classify(ctx context.Context, software models.Software) ([]Result, error)
+}
+
+// Result represents a set of suggestions a classifier makes with the same confidence
+type Result struct {
+	SoftwareType    models.SoftwareType
+	SoftwareSubtype string
+	Categories      []string
+	Vendor          string
+
+	// Confidence is a score between 0 and 1; results without confidence are ignored
+	Confidence float64
+}
+
+// Suggestion represents a suggested value for a single field, as produced by a classifier
+type Suggestion struct {
+	Classifier string
+	Field      models.ClassificationField
+	Value      string
+	Confidence float64
+}
+
+// suggestions splits a result into one suggestion per field
+func (r Result) suggestions(classifier string) []Suggestion {
+	if r.Confidence <= 0 {
+		return nil
+	}
+
+	var suggestions []Suggestion
+	add := func(field models.ClassificationField, value string) {
+		if value != "" {
+			suggestions = append(suggestions, Suggestion{
+				Classifier: classifier,
+				Field:      field,
+				Value:      value,
+				Confidence: clampConfidence(r.Confidence),
+			})
+		}
+	}
+
+	add(models.ClassificationFieldSoftwareType, string(r.SoftwareType))
+	add(models.ClassificationFieldSoftwareSubtype, r.SoftwareSubtype)
+	add(models.ClassificationFieldVendor, r.Vendor)
+	for _, category := range r.Categories {
+		add(models.ClassificationFieldFunctionalCategory, category)
+	}
+
+	return suggestions
+}
+
+// fill copies the result into the empty fields of the software, so later classifiers can build on it
+func (r Result) fill(software models.Software) models.Software {
+	if r.Confidence <= 0 {
+		return software
+	}
+	if software.SoftwareType == "" {
+		software.SoftwareType = r.SoftwareType
+	}
+	if software.SoftwareSubtype == "" {
+		software.SoftwareSubtype = r.SoftwareSubtype
+	}
+	if software.Vendor == "" {
+		software.Vendor = r.Vendor
+	}
+	return software
+}
+
+// clampConfidence keeps a confidence score between 0 and 1
+func clampConfidence(confidence float64) float64 {
+	if confidence > 1 {
+		return 1
+	}
+	if confidence < 0 {
+		return 0
+	}
+	return confidence
+}
@@ -0,0 +1,62 @@
+package classification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"apm/internal/models"
+)
+
+// Registry holds the classifiers that are chained to classify software
+type Registry struct {
+	mu          sync.RWMutex
+	classifiers []Classifier
+}
+
+// NewRegistry creates a registry running the given classifiers in order
+func NewRegistry(classifiers ...Classifier) *Registry {
+	return &Registry{classifiers: classifiers}
+}
+
+// Register appends a classifier to the end of the chain
+func (r *Registry) Register(classifier Classifier) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.classifiers = append(r.classifiers, classifier)
+}
+
+// Classifiers returns the registered classifiers in the order they run
+func (r *Registry) Classifiers() []Classifier {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]Classifier(nil), r.classifiers...)
+}
+
+// Classify runs every classifier in order and collects their suggestions. Each classifier sees
+// the software with its empty fields filled in by the classifiers before it. A failing
+// classifier does not stop the chain; its error is returned alongside the other suggestions.
+func (r *Registry) Classify(ctx context.Context, software models.Software) ([]Suggestion, error) {
+	var suggestions []Suggestion
+	var errs []error
+
+	for _, classifier := range r.Classifiers() {
+		if err := ctx.Err(); err != nil {
+			return suggestions, err
+		}
+
+		results, err := classifier.Classify(ctx, software)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("classifier %s: %w", classifier.Name(), err))
+			continue
+		}
+
+		for _, result := range results {
+			suggestions = append(suggestions, result.suggestions(classifier.Name())...)
+			software = result.fill(software)
+		}
+	}
+
+	return suggestions, errors.Join(errs...)
+}
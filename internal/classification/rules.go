@@ -0,0 +1,375 @@
+package classification
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"apm/internal/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Fields of a software record that rules can search
+const (
+	FieldDisplayName = "display_name"
+	FieldDescription = "description"
+	FieldVendor      = "vendor"
+)
+
+// ruleFields lists the fields searched by rules that do not limit their fields
+var ruleFields = []string{FieldDisplayName, FieldDescription, FieldVendor}
+
+const (
+	// defaultRuleConfidence is the confidence of rules that do not set one
+	defaultRuleConfidence = 0.6
+
+	// categoryRuleConfidence is the confidence of the rules seeded from the category list
+	categoryRuleConfidence = 0.5
+
+	// descriptionWeight lowers the confidence of rules matching only the description,
+	// which mentions other products and topics more often than the name does
+	descriptionWeight = 0.75
+)
+
+// Rule maps keywords and regular expressions found in a software record onto a classification
+type Rule struct {
+	Name string `json:"name" yaml:"name"`
+
+	// Keywords are words or phrases matched as whole words, ignoring case
+	Keywords []string `json:"keywords,omitempty" yaml:"keywords,omitempty"`
+	// Patterns are regular expressions, matched ignoring case
+	Patterns []string `json:"patterns,omitempty" yaml:"patterns,omitempty"`
+	// Fields limits the fields searched; all fields are searched when empty
+	Fields []string `json:"fields,omitempty" yaml:"fields,omitempty"`
+
+	SoftwareType    models.SoftwareType `json:"software_type,omitempty" yaml:"software_type,omitempty"`
+	SoftwareSubtype string              `json:"software_subtype,omitempty" yaml:"software_subtype,omitempty"`
+	Categories      []string            `json:"categories,omitempty" yaml:"categories,omitempty"`
+	Vendor          string              `json:"vendor,omitempty" yaml:"vendor,omitempty"`
+	Confidence      float64             `json:"confidence,omitempty" yaml:"confidence,omitempty"`
+}
+
+// RuleSet represents the contents of a rules file
+type RuleSet struct {
+	Rules []Rule `json:"rules" yaml:"rules"`
+}
+
+//go:embed default_rules.yaml
+var defaultRules []byte
+
+// DefaultRules returns the rules built into the application
+func DefaultRules() (RuleSet, error) {
+	return ParseRules(defaultRules, "yaml")
+}
+
+// LoadRules reads a rule set from a JSON or YAML file, depending on its extension
+func LoadRules(path string) (RuleSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return RuleSet{}, fmt.Errorf("failed to read rules file: %w", err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		return ParseRules(data, "json")
+	case ".yaml", ".yml":
+		return ParseRules(data, "yaml")
+	default:
+		return RuleSet{}, fmt.Errorf("unsupported rules file extension %q, expected .json, .yaml or .yml", ext)
+	}
+}
+
+// ParseRules decodes a rule set in the given format, "json" or "yaml". Unknown keys are
+// rejected, so a misspelled key in a rules file does not silently disable part of a rule.
+func ParseRules(data []byte, format string) (RuleSet, error) {
+	var set RuleSet
+
+	switch format {
+	case "json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&set); err != nil {
+			return RuleSet{}, fmt.Errorf("failed to parse JSON rules: %w", err)
+		}
+	case "yaml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&set); err != nil {
+			return RuleSet{}, fmt.Errorf("failed to parse YAML rules: %w", err)
+		}
+	default:
+		return RuleSet{}, fmt.Errorf("unsupported rules format %q", format)
+	}
+
+	return set, nil
+}
+
+// LoadCategories reads the functional categories listed in a file such as docs/categories.md,
+// one per line. Blank lines, markdown headings and the single-letter index lines are skipped.
+func LoadCategories(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open categories file: %w", err)
+	}
+	defer file.Close()
+
+	var categories []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || utf8.RuneCountInString(line) == 1 {
+			continue
+		}
+		categories = append(categories, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read categories file: %w", err)
+	}
+
+	return categories, nil
+}
+
+// abbreviatedCategory matches category names ending in a parenthesised abbreviation
+var abbreviatedCategory = regexp.MustCompile(`^(.+?)\s*\(([^)]+)\)$`)
+
+// CategoryRules creates a rule per category, suggesting the category for software whose name
+// or description mentions it. For names such as "Enterprise Service Bus (ESB)" both the name
+// and the abbreviation are keywords.
+func CategoryRules(categories []string) []Rule {
+	rules := make([]Rule, 0, len(categories))
+	for _, category := range categories {
+		keywords := []string{category}
+		if parts := abbreviatedCategory.FindStringSubmatch(category); parts != nil {
+			keywords = []string{parts[1], parts[2]}
+		}
+
+		rules = append(rules, Rule{
+			Name:       "category: " + category,
+			Keywords:   keywords,
+			Fields:     []string{FieldDisplayName, FieldDescription},
+			Categories: []string{category},
+			Confidence: categoryRuleConfidence,
+		})
+	}
+	return rules
+}
+
+// compiledRule is a rule prepared for matching
+type compiledRule struct {
+	Rule
+	keywords []string
+	patterns []*regexp.Regexp
+	fields   []string
+}
+
+// RuleClassifier classifies software by matching keyword and pattern rules, without
+// depending on any external service
+type RuleClassifier struct {
+	rules []compiledRule
+}
+
+// Ensure implementation satisfies the interface
+var _ Classifier = (*RuleClassifier)(nil)
+
+// NewRuleClassifier compiles rules into a classifier. When categories are given, the
+// categories suggested by the rules must be among them.
+func NewRuleClassifier(rules []Rule, categories []string) (*RuleClassifier, error) {
+	known := make(map[string]string, len(categories))
+	for _, category := range categories {
+		known[strings.ToLower(category)] = category
+	}
+
+	classifier := &RuleClassifier{}
+	for i, rule := range rules {
+		compiled, err := compileRule(rule, known)
+		if err != nil {
+			name := rule.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			return nil, fmt.Errorf("invalid rule %s: %w", name, err)
+		}
+		classifier.rules = append(classifier.rules, compiled)
+	}
+
+	return classifier, nil
+}
+
+// compileRule validates a rule and prepares it for matching
+func compileRule(rule Rule, knownCategories map[string]string) (compiledRule, error) {
+	compiled := compiledRule{Rule: rule, fields: rule.Fields}
+
+	if len(rule.Keywords) == 0 && len(rule.Patterns) == 0 {
+		return compiledRule{}, fmt.Errorf("rule has no keywords or patterns")
+	}
+	if rule.SoftwareType == "" && rule.SoftwareSubtype == "" && len(rule.Categories) == 0 && rule.Vendor == "" {
+		return compiledRule{}, fmt.Errorf("rule suggests nothing")
+	}
+
+	for _, keyword := range rule.Keywords {
+		keyword = strings.ToLower(strings.TrimSpace(keyword))
+		if keyword != "" {
+			compiled.keywords = append(compiled.keywords, keyword)
+		}
+	}
+	for _, pattern := range rule.Patterns {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return compiledRule{}, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		compiled.patterns = append(compiled.patterns, re)
+	}
+
+	if len(compiled.fields) == 0 {
+		compiled.fields = ruleFields
+	}
+	for _, field := range compiled.fields {
+		if field != FieldDisplayName && field != FieldDescription && field != FieldVendor {
+			return compiledRule{}, fmt.Errorf("unknown field %q", field)
+		}
+	}
+
+	switch rule.SoftwareType {
+	case "", models.SoftwareTypeAPI, models.SoftwareTypeWeb, models.SoftwareTypeMobile, models.SoftwareTypeDesktop,
+		models.SoftwareTypeEmbedded, models.SoftwareTypeMiddleware, models.SoftwareTypeLibrary:
+	default:
+		return compiledRule{}, fmt.Errorf("unknown software type %q", rule.SoftwareType)
+	}
+
+	if rule.SoftwareSubtype != "" {
+		subtype, ok := canonicalSubtype(rule.SoftwareSubtype)
+		if !ok {
+			return compiledRule{}, fmt.Errorf("unknown software subtype %q", rule.SoftwareSubtype)
+		}
+		compiled.SoftwareSubtype = subtype
+	}
+
+	if len(knownCategories) > 0 {
+		compiled.Categories = nil
+		for _, category := range rule.Categories {
+			name, ok := knownCategories[strings.ToLower(category)]
+			if !ok {
+				return compiledRule{}, fmt.Errorf("unknown category %q", category)
+			}
+			compiled.Categories = append(compiled.Categories, name)
+		}
+	}
+
+	if rule.Confidence < 0 || rule.Confidence > 1 {
+		return compiledRule{}, fmt.Errorf("confidence must be between 0 and 1")
+	}
+	if compiled.Confidence == 0 {
+		compiled.Confidence = defaultRuleConfidence
+	}
+
+	return compiled, nil
+}
+
+// Name identifies the classifier in stored suggestions
+func (c *RuleClassifier) Name() string {
+	return "rules"
+}
+
+// Classify returns a result for every rule matching the software, most confident first
+func (c *RuleClassifier) Classify(ctx context.Context, software models.Software) ([]Result, error) {
+	texts := map[string]string{
+		FieldDisplayName: strings.ToLower(software.DisplayName),
+		FieldDescription: strings.ToLower(software.Description),
+		FieldVendor:      strings.ToLower(software.Vendor),
+	}
+
+	var results []Result
+	for _, rule := range c.rules {
+		confidence := rule.match(texts)
+		if confidence <= 0 {
+			continue
+		}
+
+		results = append(results, Result{
+			SoftwareType:    rule.SoftwareType,
+			SoftwareSubtype: rule.SoftwareSubtype,
+			Categories:      rule.Categories,
+			Vendor:          rule.Vendor,
+			Confidence:      confidence,
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Confidence > results[j].Confidence
+	})
+
+	return results, nil
+}
+
+// match returns the confidence with which the rule matches the lower-cased texts of a
+// software record, or 0 when it does not match
+func (r compiledRule) match(texts map[string]string) float64 {
+	var weight float64
+	for _, field := range r.fields {
+		if !r.matchText(texts[field]) {
+			continue
+		}
+		if field == FieldDescription {
+			weight = max(weight, descriptionWeight)
+		} else {
+			weight = 1
+		}
+	}
+	return r.Confidence * weight
+}
+
+// matchText reports whether any keyword or pattern of the rule occurs in the text
+func (r compiledRule) matchText(text string) bool {
+	if text == "" {
+		return false
+	}
+	for _, keyword := range r.keywords {
+		if containsWord(text, keyword) {
+			return true
+		}
+	}
+	for _, pattern := range r.patterns {
+		if pattern.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsWord reports whether the word occurs in the text without being part of a longer word
+func containsWord(text, word string) bool {
+	for offset := 0; offset < len(text); {
+		index := strings.Index(text[offset:], word)
+		if index < 0 {
+			return false
+		}
+		start := offset + index
+		end := start + len(word)
+
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			return true
+		}
+
+		_, size := utf8.DecodeRuneInString(text[start:])
+		offset = start + size
+	}
+	return false
+}
+
+// isWordRune reports whether a rune is part of a word
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
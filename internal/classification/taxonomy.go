@@ -0,0 +1,45 @@
+package classification
+
+import "strings"
+
+// Software classes, as distinguished in docs/instructions.md
+const (
+	ApplicationSoftware = "Application Software"
+	SystemSoftware      = "System Software"
+)
+
+// subtypeClasses maps every documented software subtype onto its class
+var subtypeClasses = map[string]string{
+	"Productivity Software":  ApplicationSoftware,
+	"Creative Software":      ApplicationSoftware,
+	"Business Software":      ApplicationSoftware,
+	"Educational Software":   ApplicationSoftware,
+	"Communication Software": ApplicationSoftware,
+	"Entertainment Software": ApplicationSoftware,
+	"Utility Software":       ApplicationSoftware,
+	"Web Browsers":           ApplicationSoftware,
+	"Integrations":           SystemSoftware,
+	"Development Frameworks": SystemSoftware,
+	"Development Libraries":  SystemSoftware,
+	"Drivers":                SystemSoftware,
+	"Operating Systems":      SystemSoftware,
+	"Firmware":               SystemSoftware,
+	"Storage Systems":        SystemSoftware,
+	"Embedded Systems":       SystemSoftware,
+}
+
+// ClassOf returns the class of a software subtype, or an empty string for an unknown subtype
+func ClassOf(subtype string) string {
+	name, _ := canonicalSubtype(subtype)
+	return subtypeClasses[name]
+}
+
+// canonicalSubtype returns the documented spelling of a subtype, if it is known
+func canonicalSubtype(subtype string) (string, bool) {
+	for name := range subtypeClasses {
+		if strings.EqualFold(name, subtype) {
+			return name, true
+		}
+	}
+	return "", false
+}
@@ -8,10 +8,14 @@ import (
 
 // Config holds the application configuration
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Logging  LoggingConfig
-	CORS     CORSConfig
+	Server         ServerConfig
+	Database       DatabaseConfig
+	Logging        LoggingConfig
+	CORS           CORSConfig
+	Classification ClassificationConfig
+	Mail           MailConfig
+	AccessLog      AccessLogConfig
+	Archive        ArchiveConfig
 }
 
 // ServerConfig holds server-specific configuration
@@ -22,6 +26,10 @@ type ServerConfig struct {
 	WriteTimeout int    `mapstructure:"write_timeout"`
 	IdleTimeout  int    `mapstructure:"idle_timeout"`
 	JWTSecret    string `mapstructure:"jwt_secret"`
+	BaseDomain   string `envconfig:"BASE_DOMAIN"` // Organizations are served from subdomains of it, e.g. acme.<base domain>
+
+	AccessTokenTTL  int `envconfig:"ACCESS_TOKEN_TTL" default:"900"`      // 15 minutes in seconds
+	RefreshTokenTTL int `envconfig:"REFRESH_TOKEN_TTL" default:"2592000"` // 30 days in seconds
 }
 
 // DatabaseConfig holds database-specific configuration
@@ -47,6 +55,33 @@ type CORSConfig struct {
 	AllowedHeaders []string `envconfig:"ALLOWED_HEADERS" default:"Content-Type,Authorization"`
 }
 
+// ClassificationConfig holds configuration of the rule-based classifier
+type ClassificationConfig struct {
+	RulesFile      string `envconfig:"RULES_FILE"`                                   // JSON or YAML rules replacing the built-in ones
+	CategoriesFile string `envconfig:"CATEGORIES_FILE" default:"docs/categories.md"` // Category list seeding a rule per category
+}
+
+// MailConfig holds configuration of the emails sent to users
+type MailConfig struct {
+	Driver string `default:"log"`                                       // "log" or "file"
+	Dir    string `default:"tmp/mail"`                                  // Directory the file driver writes emails to
+	From   string `default:"APM <no-reply@localhost>"`                  // Sender of the emails
+	AppURL string `envconfig:"APP_URL" default:"http://localhost:3000"` // Base URL of the UI, for links in emails
+}
+
+// AccessLogConfig holds configuration of the batching of access log writes
+type AccessLogConfig struct {
+	BufferSize    int `envconfig:"BUFFER_SIZE" default:"1000"` // Entries waiting to be written before further ones are dropped
+	BatchSize     int `envconfig:"BATCH_SIZE" default:"100"`   // Entries written at once
+	FlushInterval int `envconfig:"FLUSH_INTERVAL" default:"5"` // Seconds after which waiting entries are written
+}
+
+// ArchiveConfig holds configuration of the purging of archived records
+type ArchiveConfig struct {
+	RetentionDays int `envconfig:"RETENTION_DAYS" default:"90"` // Days archived records are kept for; 0 keeps them forever. Versions of software are never purged.
+	PurgeInterval int `envconfig:"PURGE_INTERVAL" default:"24"` // Hours between purges
+}
+
 // Load loads the application configuration from environment variables
 // using the envconfig library
 func Load() (Config, error) {
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"errors"
+
+	"apm/internal/apperr"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+// Codes of the errors of PostgreSQL caused by the values written rather than by a failure
+const (
+	codeNotNullViolation    = "23502"
+	codeForeignKeyViolation = "23503"
+	codeUniqueViolation     = "23505"
+	codeCheckViolation      = "23514"
+	codeStringTooLong       = "22001"
+	codeNumericOutOfRange   = "22003"
+	codeInvalidDatetime     = "22007"
+	codeDatetimeOutOfRange  = "22008"
+	codeInvalidText         = "22P02"
+)
+
+// dbError converts an error of the database caused by the request into an error of its kind,
+// keeping it as the cause: a missing row is not found, a duplicate or dangling reference a
+// conflict and a value the schema rejects invalid. Other errors are failures and kept as they are.
+func dbError(err error) error {
+	var typed *apperr.Error
+	if err == nil || errors.As(err, &typed) {
+		return err
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperr.Wrap(apperr.KindNotFound, "record not found", err)
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case codeUniqueViolation:
+		return apperr.Wrap(apperr.KindConflict, "record already exists", err)
+	case codeForeignKeyViolation:
+		return apperr.Wrap(apperr.KindConflict, "record refers to a missing record or is referred to by others", err)
+	case codeNotNullViolation, codeCheckViolation, codeStringTooLong, codeNumericOutOfRange,
+		codeInvalidDatetime, codeDatetimeOutOfRange, codeInvalidText:
+		return apperr.Wrap(apperr.KindValidation, "invalid value", err)
+	}
+	return err
+}
@@ -2,8 +2,10 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"apm/internal/models"
+	"apm/internal/pagination"
 )
 
 // UserRepository defines the interface for user-related database operations
@@ -11,18 +13,54 @@ type UserRepository interface {
 	Create(ctx context.Context, user models.User) (models.User, error)
 	GetByID(ctx context.Context, id string) (models.User, error)
 	GetByEmail(ctx context.Context, email string) (models.User, error)
-	List(ctx context.Context, limit, offset int) ([]models.User, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.User], error)
 	Update(ctx context.Context, user models.User) error
 	Delete(ctx context.Context, id string) error
+	CountAll(ctx context.Context, organizationID string) (int, error)
+	CountByRole(ctx context.Context, organizationID string) (map[models.UserRole]int, error)
+	UseMFAStep(ctx context.Context, id string, step int64) (bool, error)
+	RecordMFAFailure(ctx context.Context, id string, maxFailures int, lockedUntil time.Time) error
+	ResetMFAFailures(ctx context.Context, id string) error
+}
+
+// OrganizationRepository defines the interface for organization-related database operations
+type OrganizationRepository interface {
+	Create(ctx context.Context, organization models.Organization) (models.Organization, error)
+	GetByID(ctx context.Context, id string) (models.Organization, error)
+	GetBySubdomain(ctx context.Context, subdomain string) (models.Organization, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.Organization], error)
+	Update(ctx context.Context, organization models.Organization) error
+	Delete(ctx context.Context, id string) error
+}
+
+// RefreshTokenRepository defines the interface for refresh token-related database operations
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token models.RefreshToken) (models.RefreshToken, error)
+	GetByHash(ctx context.Context, tokenHash string) (models.RefreshToken, error)
+	Revoke(ctx context.Context, id string) (bool, error)
+	RevokeSession(ctx context.Context, sessionID string) error
+	RevokeAllForUser(ctx context.Context, userID string) error
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
+
+// MFARecoveryCodeRepository defines the interface for MFA recovery code-related database operations
+type MFARecoveryCodeRepository interface {
+	ReplaceForUser(ctx context.Context, userID string, codeHashes []string) error
+	Use(ctx context.Context, userID, codeHash string) (bool, error)
+	DeleteForUser(ctx context.Context, userID string) error
 }
 
 // UserGroupRepository defines the interface for user group-related database operations
 type UserGroupRepository interface {
 	Create(ctx context.Context, group models.UserGroup) (models.UserGroup, error)
 	GetByID(ctx context.Context, id string) (models.UserGroup, error)
-	List(ctx context.Context, limit, offset int) ([]models.UserGroup, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.UserGroup], error)
 	Update(ctx context.Context, group models.UserGroup) error
 	Delete(ctx context.Context, id string) error
+	AddMember(ctx context.Context, groupID, userID string) error
+	RemoveMember(ctx context.Context, groupID, userID string) (bool, error)
+	ListMembers(ctx context.Context, groupID string, page pagination.Request) (pagination.Page[models.User], error)
+	ListByUser(ctx context.Context, userID string) ([]models.UserGroup, error)
 }
 
 // StakeholderRepository defines the interface for stakeholder-related database operations
@@ -30,34 +68,47 @@ type StakeholderRepository interface {
 	Create(ctx context.Context, stakeholder models.Stakeholder) (models.Stakeholder, error)
 	GetByID(ctx context.Context, id string) (models.Stakeholder, error)
 	GetByUserID(ctx context.Context, userID string) ([]models.Stakeholder, error)
-	List(ctx context.Context, limit, offset int) ([]models.Stakeholder, error)
+	GetBySoftwareID(ctx context.Context, softwareID string) ([]models.Stakeholder, error)
+	CountBySoftwareAndRole(ctx context.Context, softwareID string, role models.StakeholderRole) (int, error)
+	CountSoleBusinessOwnerships(ctx context.Context, userID string) (int, error)
+	List(ctx context.Context, includeArchived bool, page pagination.Request) (pagination.Page[models.Stakeholder], error)
 	Update(ctx context.Context, stakeholder models.Stakeholder) error
 	Delete(ctx context.Context, id string) error
+	PurgeArchived(ctx context.Context, before time.Time) (int64, error)
 }
 
 // EntityRepository defines the interface for entity-related database operations
 type EntityRepository interface {
 	Create(ctx context.Context, entity models.Entity) (models.Entity, error)
 	GetByID(ctx context.Context, id string) (models.Entity, error)
-	List(ctx context.Context, limit, offset int) ([]models.Entity, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.Entity], error)
 	Update(ctx context.Context, entity models.Entity) error
 	Delete(ctx context.Context, id string) error
 }
 
 // SoftwareRepository defines the interface for software-related database operations
 type SoftwareRepository interface {
-	Create(ctx context.Context, software models.Software) (models.Software, error)
+	Create(ctx context.Context, software models.Software, stakeholders ...models.Stakeholder) (models.Software, error)
 	GetByID(ctx context.Context, id string) (models.Software, error)
-	List(ctx context.Context, limit, offset int) ([]models.Software, error)
+	GetByIDIncludingArchived(ctx context.Context, id string) (models.Software, error)
+	List(ctx context.Context, filter models.SoftwareFilter, page pagination.Request) (pagination.Page[models.Software], error)
+	FindDuplicates(ctx context.Context, foreignKey, displayName, vendor string, minSimilarity float64) ([]models.SoftwareMatch, error)
 	Update(ctx context.Context, software models.Software) error
 	Delete(ctx context.Context, id string) error
+	Undelete(ctx context.Context, id string) (models.Software, error)
+	Restore(ctx context.Context, software models.Software) (models.Software, error)
+	PurgeArchived(ctx context.Context, before time.Time) (int64, error)
+	ListVersions(ctx context.Context, softwareID string, page pagination.Request) (pagination.Page[models.SoftwareVersion], error)
+	GetVersion(ctx context.Context, softwareID string, version int) (models.SoftwareVersion, error)
+	GetVersionAt(ctx context.Context, softwareID string, at time.Time) (models.SoftwareVersion, error)
+	GetLatestVersion(ctx context.Context, softwareID string) (models.SoftwareVersion, error)
 }
 
 // FunctionalCategoryRepository defines the interface for functional category-related database operations
 type FunctionalCategoryRepository interface {
 	Create(ctx context.Context, category models.FunctionalCategory) (models.FunctionalCategory, error)
 	GetByID(ctx context.Context, id string) (models.FunctionalCategory, error)
-	List(ctx context.Context, limit, offset int) ([]models.FunctionalCategory, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.FunctionalCategory], error)
 	Update(ctx context.Context, category models.FunctionalCategory) error
 	Delete(ctx context.Context, id string) error
 }
@@ -66,7 +117,7 @@ type FunctionalCategoryRepository interface {
 type SoftwareGroupRepository interface {
 	Create(ctx context.Context, group models.SoftwareGroup) (models.SoftwareGroup, error)
 	GetByID(ctx context.Context, id string) (models.SoftwareGroup, error)
-	List(ctx context.Context, limit, offset int) ([]models.SoftwareGroup, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.SoftwareGroup], error)
 	Update(ctx context.Context, group models.SoftwareGroup) error
 	Delete(ctx context.Context, id string) error
 }
@@ -75,7 +126,7 @@ type SoftwareGroupRepository interface {
 type StatusRepository interface {
 	Create(ctx context.Context, status models.Status) (models.Status, error)
 	GetByID(ctx context.Context, id string) (models.Status, error)
-	List(ctx context.Context, limit, offset int) ([]models.Status, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.Status], error)
 	Update(ctx context.Context, status models.Status) error
 	Delete(ctx context.Context, id string) error
 }
@@ -84,7 +135,7 @@ type StatusRepository interface {
 type StatusLogRepository interface {
 	Create(ctx context.Context, log models.StatusLog) (models.StatusLog, error)
 	GetByID(ctx context.Context, id string) (models.StatusLog, error)
-	List(ctx context.Context, limit, offset int) ([]models.StatusLog, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.StatusLog], error)
 	Update(ctx context.Context, log models.StatusLog) error
 	Delete(ctx context.Context, id string) error
 }
@@ -93,7 +144,7 @@ type StatusLogRepository interface {
 type RankRepository interface {
 	Create(ctx context.Context, rank models.Rank) (models.Rank, error)
 	GetByID(ctx context.Context, id string) (models.Rank, error)
-	List(ctx context.Context, limit, offset int) ([]models.Rank, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.Rank], error)
 	Update(ctx context.Context, rank models.Rank) error
 	Delete(ctx context.Context, id string) error
 }
@@ -102,7 +153,7 @@ type RankRepository interface {
 type NewsArticleRepository interface {
 	Create(ctx context.Context, news models.NewsArticle) (models.NewsArticle, error)
 	GetByID(ctx context.Context, id string) (models.NewsArticle, error)
-	List(ctx context.Context, limit, offset int) ([]models.NewsArticle, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.NewsArticle], error)
 	Update(ctx context.Context, news models.NewsArticle) error
 	Delete(ctx context.Context, id string) error
 }
@@ -111,7 +162,7 @@ type NewsArticleRepository interface {
 type MediaRepository interface {
 	Create(ctx context.Context, media models.Media) (models.Media, error)
 	GetByID(ctx context.Context, id string) (models.Media, error)
-	List(ctx context.Context, limit, offset int) ([]models.Media, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.Media], error)
 	Update(ctx context.Context, media models.Media) error
 	Delete(ctx context.Context, id string) error
 }
@@ -121,7 +172,7 @@ type ProductDocumentationRepository interface {
 	Create(ctx context.Context, doc models.ProductDocumentation) (models.ProductDocumentation, error)
 	GetByID(ctx context.Context, id string) (models.ProductDocumentation, error)
 	GetByForeignKey(ctx context.Context, foreignKey string) ([]models.ProductDocumentation, error)
-	List(ctx context.Context, limit, offset int) ([]models.ProductDocumentation, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.ProductDocumentation], error)
 	Update(ctx context.Context, doc models.ProductDocumentation) error
 	Delete(ctx context.Context, id string) error
 }
@@ -130,6 +181,51 @@ type ProductDocumentationRepository interface {
 type LogRepository interface {
 	Create(ctx context.Context, log models.Log) (models.Log, error)
 	GetByID(ctx context.Context, id string) (models.Log, error)
-	List(ctx context.Context, limit, offset int) ([]models.Log, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.Log], error)
+	Delete(ctx context.Context, id string) error
+}
+
+// EditLogRepository defines the interface for audit trail-related database operations
+type EditLogRepository interface {
+	Create(ctx context.Context, logs []models.EditLog) error
+	ListByEntity(ctx context.Context, entityType, entityID string, page pagination.Request) (pagination.Page[models.EditLog], error)
+	ListByUser(ctx context.Context, userID string, page pagination.Request) (pagination.Page[models.EditLog], error)
+}
+
+// AccessLogRepository defines the interface for access log-related database operations
+type AccessLogRepository interface {
+	CreateBatch(ctx context.Context, logs []models.AccessLog) error
+	List(ctx context.Context, filter models.AccessLogFilter, page pagination.Request) (pagination.Page[models.AccessLog], error)
+}
+
+// SearchRepository defines the interface for searching records by their text
+type SearchRepository interface {
+	Search(ctx context.Context, query models.SearchQuery, page pagination.Request) (models.SearchResults, error)
+}
+
+// ImportJobRepository defines the interface for import job-related database operations
+type ImportJobRepository interface {
+	Create(ctx context.Context, job models.ImportJob) (models.ImportJob, error)
+	GetByID(ctx context.Context, id string) (models.ImportJob, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.ImportJob], error)
+	Update(ctx context.Context, job models.ImportJob) error
+}
+
+// ImportMappingProfileRepository defines the interface for import mapping profile-related database operations
+type ImportMappingProfileRepository interface {
+	Create(ctx context.Context, profile models.ImportMappingProfile) (models.ImportMappingProfile, error)
+	GetByID(ctx context.Context, id string) (models.ImportMappingProfile, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.ImportMappingProfile], error)
+	Update(ctx context.Context, profile models.ImportMappingProfile) error
 	Delete(ctx context.Context, id string) error
 }
+
+// ClassificationSuggestionRepository defines the interface for classification suggestion-related database operations
+type ClassificationSuggestionRepository interface {
+	Create(ctx context.Context, suggestion models.ClassificationSuggestion) (models.ClassificationSuggestion, error)
+	GetByID(ctx context.Context, id string) (models.ClassificationSuggestion, error)
+	ListBySoftware(ctx context.Context, softwareID string) ([]models.ClassificationSuggestion, error)
+	ListByStatus(ctx context.Context, status models.SuggestionStatus, page pagination.Request) (pagination.Page[models.ClassificationSuggestion], error)
+	Update(ctx context.Context, suggestion models.ClassificationSuggestion) error
+	DeletePending(ctx context.Context, softwareID string) error
+}
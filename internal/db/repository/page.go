@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+	"time"
+
+	"apm/internal/pagination"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// sortKey is a key of the order of a list: an expression rows are sorted by, which must never be
+// NULL so that rows can be compared with it, and its direction
+type sortKey struct {
+	expr       string
+	descending bool
+}
+
+// pageQuery is a query for a page of the rows of a table matching conditions. Its order must end
+// with a unique key; keys returns the values of the sort keys of an item, in the text form they
+// are compared with in SQL.
+type pageQuery[T any] struct {
+	columns string
+	from    string
+	conds   *conditions
+	order   []sortKey
+	keys    func(T) []string
+	scan    func(pgx.Row) (T, error)
+}
+
+// listPage retrieves a page of a list, at the offset of the request or next to its cursor. A row
+// beyond the page is fetched to learn whether there is a page after it.
+func listPage[T any](ctx context.Context, pool *pgxpool.Pool, q pageQuery[T], req pagination.Request) (pagination.Page[T], error) {
+	req = req.Normalized()
+	order := orderSignature(q.order)
+
+	var page pagination.Page[T]
+	if req.IncludeTotal {
+		var total int
+		query := `SELECT COUNT(*) FROM ` + q.from + q.conds.where()
+		if err := pool.QueryRow(ctx, query, q.conds.args...).Scan(&total); err != nil {
+			return page, fmt.Errorf("failed to count rows: %w", dbError(err))
+		}
+		page.Total = &total
+	}
+
+	backward := false
+	if cursor := req.Cursor; cursor != nil {
+		if cursor.Order != order || len(cursor.Keys) != len(q.order) {
+			return page, fmt.Errorf("%w: it belongs to another list or order", pagination.ErrInvalidCursor)
+		}
+		backward = cursor.Backward
+		clause, args := seekCondition(q.order, cursor.Keys, backward)
+		q.conds.add(clause, args...)
+	}
+
+	// Going backward, rows are read in reverse from the cursor and put back in order afterwards
+	var orderBy []string
+	for _, key := range q.order {
+		if key.descending != backward {
+			orderBy = append(orderBy, key.expr+" DESC")
+		} else {
+			orderBy = append(orderBy, key.expr)
+		}
+	}
+	limit := q.conds.bind(` LIMIT ? OFFSET ?`, req.Limit+1, req.Offset)
+
+	query := `SELECT ` + q.columns + ` FROM ` + q.from + q.conds.where() + ` ORDER BY ` + strings.Join(orderBy, ", ") + limit
+	rows, err := pool.Query(ctx, query, q.conds.args...)
+	if err != nil {
+		return page, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := q.scan(rows)
+		if err != nil {
+			return page, fmt.Errorf("failed to scan row: %w", dbError(err))
+		}
+		page.Items = append(page.Items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return page, fmt.Errorf("error during rows iteration: %w", dbError(err))
+	}
+
+	more := len(page.Items) > req.Limit
+	if more {
+		page.Items = page.Items[:req.Limit]
+	}
+	if backward {
+		slices.Reverse(page.Items)
+	}
+
+	// An empty page next to a cursor still leads back to the rows on the other side of it
+	first, last := []string(nil), []string(nil)
+	if len(page.Items) > 0 {
+		first, last = q.keys(page.Items[0]), q.keys(page.Items[len(page.Items)-1])
+	} else if req.Cursor != nil {
+		first, last = req.Cursor.Keys, req.Cursor.Keys
+	}
+
+	cursor := func(keys []string, backward bool) string {
+		if keys == nil {
+			return ""
+		}
+		return pagination.Cursor{Order: order, Keys: keys, Backward: backward}.Encode()
+	}
+	if backward {
+		page.NextCursor = cursor(last, false)
+		if more {
+			page.PrevCursor = cursor(first, true)
+		}
+	} else {
+		if more {
+			page.NextCursor = cursor(last, false)
+		}
+		if req.Cursor != nil || req.Offset > 0 {
+			page.PrevCursor = cursor(first, true)
+		}
+	}
+
+	return page, nil
+}
+
+// seekCondition returns the condition selecting the rows after the sort key values in the
+// order, or before them going backward, with an argument for each value it compares
+func seekCondition(order []sortKey, keys []string, backward bool) (string, []interface{}) {
+	past := func(key sortKey) string {
+		if key.descending != backward {
+			return " < "
+		}
+		return " > "
+	}
+
+	// Keys sorted in the same direction compare as a row, which indexes on them can serve
+	uniform := true
+	for _, key := range order {
+		uniform = uniform && key.descending == order[0].descending
+	}
+	if uniform {
+		exprs := make([]string, len(order))
+		args := make([]interface{}, len(order))
+		for i, key := range order {
+			exprs[i] = key.expr
+			args[i] = keys[i]
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(order)), ", ")
+		return "(" + strings.Join(exprs, ", ") + ")" + past(order[0]) + "(" + placeholders + ")", args
+	}
+
+	// Otherwise a row comes after the values when it equals them up to a key and is past it there
+	var alternatives []string
+	var args []interface{}
+	for i, key := range order {
+		var parts []string
+		for j, previous := range order[:i] {
+			parts = append(parts, previous.expr+" = ?")
+			args = append(args, keys[j])
+		}
+		parts = append(parts, key.expr+past(key)+"?")
+		args = append(args, keys[i])
+		alternatives = append(alternatives, "("+strings.Join(parts, " AND ")+")")
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
+
+// orderSignature identifies an order, so that cursors are only used with the order they were
+// made for
+func orderSignature(order []sortKey) string {
+	hash := fnv.New32a()
+	for _, key := range order {
+		fmt.Fprintf(hash, "%s %t;", key.expr, key.descending)
+	}
+	return fmt.Sprintf("%08x", hash.Sum32())
+}
+
+// timeKey returns the value of a time sort key, precise enough to compare equal to the stored time
+func timeKey(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"apm/internal/pagination"
+)
+
+func TestSeekCondition(t *testing.T) {
+	byName := []sortKey{{expr: "name"}, {expr: "id"}}
+	byNewest := []sortKey{{expr: "created_at", descending: true}, {expr: "id", descending: true}}
+	mixed := []sortKey{{expr: "name"}, {expr: "created_at", descending: true}, {expr: "id"}}
+
+	tests := []struct {
+		name     string
+		order    []sortKey
+		keys     []string
+		backward bool
+		want     string
+		args     []interface{}
+	}{
+		{
+			"ascending", byName, []string{"Ledger", "7"}, false,
+			"(name, id) > ($2, $3)",
+			[]interface{}{"Ledger", "7"},
+		},
+		{
+			"ascending backward", byName, []string{"Ledger", "7"}, true,
+			"(name, id) < ($2, $3)",
+			[]interface{}{"Ledger", "7"},
+		},
+		{
+			"descending", byNewest, []string{"2024-01-02T00:00:00Z", "7"}, false,
+			"(created_at, id) < ($2, $3)",
+			[]interface{}{"2024-01-02T00:00:00Z", "7"},
+		},
+		{
+			"descending backward", byNewest, []string{"2024-01-02T00:00:00Z", "7"}, true,
+			"(created_at, id) > ($2, $3)",
+			[]interface{}{"2024-01-02T00:00:00Z", "7"},
+		},
+		{
+			"mixed", mixed, []string{"Ledger", "2024-01-02T00:00:00Z", "7"}, false,
+			"((name > $2) OR (name = $3 AND created_at < $4) OR (name = $5 AND created_at = $6 AND id > $7))",
+			[]interface{}{"Ledger", "Ledger", "2024-01-02T00:00:00Z", "Ledger", "2024-01-02T00:00:00Z", "7"},
+		},
+		{
+			"mixed backward", mixed, []string{"Ledger", "2024-01-02T00:00:00Z", "7"}, true,
+			"((name < $2) OR (name = $3 AND created_at > $4) OR (name = $5 AND created_at = $6 AND id < $7))",
+			[]interface{}{"Ledger", "Ledger", "2024-01-02T00:00:00Z", "Ledger", "2024-01-02T00:00:00Z", "7"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The condition follows another, as in listPage, so its placeholders are numbered after it
+			conds := newConditions("organization_id = ?", "org-a")
+			clause, args := seekCondition(tt.order, tt.keys, tt.backward)
+			conds.add(clause, args...)
+
+			if got := conds.clauses[1]; got != tt.want {
+				t.Errorf("condition = %s\nwant %s", got, tt.want)
+			}
+			if got := conds.args[1:]; !reflect.DeepEqual(got, tt.args) {
+				t.Errorf("arguments = %v, want %v", got, tt.args)
+			}
+		})
+	}
+}
+
+func TestOrderSignature(t *testing.T) {
+	order := []sortKey{{expr: "name"}, {expr: "id"}}
+	signature := orderSignature(order)
+
+	if got := orderSignature([]sortKey{{expr: "name"}, {expr: "id"}}); got != signature {
+		t.Errorf("signature of the same order = %s, want %s", got, signature)
+	}
+
+	others := map[string][]sortKey{
+		"other direction":  {{expr: "name", descending: true}, {expr: "id"}},
+		"other expression": {{expr: "vendor"}, {expr: "id"}},
+		"other key order":  {{expr: "id"}, {expr: "name"}},
+		"fewer keys":       {{expr: "id"}},
+	}
+	for name, other := range others {
+		if got := orderSignature(other); got == signature {
+			t.Errorf("signature of %s = %s, same as the order", name, got)
+		}
+	}
+}
+
+func TestListPageRejectsCursorOfAnotherOrder(t *testing.T) {
+	order := []sortKey{{expr: "name"}, {expr: "id"}}
+
+	tests := []struct {
+		name   string
+		cursor pagination.Cursor
+	}{
+		{"other order", pagination.Cursor{Order: orderSignature([]sortKey{{expr: "name", descending: true}, {expr: "id"}}), Keys: []string{"Ledger", "7"}}},
+		{"too few keys", pagination.Cursor{Order: orderSignature(order), Keys: []string{"7"}}},
+		{"too many keys", pagination.Cursor{Order: orderSignature(order), Keys: []string{"Ledger", "7", "8"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The cursor is rejected before any query is made, so no pool is needed
+			q := pageQuery[string]{from: "software", conds: newConditions("organization_id = ?", "org-a"), order: order}
+			_, err := listPage(context.Background(), nil, q, pagination.Request{Cursor: &tt.cursor})
+			if !errors.Is(err, pagination.ErrInvalidCursor) {
+				t.Errorf("listPage() = %v, want %v", err, pagination.ErrInvalidCursor)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"apm/internal/models"
+	"apm/internal/pagination"
+	"apm/internal/tenant"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Ensure implementation satisfies the interface
+var _ AccessLogRepository = (*PostgresAccessLogRepository)(nil)
+
+// PostgresAccessLogRepository implements AccessLogRepository using PostgreSQL. Access logs
+// are written in the background, outside of any request, so they carry their organization
+// themselves; they are listed for the organization in the context.
+type PostgresAccessLogRepository struct {
+	pool   *pgxpool.Pool
+	logger *log.Logger
+}
+
+// NewPostgresAccessLogRepository creates a new PostgreSQL access log repository
+func NewPostgresAccessLogRepository(pool *pgxpool.Pool) AccessLogRepository {
+	return &PostgresAccessLogRepository{
+		pool:   pool,
+		logger: log.New(log.Writer(), "[AccessLogRepo] ", log.LstdFlags),
+	}
+}
+
+// accessLogColumns lists the columns read for an access log
+const accessLogColumns = `
+	id, COALESCE(organization_id::text, ''), COALESCE(user_id::text, ''), COALESCE(role::text, ''),
+	event, COALESCE(email, ''), success, ip_address, COALESCE(user_agent, ''), COALESCE(method, ''),
+	COALESCE(path, ''), COALESCE(status, 0), timestamp
+`
+
+// CreateBatch inserts access logs into the database in a single round trip
+func (r *PostgresAccessLogRepository) CreateBatch(ctx context.Context, logs []models.AccessLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO access_logs (
+			id, organization_id, user_id, role, event, email, success, ip_address, user_agent,
+			method, path, status, timestamp
+		) VALUES (
+			$1, NULLIF($2, '')::uuid, NULLIF($3, '')::uuid, NULLIF($4, '')::user_role, $5, NULLIF($6, ''), $7, $8,
+			NULLIF($9, ''), NULLIF($10, ''), NULLIF($11, ''), NULLIF($12, 0), $13
+		)
+	`
+
+	batch := &pgx.Batch{}
+	for _, entry := range logs {
+		batch.Queue(query,
+			generateID(), entry.OrganizationID, entry.UserID, string(entry.Role), entry.Event, entry.Email,
+			entry.Success, entry.IPAddress, entry.UserAgent, entry.Method, entry.Path, entry.Status, entry.Timestamp,
+		)
+	}
+
+	results := r.pool.SendBatch(ctx, batch)
+	defer results.Close()
+
+	for range logs {
+		if _, err := results.Exec(); err != nil {
+			return fmt.Errorf("failed to create access logs: %w", dbError(err))
+		}
+	}
+
+	return nil
+}
+
+// List retrieves a page of the access history matching the filter, newest first
+func (r *PostgresAccessLogRepository) List(ctx context.Context, filter models.AccessLogFilter, page pagination.Request) (pagination.Page[models.AccessLog], error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return pagination.Page[models.AccessLog]{}, fmt.Errorf("failed to list access logs: %w", dbError(err))
+	}
+
+	conds := newConditions(`organization_id = ?`, organizationID)
+	if filter.UserID != "" {
+		conds.add(`user_id = ?`, filter.UserID)
+	}
+	if filter.IPAddress != "" {
+		conds.add(`ip_address = ?`, filter.IPAddress)
+	}
+	if filter.From != nil {
+		conds.add(`timestamp >= ?`, *filter.From)
+	}
+	if filter.To != nil {
+		conds.add(`timestamp < ?`, *filter.To)
+	}
+
+	query := pageQuery[models.AccessLog]{
+		columns: accessLogColumns,
+		from:    "access_logs",
+		conds:   conds,
+		order:   []sortKey{{expr: "timestamp", descending: true}, {expr: "id", descending: true}},
+		keys: func(entry models.AccessLog) []string {
+			return []string{timeKey(entry.Timestamp), entry.ID}
+		},
+		scan: scanAccessLog,
+	}
+
+	logs, err := listPage(ctx, r.pool, query, page)
+	if err != nil {
+		return pagination.Page[models.AccessLog]{}, fmt.Errorf("failed to list access logs: %w", dbError(err))
+	}
+
+	return logs, nil
+}
+
+// scanAccessLog scans the accessLogColumns of a row
+func scanAccessLog(row pgx.Row) (models.AccessLog, error) {
+	var entry models.AccessLog
+	err := row.Scan(
+		&entry.ID, &entry.OrganizationID, &entry.UserID, &entry.Role, &entry.Event, &entry.Email,
+		&entry.Success, &entry.IPAddress, &entry.UserAgent, &entry.Method, &entry.Path, &entry.Status,
+		&entry.Timestamp,
+	)
+	return entry, err
+}
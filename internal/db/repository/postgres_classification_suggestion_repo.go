@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"apm/internal/models"
+	"apm/internal/pagination"
+	"apm/internal/tenant"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Ensure implementation satisfies the interface
+var _ ClassificationSuggestionRepository = (*PostgresClassificationSuggestionRepository)(nil)
+
+// PostgresClassificationSuggestionRepository implements ClassificationSuggestionRepository using
+// PostgreSQL. All methods act on the suggestions of the organization in the context.
+type PostgresClassificationSuggestionRepository struct {
+	pool   *pgxpool.Pool
+	logger *log.Logger
+}
+
+// NewPostgresClassificationSuggestionRepository creates a new PostgreSQL classification suggestion repository
+func NewPostgresClassificationSuggestionRepository(pool *pgxpool.Pool) ClassificationSuggestionRepository {
+	return &PostgresClassificationSuggestionRepository{
+		pool:   pool,
+		logger: log.New(log.Writer(), "[ClassificationSuggestionRepo] ", log.LstdFlags),
+	}
+}
+
+// classificationSuggestionColumns lists the columns read for a classification suggestion
+const classificationSuggestionColumns = `
+	id, software_id, classifier, field, value, confidence, status,
+	COALESCE(reviewed_by, ''), reviewed_at, created_at, updated_at
+`
+
+// Create inserts a new classification suggestion into the database
+func (r *PostgresClassificationSuggestionRepository) Create(ctx context.Context, suggestion models.ClassificationSuggestion) (models.ClassificationSuggestion, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return models.ClassificationSuggestion{}, fmt.Errorf("failed to create classification suggestion: %w", dbError(err))
+	}
+
+	// Generate a new ID if not provided
+	if suggestion.ID == "" {
+		suggestion.ID = generateID()
+	}
+	if suggestion.Status == "" {
+		suggestion.Status = models.SuggestionPending
+	}
+
+	// Set timestamps
+	now := time.Now().UTC()
+	suggestion.CreatedAt = now
+	suggestion.UpdatedAt = now
+
+	query := `
+		INSERT INTO classification_suggestions (
+			id, software_id, classifier, field, value, confidence, status,
+			reviewed_by, reviewed_at, created_at, updated_at, organization_id
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9, $10, $11, $12
+		)
+	`
+
+	_, err = r.pool.Exec(ctx, query,
+		suggestion.ID, suggestion.SoftwareID, suggestion.Classifier, suggestion.Field,
+		suggestion.Value, suggestion.Confidence, suggestion.Status, suggestion.ReviewedBy,
+		suggestion.ReviewedAt, suggestion.CreatedAt, suggestion.UpdatedAt, organizationID,
+	)
+	if err != nil {
+		return models.ClassificationSuggestion{}, fmt.Errorf("failed to create classification suggestion: %w", dbError(err))
+	}
+
+	return suggestion, nil
+}
+
+// GetByID retrieves a classification suggestion by its ID
+func (r *PostgresClassificationSuggestionRepository) GetByID(ctx context.Context, id string) (models.ClassificationSuggestion, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return models.ClassificationSuggestion{}, fmt.Errorf("failed to get classification suggestion by ID: %w", dbError(err))
+	}
+
+	query := `
+		SELECT ` + classificationSuggestionColumns + ` FROM classification_suggestions
+		WHERE id = $1 AND organization_id = $2
+	`
+	row := r.pool.QueryRow(ctx, query, id, organizationID)
+
+	suggestion, err := scanClassificationSuggestion(row)
+	if err != nil {
+		return models.ClassificationSuggestion{}, fmt.Errorf("failed to get classification suggestion by ID: %w", dbError(err))
+	}
+
+	return suggestion, nil
+}
+
+// ListBySoftware retrieves all suggestions made for a software record, most confident first
+func (r *PostgresClassificationSuggestionRepository) ListBySoftware(ctx context.Context, softwareID string) ([]models.ClassificationSuggestion, error) {
+	query := `
+		SELECT ` + classificationSuggestionColumns + ` FROM classification_suggestions
+		WHERE software_id = $1 AND organization_id = $2
+		ORDER BY field, confidence DESC, created_at
+	`
+	return r.list(ctx, query, softwareID)
+}
+
+// ListByStatus retrieves a page of suggestions in a given review state, oldest first
+func (r *PostgresClassificationSuggestionRepository) ListByStatus(ctx context.Context, status models.SuggestionStatus, page pagination.Request) (pagination.Page[models.ClassificationSuggestion], error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return pagination.Page[models.ClassificationSuggestion]{}, fmt.Errorf("failed to list classification suggestions: %w", dbError(err))
+	}
+
+	conds := newConditions(`organization_id = ?`, organizationID)
+	conds.add(`status = ?`, status)
+
+	query := pageQuery[models.ClassificationSuggestion]{
+		columns: classificationSuggestionColumns,
+		from:    "classification_suggestions",
+		conds:   conds,
+		order:   []sortKey{{expr: "created_at"}, {expr: "software_id"}, {expr: "field"}, {expr: "id"}},
+		keys: func(suggestion models.ClassificationSuggestion) []string {
+			return []string{timeKey(suggestion.CreatedAt), suggestion.SoftwareID, string(suggestion.Field), suggestion.ID}
+		},
+		scan: scanClassificationSuggestion,
+	}
+
+	suggestions, err := listPage(ctx, r.pool, query, page)
+	if err != nil {
+		return pagination.Page[models.ClassificationSuggestion]{}, fmt.Errorf("failed to list classification suggestions: %w", dbError(err))
+	}
+
+	return suggestions, nil
+}
+
+// Update stores the review state of an existing classification suggestion
+func (r *PostgresClassificationSuggestionRepository) Update(ctx context.Context, suggestion models.ClassificationSuggestion) error {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update classification suggestion: %w", dbError(err))
+	}
+
+	// Update the UpdatedAt timestamp
+	suggestion.UpdatedAt = time.Now().UTC()
+
+	query := `
+		UPDATE classification_suggestions SET
+			status = $2,
+			reviewed_by = NULLIF($3, ''),
+			reviewed_at = $4,
+			updated_at = $5
+		WHERE id = $1 AND organization_id = $6
+	`
+
+	_, err = r.pool.Exec(ctx, query,
+		suggestion.ID, suggestion.Status, suggestion.ReviewedBy, suggestion.ReviewedAt, suggestion.UpdatedAt,
+		organizationID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update classification suggestion: %w", dbError(err))
+	}
+
+	return nil
+}
+
+// DeletePending removes the suggestions of a software record that have not been reviewed yet
+func (r *PostgresClassificationSuggestionRepository) DeletePending(ctx context.Context, softwareID string) error {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete pending classification suggestions: %w", dbError(err))
+	}
+
+	query := `
+		DELETE FROM classification_suggestions
+		WHERE software_id = $1 AND status = $2 AND organization_id = $3
+	`
+	_, err = r.pool.Exec(ctx, query, softwareID, models.SuggestionPending, organizationID)
+	if err != nil {
+		return fmt.Errorf("failed to delete pending classification suggestions: %w", dbError(err))
+	}
+
+	return nil
+}
+
+// list runs a query returning classification suggestions; the ID of the organization in the
+// context is passed as the parameter after args
+func (r *PostgresClassificationSuggestionRepository) list(ctx context.Context, query string, args ...interface{}) ([]models.ClassificationSuggestion, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list classification suggestions: %w", dbError(err))
+	}
+
+	rows, err := r.pool.Query(ctx, query, append(args, organizationID)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list classification suggestions: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	var suggestions []models.ClassificationSuggestion
+	for rows.Next() {
+		suggestion, err := scanClassificationSuggestion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan classification suggestion: %w", dbError(err))
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", dbError(err))
+	}
+
+	return suggestions, nil
+}
+
+// scanClassificationSuggestion scans the classificationSuggestionColumns of a row
+func scanClassificationSuggestion(row pgx.Row) (models.ClassificationSuggestion, error) {
+	var suggestion models.ClassificationSuggestion
+	err := row.Scan(
+		&suggestion.ID, &suggestion.SoftwareID, &suggestion.Classifier, &suggestion.Field,
+		&suggestion.Value, &suggestion.Confidence, &suggestion.Status,
+		&suggestion.ReviewedBy, &suggestion.ReviewedAt, &suggestion.CreatedAt, &suggestion.UpdatedAt,
+	)
+	return suggestion, err
+}
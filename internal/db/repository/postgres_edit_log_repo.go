@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"apm/internal/models"
+	"apm/internal/pagination"
+	"apm/internal/tenant"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Ensure implementation satisfies the interface
+var _ EditLogRepository = (*PostgresEditLogRepository)(nil)
+
+// PostgresEditLogRepository implements EditLogRepository using PostgreSQL. All methods act on
+// the audit trail of the organization in the context.
+type PostgresEditLogRepository struct {
+	pool   *pgxpool.Pool
+	logger *log.Logger
+}
+
+// NewPostgresEditLogRepository creates a new PostgreSQL edit log repository
+func NewPostgresEditLogRepository(pool *pgxpool.Pool) EditLogRepository {
+	return &PostgresEditLogRepository{
+		pool:   pool,
+		logger: log.New(log.Writer(), "[EditLogRepo] ", log.LstdFlags),
+	}
+}
+
+// editLogColumns lists the columns read for an edit log
+const editLogColumns = `
+	id, COALESCE(change_id, ''), COALESCE(user_id::text, ''), entity_type, entity_id, action,
+	field_name, COALESCE(old_value, ''), COALESCE(new_value, ''), timestamp
+`
+
+// Create inserts the field rows of a change into the database, in a single transaction
+func (r *PostgresEditLogRepository) Create(ctx context.Context, logs []models.EditLog) error {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create edit logs: %w", dbError(err))
+	}
+
+	err = r.pool.BeginFunc(ctx, func(tx pgx.Tx) error {
+		query := `
+			INSERT INTO edit_logs (
+				id, organization_id, change_id, user_id, entity_type, entity_id, action,
+				field_name, old_value, new_value, timestamp
+			) VALUES (
+				$1, $2, $3, NULLIF($4, '')::uuid, $5, $6, $7, $8, $9, $10, $11
+			)
+		`
+		for _, entry := range logs {
+			_, err := tx.Exec(ctx, query,
+				generateID(), organizationID, entry.ChangeID, entry.UserID, entry.EntityType, entry.EntityID,
+				entry.Action, entry.FieldName, entry.OldValue, entry.NewValue, entry.Timestamp,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create edit logs: %w", dbError(err))
+	}
+
+	return nil
+}
+
+// ListByEntity retrieves a page of the history of a record, newest first
+func (r *PostgresEditLogRepository) ListByEntity(ctx context.Context, entityType, entityID string, page pagination.Request) (pagination.Page[models.EditLog], error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return pagination.Page[models.EditLog]{}, fmt.Errorf("failed to list edit logs: %w", dbError(err))
+	}
+
+	conds := newConditions(`organization_id = ?`, organizationID)
+	conds.add(`entity_type = ?`, entityType)
+	conds.add(`entity_id = ?`, entityID)
+	return r.list(ctx, conds, page)
+}
+
+// ListByUser retrieves a page of the changes made by a user, newest first
+func (r *PostgresEditLogRepository) ListByUser(ctx context.Context, userID string, page pagination.Request) (pagination.Page[models.EditLog], error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return pagination.Page[models.EditLog]{}, fmt.Errorf("failed to list edit logs: %w", dbError(err))
+	}
+
+	conds := newConditions(`organization_id = ?`, organizationID)
+	conds.add(`user_id = ?`, userID)
+	return r.list(ctx, conds, page)
+}
+
+// list retrieves a page of the edit logs matching conditions, newest first
+func (r *PostgresEditLogRepository) list(ctx context.Context, conds *conditions, page pagination.Request) (pagination.Page[models.EditLog], error) {
+	query := pageQuery[models.EditLog]{
+		columns: editLogColumns,
+		from:    "edit_logs",
+		conds:   conds,
+		order:   []sortKey{{expr: "timestamp", descending: true}, {expr: "field_name"}, {expr: "id"}},
+		keys: func(entry models.EditLog) []string {
+			return []string{timeKey(entry.Timestamp), entry.FieldName, entry.ID}
+		},
+		scan: scanEditLog,
+	}
+
+	logs, err := listPage(ctx, r.pool, query, page)
+	if err != nil {
+		return pagination.Page[models.EditLog]{}, fmt.Errorf("failed to list edit logs: %w", dbError(err))
+	}
+
+	return logs, nil
+}
+
+// scanEditLog scans the editLogColumns of a row
+func scanEditLog(row pgx.Row) (models.EditLog, error) {
+	var entry models.EditLog
+	err := row.Scan(
+		&entry.ID, &entry.ChangeID, &entry.UserID, &entry.EntityType, &entry.EntityID, &entry.Action,
+		&entry.FieldName, &entry.OldValue, &entry.NewValue, &entry.Timestamp,
+	)
+	return entry, err
+}
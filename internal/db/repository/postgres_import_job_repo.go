@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"apm/internal/models"
+	"apm/internal/pagination"
+	"apm/internal/tenant"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Ensure implementation satisfies the interface
+var _ ImportJobRepository = (*PostgresImportJobRepository)(nil)
+
+// PostgresImportJobRepository implements ImportJobRepository using PostgreSQL. All methods
+// act on the import jobs of the organization in the context.
+type PostgresImportJobRepository struct {
+	pool   *pgxpool.Pool
+	logger *log.Logger
+}
+
+// NewPostgresImportJobRepository creates a new PostgreSQL import job repository
+func NewPostgresImportJobRepository(pool *pgxpool.Pool) ImportJobRepository {
+	return &PostgresImportJobRepository{
+		pool:   pool,
+		logger: log.New(log.Writer(), "[ImportJobRepo] ", log.LstdFlags),
+	}
+}
+
+// importJobColumns lists the columns read for an import job, without the file content
+const importJobColumns = `
+	id, file_name, COALESCE(uploaded_by, ''), status,
+	total_rows, created_rows, updated_rows, skipped_rows, flagged_rows, failed_rows,
+	report, COALESCE(error_message, ''), COALESCE(rerun_of, ''),
+	COALESCE(mapping_profile_id, ''), duplicate_action,
+	started_at, finished_at, created_at, updated_at
+`
+
+// Create inserts a new import job into the database
+func (r *PostgresImportJobRepository) Create(ctx context.Context, job models.ImportJob) (models.ImportJob, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return models.ImportJob{}, fmt.Errorf("failed to create import job: %w", dbError(err))
+	}
+
+	// Generate a new ID if not provided
+	if job.ID == "" {
+		job.ID = generateID()
+	}
+	if job.Status == "" {
+		job.Status = models.ImportJobPending
+	}
+	if job.DuplicateAction == "" {
+		job.DuplicateAction = models.DuplicateActionSkip
+	}
+
+	// Set timestamps
+	now := time.Now().UTC()
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	report, err := marshalImportReport(job.Report)
+	if err != nil {
+		return models.ImportJob{}, err
+	}
+
+	query := `
+		INSERT INTO import_jobs (
+			id, file_name, file_content, uploaded_by, status,
+			total_rows, created_rows, updated_rows, skipped_rows, flagged_rows, failed_rows,
+			report, error_message, rerun_of, mapping_profile_id, duplicate_action,
+			started_at, finished_at, created_at, updated_at, organization_id
+		) VALUES (
+			$1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8, $9, $10, $11,
+			$12, NULLIF($13, ''), NULLIF($14, ''), NULLIF($15, ''), $16,
+			$17, $18, $19, $20, $21
+		)
+	`
+
+	_, err = r.pool.Exec(ctx, query,
+		job.ID, job.FileName, job.FileContent, job.UploadedBy, job.Status,
+		job.TotalRows, job.CreatedRows, job.UpdatedRows, job.SkippedRows, job.FlaggedRows, job.FailedRows,
+		report, job.ErrorMessage, job.RerunOf, job.MappingProfileID, job.DuplicateAction,
+		job.StartedAt, job.FinishedAt, job.CreatedAt, job.UpdatedAt, organizationID,
+	)
+	if err != nil {
+		return models.ImportJob{}, fmt.Errorf("failed to create import job: %w", dbError(err))
+	}
+
+	return job, nil
+}
+
+// GetByID retrieves an import job, including its uploaded file, by its ID
+func (r *PostgresImportJobRepository) GetByID(ctx context.Context, id string) (models.ImportJob, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return models.ImportJob{}, fmt.Errorf("failed to get import job by ID: %w", dbError(err))
+	}
+
+	query := `SELECT ` + importJobColumns + `, file_content FROM import_jobs WHERE id = $1 AND organization_id = $2`
+	row := r.pool.QueryRow(ctx, query, id, organizationID)
+
+	var content []byte
+	job, err := scanImportJob(row, &content)
+	if err != nil {
+		return models.ImportJob{}, fmt.Errorf("failed to get import job by ID: %w", dbError(err))
+	}
+	job.FileContent = content
+
+	return job, nil
+}
+
+// List retrieves a page of import jobs, most recent first, without their files
+func (r *PostgresImportJobRepository) List(ctx context.Context, page pagination.Request) (pagination.Page[models.ImportJob], error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return pagination.Page[models.ImportJob]{}, fmt.Errorf("failed to list import jobs: %w", dbError(err))
+	}
+
+	query := pageQuery[models.ImportJob]{
+		columns: importJobColumns,
+		from:    "import_jobs",
+		conds:   newConditions(`organization_id = ?`, organizationID),
+		order:   []sortKey{{expr: "created_at", descending: true}, {expr: "id", descending: true}},
+		keys: func(job models.ImportJob) []string {
+			return []string{timeKey(job.CreatedAt), job.ID}
+		},
+		scan: func(row pgx.Row) (models.ImportJob, error) {
+			return scanImportJob(row)
+		},
+	}
+
+	jobs, err := listPage(ctx, r.pool, query, page)
+	if err != nil {
+		return pagination.Page[models.ImportJob]{}, fmt.Errorf("failed to list import jobs: %w", dbError(err))
+	}
+
+	return jobs, nil
+}
+
+// Update stores the status, counters and report of an existing import job
+func (r *PostgresImportJobRepository) Update(ctx context.Context, job models.ImportJob) error {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update import job: %w", dbError(err))
+	}
+
+	// Update the UpdatedAt timestamp
+	job.UpdatedAt = time.Now().UTC()
+
+	report, err := marshalImportReport(job.Report)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE import_jobs SET
+			status = $2,
+			total_rows = $3,
+			created_rows = $4,
+			updated_rows = $5,
+			skipped_rows = $6,
+			flagged_rows = $7,
+			failed_rows = $8,
+			report = $9,
+			error_message = NULLIF($10, ''),
+			started_at = $11,
+			finished_at = $12,
+			updated_at = $13
+		WHERE id = $1 AND organization_id = $14
+	`
+
+	_, err = r.pool.Exec(ctx, query,
+		job.ID, job.Status, job.TotalRows, job.CreatedRows, job.UpdatedRows,
+		job.SkippedRows, job.FlaggedRows, job.FailedRows, report, job.ErrorMessage,
+		job.StartedAt, job.FinishedAt, job.UpdatedAt, organizationID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update import job: %w", dbError(err))
+	}
+
+	return nil
+}
+
+// scanImportJob scans the importJobColumns of a row, followed by any extra destinations
+func scanImportJob(row pgx.Row, extra ...interface{}) (models.ImportJob, error) {
+	var job models.ImportJob
+	var report []byte
+
+	dest := []interface{}{
+		&job.ID, &job.FileName, &job.UploadedBy, &job.Status,
+		&job.TotalRows, &job.CreatedRows, &job.UpdatedRows, &job.SkippedRows, &job.FlaggedRows, &job.FailedRows,
+		&report, &job.ErrorMessage, &job.RerunOf, &job.MappingProfileID, &job.DuplicateAction,
+		&job.StartedAt, &job.FinishedAt, &job.CreatedAt, &job.UpdatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return models.ImportJob{}, err
+	}
+
+	if len(report) > 0 {
+		job.Report = &models.ImportReport{}
+		if err := json.Unmarshal(report, job.Report); err != nil {
+			return models.ImportJob{}, fmt.Errorf("failed to decode import report: %w", dbError(err))
+		}
+	}
+
+	return job, nil
+}
+
+// marshalImportReport encodes a report for the JSONB report column
+func marshalImportReport(report *models.ImportReport) ([]byte, error) {
+	if report == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode import report: %w", dbError(err))
+	}
+	return data, nil
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"apm/internal/models"
+	"apm/internal/pagination"
+	"apm/internal/tenant"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Ensure implementation satisfies the interface
+var _ ImportMappingProfileRepository = (*PostgresImportMappingProfileRepository)(nil)
+
+// PostgresImportMappingProfileRepository implements ImportMappingProfileRepository using
+// PostgreSQL. All methods act on the mapping profiles of the organization in the context.
+type PostgresImportMappingProfileRepository struct {
+	pool   *pgxpool.Pool
+	logger *log.Logger
+}
+
+// NewPostgresImportMappingProfileRepository creates a new PostgreSQL import mapping profile repository
+func NewPostgresImportMappingProfileRepository(pool *pgxpool.Pool) ImportMappingProfileRepository {
+	return &PostgresImportMappingProfileRepository{
+		pool:   pool,
+		logger: log.New(log.Writer(), "[ImportMappingProfileRepo] ", log.LstdFlags),
+	}
+}
+
+// importMappingProfileColumns lists the columns read for a mapping profile
+const importMappingProfileColumns = `
+	id, name, COALESCE(description, ''), column_mapping, value_mapping, created_at, updated_at
+`
+
+// Create inserts a new mapping profile into the database
+func (r *PostgresImportMappingProfileRepository) Create(ctx context.Context, profile models.ImportMappingProfile) (models.ImportMappingProfile, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return models.ImportMappingProfile{}, fmt.Errorf("failed to create import mapping profile: %w", dbError(err))
+	}
+
+	// Generate a new ID if not provided
+	if profile.ID == "" {
+		profile.ID = generateID()
+	}
+
+	// Set timestamps
+	now := time.Now().UTC()
+	profile.CreatedAt = now
+	profile.UpdatedAt = now
+
+	columns, values, err := marshalImportMappings(profile)
+	if err != nil {
+		return models.ImportMappingProfile{}, err
+	}
+
+	query := `
+		INSERT INTO import_mapping_profiles (
+			id, name, description, column_mapping, value_mapping, created_at, updated_at, organization_id
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8
+		)
+	`
+
+	_, err = r.pool.Exec(ctx, query,
+		profile.ID, profile.Name, profile.Description, columns, values,
+		profile.CreatedAt, profile.UpdatedAt, organizationID,
+	)
+	if err != nil {
+		return models.ImportMappingProfile{}, fmt.Errorf("failed to create import mapping profile: %w", dbError(err))
+	}
+
+	return profile, nil
+}
+
+// GetByID retrieves a mapping profile by its ID
+func (r *PostgresImportMappingProfileRepository) GetByID(ctx context.Context, id string) (models.ImportMappingProfile, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return models.ImportMappingProfile{}, fmt.Errorf("failed to get import mapping profile by ID: %w", dbError(err))
+	}
+
+	query := `SELECT ` + importMappingProfileColumns + ` FROM import_mapping_profiles WHERE id = $1 AND organization_id = $2`
+	row := r.pool.QueryRow(ctx, query, id, organizationID)
+
+	profile, err := scanImportMappingProfile(row)
+	if err != nil {
+		return models.ImportMappingProfile{}, fmt.Errorf("failed to get import mapping profile by ID: %w", dbError(err))
+	}
+
+	return profile, nil
+}
+
+// List retrieves a page of mapping profiles ordered by name
+func (r *PostgresImportMappingProfileRepository) List(ctx context.Context, page pagination.Request) (pagination.Page[models.ImportMappingProfile], error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return pagination.Page[models.ImportMappingProfile]{}, fmt.Errorf("failed to list import mapping profiles: %w", dbError(err))
+	}
+
+	query := pageQuery[models.ImportMappingProfile]{
+		columns: importMappingProfileColumns,
+		from:    "import_mapping_profiles",
+		conds:   newConditions(`organization_id = ?`, organizationID),
+		order:   []sortKey{{expr: "name"}, {expr: "id"}},
+		keys: func(profile models.ImportMappingProfile) []string {
+			return []string{profile.Name, profile.ID}
+		},
+		scan: scanImportMappingProfile,
+	}
+
+	profiles, err := listPage(ctx, r.pool, query, page)
+	if err != nil {
+		return pagination.Page[models.ImportMappingProfile]{}, fmt.Errorf("failed to list import mapping profiles: %w", dbError(err))
+	}
+
+	return profiles, nil
+}
+
+// Update updates an existing mapping profile
+func (r *PostgresImportMappingProfileRepository) Update(ctx context.Context, profile models.ImportMappingProfile) error {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update import mapping profile: %w", dbError(err))
+	}
+
+	// Update the UpdatedAt timestamp
+	profile.UpdatedAt = time.Now().UTC()
+
+	columns, values, err := marshalImportMappings(profile)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE import_mapping_profiles SET
+			name = $2,
+			description = $3,
+			column_mapping = $4,
+			value_mapping = $5,
+			updated_at = $6
+		WHERE id = $1 AND organization_id = $7
+	`
+
+	_, err = r.pool.Exec(ctx, query,
+		profile.ID, profile.Name, profile.Description, columns, values, profile.UpdatedAt, organizationID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update import mapping profile: %w", dbError(err))
+	}
+
+	return nil
+}
+
+// Delete removes a mapping profile by its ID
+func (r *PostgresImportMappingProfileRepository) Delete(ctx context.Context, id string) error {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete import mapping profile: %w", dbError(err))
+	}
+
+	query := `DELETE FROM import_mapping_profiles WHERE id = $1 AND organization_id = $2`
+	_, err = r.pool.Exec(ctx, query, id, organizationID)
+	if err != nil {
+		return fmt.Errorf("failed to delete import mapping profile: %w", dbError(err))
+	}
+
+	return nil
+}
+
+// scanImportMappingProfile scans the importMappingProfileColumns of a row
+func scanImportMappingProfile(row pgx.Row) (models.ImportMappingProfile, error) {
+	var profile models.ImportMappingProfile
+	var columns, values []byte
+
+	err := row.Scan(
+		&profile.ID, &profile.Name, &profile.Description, &columns, &values,
+		&profile.CreatedAt, &profile.UpdatedAt,
+	)
+	if err != nil {
+		return models.ImportMappingProfile{}, err
+	}
+
+	if err := json.Unmarshal(columns, &profile.ColumnMapping); err != nil {
+		return models.ImportMappingProfile{}, fmt.Errorf("failed to decode column mapping: %w", dbError(err))
+	}
+	if err := json.Unmarshal(values, &profile.ValueMapping); err != nil {
+		return models.ImportMappingProfile{}, fmt.Errorf("failed to decode value mapping: %w", dbError(err))
+	}
+
+	return profile, nil
+}
+
+// marshalImportMappings encodes the column and value mappings for their JSONB columns
+func marshalImportMappings(profile models.ImportMappingProfile) ([]byte, []byte, error) {
+	columns := profile.ColumnMapping
+	if columns == nil {
+		columns = map[string]string{}
+	}
+	values := profile.ValueMapping
+	if values == nil {
+		values = map[string]map[string]string{}
+	}
+
+	columnsJSON, err := json.Marshal(columns)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode column mapping: %w", dbError(err))
+	}
+	valuesJSON, err := json.Marshal(values)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode value mapping: %w", dbError(err))
+	}
+
+	return columnsJSON, valuesJSON, nil
+}
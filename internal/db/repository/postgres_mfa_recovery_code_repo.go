@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Ensure implementation satisfies the interface
+var _ MFARecoveryCodeRepository = (*PostgresMFARecoveryCodeRepository)(nil)
+
+// PostgresMFARecoveryCodeRepository implements MFARecoveryCodeRepository using PostgreSQL
+type PostgresMFARecoveryCodeRepository struct {
+	pool   *pgxpool.Pool
+	logger *log.Logger
+}
+
+// NewPostgresMFARecoveryCodeRepository creates a new PostgreSQL MFA recovery code repository
+func NewPostgresMFARecoveryCodeRepository(pool *pgxpool.Pool) MFARecoveryCodeRepository {
+	return &PostgresMFARecoveryCodeRepository{
+		pool:   pool,
+		logger: log.New(log.Writer(), "[MFARecoveryCodeRepo] ", log.LstdFlags),
+	}
+}
+
+// ReplaceForUser replaces the recovery codes of a user with new ones, in a single transaction
+func (r *PostgresMFARecoveryCodeRepository) ReplaceForUser(ctx context.Context, userID string, codeHashes []string) error {
+	err := r.pool.BeginFunc(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		for _, codeHash := range codeHashes {
+			query := `INSERT INTO mfa_recovery_codes (id, user_id, code_hash, created_at) VALUES ($1, $2, $3, $4)`
+			if _, err := tx.Exec(ctx, query, generateID(), userID, codeHash, now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to replace recovery codes: %w", dbError(err))
+	}
+
+	return nil
+}
+
+// Use marks an unused recovery code of a user as used, reporting whether there was one
+func (r *PostgresMFARecoveryCodeRepository) Use(ctx context.Context, userID, codeHash string) (bool, error) {
+	query := `
+		UPDATE mfa_recovery_codes SET used_at = $3
+		WHERE id = (
+			SELECT id FROM mfa_recovery_codes
+			WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+			LIMIT 1
+		) AND used_at IS NULL
+	`
+	tag, err := r.pool.Exec(ctx, query, userID, codeHash, time.Now().UTC())
+	if err != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", dbError(err))
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// DeleteForUser removes all recovery codes of a user
+func (r *PostgresMFARecoveryCodeRepository) DeleteForUser(ctx context.Context, userID string) error {
+	query := `DELETE FROM mfa_recovery_codes WHERE user_id = $1`
+	_, err := r.pool.Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", dbError(err))
+	}
+
+	return nil
+}
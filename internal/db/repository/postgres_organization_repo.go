@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"apm/internal/models"
+	"apm/internal/pagination"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Ensure implementation satisfies the interface
+var _ OrganizationRepository = (*PostgresOrganizationRepository)(nil)
+
+// PostgresOrganizationRepository implements OrganizationRepository using PostgreSQL
+type PostgresOrganizationRepository struct {
+	pool   *pgxpool.Pool
+	logger *log.Logger
+}
+
+// NewPostgresOrganizationRepository creates a new PostgreSQL organization repository
+func NewPostgresOrganizationRepository(pool *pgxpool.Pool) OrganizationRepository {
+	return &PostgresOrganizationRepository{
+		pool:   pool,
+		logger: log.New(log.Writer(), "[OrganizationRepo] ", log.LstdFlags),
+	}
+}
+
+// organizationColumns lists the columns read for an organization
+const organizationColumns = `id, name, display_name, subdomain, created_at, updated_at`
+
+// Create inserts a new organization into the database
+func (r *PostgresOrganizationRepository) Create(ctx context.Context, organization models.Organization) (models.Organization, error) {
+	// Generate a new ID if not provided
+	if organization.ID == "" {
+		organization.ID = generateID()
+	}
+
+	// Set timestamps
+	now := time.Now().UTC()
+	organization.CreatedAt = now
+	organization.UpdatedAt = now
+
+	query := `
+		INSERT INTO organizations (id, name, display_name, subdomain, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + organizationColumns
+
+	row := r.pool.QueryRow(ctx, query,
+		organization.ID, organization.Name, organization.DisplayName, organization.Subdomain,
+		organization.CreatedAt, organization.UpdatedAt,
+	)
+
+	created, err := scanOrganization(row)
+	if err != nil {
+		return models.Organization{}, fmt.Errorf("failed to create organization: %w", dbError(err))
+	}
+
+	return created, nil
+}
+
+// GetByID retrieves an organization by its ID
+func (r *PostgresOrganizationRepository) GetByID(ctx context.Context, id string) (models.Organization, error) {
+	query := `SELECT ` + organizationColumns + ` FROM organizations WHERE id = $1`
+	row := r.pool.QueryRow(ctx, query, id)
+
+	organization, err := scanOrganization(row)
+	if err != nil {
+		return models.Organization{}, fmt.Errorf("failed to get organization by ID: %w", dbError(err))
+	}
+
+	return organization, nil
+}
+
+// GetBySubdomain retrieves an organization by its subdomain, ignoring case
+func (r *PostgresOrganizationRepository) GetBySubdomain(ctx context.Context, subdomain string) (models.Organization, error) {
+	query := `SELECT ` + organizationColumns + ` FROM organizations WHERE lower(subdomain) = lower($1)`
+	row := r.pool.QueryRow(ctx, query, subdomain)
+
+	organization, err := scanOrganization(row)
+	if err != nil {
+		return models.Organization{}, fmt.Errorf("failed to get organization by subdomain: %w", dbError(err))
+	}
+
+	return organization, nil
+}
+
+// List retrieves a page of organizations ordered by name
+func (r *PostgresOrganizationRepository) List(ctx context.Context, page pagination.Request) (pagination.Page[models.Organization], error) {
+	query := pageQuery[models.Organization]{
+		columns: organizationColumns,
+		from:    "organizations",
+		conds:   &conditions{},
+		order:   []sortKey{{expr: "name"}, {expr: "id"}},
+		keys: func(organization models.Organization) []string {
+			return []string{organization.Name, organization.ID}
+		},
+		scan: scanOrganization,
+	}
+
+	organizations, err := listPage(ctx, r.pool, query, page)
+	if err != nil {
+		return pagination.Page[models.Organization]{}, fmt.Errorf("failed to list organizations: %w", dbError(err))
+	}
+
+	return organizations, nil
+}
+
+// Update updates the names of an existing organization
+func (r *PostgresOrganizationRepository) Update(ctx context.Context, organization models.Organization) error {
+	// Update the UpdatedAt timestamp
+	organization.UpdatedAt = time.Now().UTC()
+
+	query := `
+		UPDATE organizations SET
+			name = $2,
+			display_name = $3,
+			updated_at = $4
+		WHERE id = $1
+	`
+
+	_, err := r.pool.Exec(ctx, query, organization.ID, organization.Name, organization.DisplayName, organization.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update organization: %w", dbError(err))
+	}
+
+	return nil
+}
+
+// Delete removes an organization, and with it all of its users and data, from the database
+func (r *PostgresOrganizationRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM organizations WHERE id = $1`
+	_, err := r.pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete organization: %w", dbError(err))
+	}
+
+	return nil
+}
+
+// scanOrganization scans the organizationColumns of a row
+func scanOrganization(row pgx.Row) (models.Organization, error) {
+	var organization models.Organization
+	err := row.Scan(
+		&organization.ID, &organization.Name, &organization.DisplayName, &organization.Subdomain,
+		&organization.CreatedAt, &organization.UpdatedAt,
+	)
+	return organization, err
+}
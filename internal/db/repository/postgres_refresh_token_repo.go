@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"apm/internal/models"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Ensure implementation satisfies the interface
+var _ RefreshTokenRepository = (*PostgresRefreshTokenRepository)(nil)
+
+// PostgresRefreshTokenRepository implements RefreshTokenRepository using PostgreSQL
+type PostgresRefreshTokenRepository struct {
+	pool   *pgxpool.Pool
+	logger *log.Logger
+}
+
+// NewPostgresRefreshTokenRepository creates a new PostgreSQL refresh token repository
+func NewPostgresRefreshTokenRepository(pool *pgxpool.Pool) RefreshTokenRepository {
+	return &PostgresRefreshTokenRepository{
+		pool:   pool,
+		logger: log.New(log.Writer(), "[RefreshTokenRepo] ", log.LstdFlags),
+	}
+}
+
+// Create inserts a new refresh token into the database
+func (r *PostgresRefreshTokenRepository) Create(ctx context.Context, token models.RefreshToken) (models.RefreshToken, error) {
+	// Generate a new ID if not provided
+	if token.ID == "" {
+		token.ID = generateID()
+	}
+	token.CreatedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO refresh_tokens (id, user_id, session_id, token_hash, expires_at, revoked_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		token.ID, token.UserID, token.SessionID, token.TokenHash, token.ExpiresAt, token.RevokedAt, token.CreatedAt,
+	)
+	if err != nil {
+		return models.RefreshToken{}, fmt.Errorf("failed to create refresh token: %w", dbError(err))
+	}
+
+	return token, nil
+}
+
+// GetByHash retrieves a refresh token by the hash of the token
+func (r *PostgresRefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (models.RefreshToken, error) {
+	query := `
+		SELECT id, user_id, session_id, token_hash, expires_at, revoked_at, created_at
+		FROM refresh_tokens WHERE token_hash = $1
+	`
+	row := r.pool.QueryRow(ctx, query, tokenHash)
+
+	var token models.RefreshToken
+	err := row.Scan(
+		&token.ID, &token.UserID, &token.SessionID, &token.TokenHash,
+		&token.ExpiresAt, &token.RevokedAt, &token.CreatedAt,
+	)
+	if err != nil {
+		return models.RefreshToken{}, fmt.Errorf("failed to get refresh token: %w", dbError(err))
+	}
+
+	return token, nil
+}
+
+// Revoke revokes a refresh token, reporting whether it was still active. Only one of several
+// concurrent callers revokes the token, so a token can be exchanged just once.
+func (r *PostgresRefreshTokenRepository) Revoke(ctx context.Context, id string) (bool, error) {
+	query := `UPDATE refresh_tokens SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL`
+	tag, err := r.pool.Exec(ctx, query, id, time.Now().UTC())
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke refresh token: %w", dbError(err))
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// RevokeSession revokes the active refresh tokens of a login session
+func (r *PostgresRefreshTokenRepository) RevokeSession(ctx context.Context, sessionID string) error {
+	query := `UPDATE refresh_tokens SET revoked_at = $2 WHERE session_id = $1 AND revoked_at IS NULL`
+	_, err := r.pool.Exec(ctx, query, sessionID, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", dbError(err))
+	}
+
+	return nil
+}
+
+// RevokeAllForUser revokes the active refresh tokens of all sessions of a user
+func (r *PostgresRefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	query := `UPDATE refresh_tokens SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL`
+	_, err := r.pool.Exec(ctx, query, userID, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to revoke sessions of user: %w", dbError(err))
+	}
+
+	return nil
+}
+
+// DeleteExpired removes the refresh tokens that expired before the given time
+func (r *PostgresRefreshTokenRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	query := `DELETE FROM refresh_tokens WHERE expires_at < $1`
+	_, err := r.pool.Exec(ctx, query, before)
+	if err != nil {
+		return fmt.Errorf("failed to delete expired refresh tokens: %w", dbError(err))
+	}
+
+	return nil
+}
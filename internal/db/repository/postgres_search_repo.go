@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+
+	"apm/internal/models"
+	"apm/internal/pagination"
+	"apm/internal/tenant"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Ensure implementation satisfies the interface
+var _ SearchRepository = (*PostgresSearchRepository)(nil)
+
+// PostgresSearchRepository implements SearchRepository using PostgreSQL. It searches the
+// software of the organization in the context, along with the entities and categories shared by
+// all organizations. Records match on the words of their text, or on names similar to the text.
+type PostgresSearchRepository struct {
+	pool   *pgxpool.Pool
+	logger *log.Logger
+}
+
+// NewPostgresSearchRepository creates a new PostgreSQL search repository
+func NewPostgresSearchRepository(pool *pgxpool.Pool) SearchRepository {
+	return &PostgresSearchRepository{
+		pool:   pool,
+		logger: log.New(log.Writer(), "[SearchRepo] ", log.LstdFlags),
+	}
+}
+
+// Delimiters of the matches in snippets. They are private use characters, which do not occur in
+// the text, so they can be told apart from it once it has been escaped.
+const (
+	highlightStart = "\uE000"
+	highlightStop  = "\uE001"
+)
+
+// searchMatches defines the matches of a search, for the text $1 and the organization $2. Their
+// score adds the rank of the words matched to the similarity of their names to the text.
+const searchMatches = `
+	WITH search AS (
+		SELECT websearch_to_tsquery('english', $1) AS query
+	), matches AS (
+		SELECT 'software' AS type, s.id, s.display_name AS title, COALESCE(s.vendor, '') AS subtitle,
+			COALESCE(s.description, '') AS body,
+			ts_rank(s.search_vector, search.query)
+				+ GREATEST(similarity(s.display_name, $1), word_similarity($1, COALESCE(s.vendor, ''))) AS score
+		FROM software s CROSS JOIN search
+		WHERE s.organization_id = $2 AND s.deleted_at IS NULL
+			AND (s.search_vector @@ search.query OR s.display_name % $1 OR $1 <% s.display_name OR $1 <% s.vendor)
+		UNION ALL
+		SELECT 'entity', e.id::text, COALESCE(oe.custom_name, e.name), e.entity_type::text,
+			COALESCE(oe.custom_description, e.description, ''),
+			ts_rank(e.search_vector, search.query)
+				+ GREATEST(similarity(COALESCE(oe.custom_name, e.name), $1), word_similarity($1, e.name))
+		FROM master_entities e
+		LEFT JOIN organization_entities oe ON oe.master_entity_id = e.id AND oe.organization_id = $2
+		CROSS JOIN search
+		WHERE e.search_vector @@ search.query OR e.name % $1 OR $1 <% e.name OR $1 <% oe.custom_name
+		UNION ALL
+		SELECT 'category', c.id::text, c.name, '', COALESCE(c.description, ''),
+			ts_rank(c.search_vector, search.query) + GREATEST(similarity(c.name, $1), word_similarity($1, c.name))
+		FROM categories c CROSS JOIN search
+		WHERE c.search_vector @@ search.query OR c.name % $1 OR $1 <% c.name
+	)
+`
+
+// Search retrieves a page of the records of the kinds searched matching the text, best matches
+// first, with the number of matches of every kind. Results are ranked, so they are paged by offset.
+func (r *PostgresSearchRepository) Search(ctx context.Context, query models.SearchQuery, page pagination.Request) (models.SearchResults, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return models.SearchResults{}, fmt.Errorf("failed to search: %w", dbError(err))
+	}
+	page = page.Normalized()
+
+	types := make([]string, len(query.Types))
+	for i, resultType := range query.Types {
+		types[i] = string(resultType)
+	}
+
+	results := models.SearchResults{Facets: make(map[models.SearchResultType]int)}
+
+	facets, err := r.pool.Query(ctx, searchMatches+`SELECT type, COUNT(*) FROM matches GROUP BY type`, query.Text, organizationID)
+	if err != nil {
+		return models.SearchResults{}, fmt.Errorf("failed to count search matches: %w", dbError(err))
+	}
+	defer facets.Close()
+
+	for facets.Next() {
+		var resultType models.SearchResultType
+		var count int
+		if err := facets.Scan(&resultType, &count); err != nil {
+			return models.SearchResults{}, fmt.Errorf("failed to scan search facet: %w", dbError(err))
+		}
+		results.Facets[resultType] = count
+	}
+
+	if err = facets.Err(); err != nil {
+		return models.SearchResults{}, fmt.Errorf("error during rows iteration: %w", dbError(err))
+	}
+
+	// Snippets are only made for the page, as they are the costly part of the results
+	resultsQuery := searchMatches + `
+		SELECT type, id, title, subtitle,
+			ts_headline('english', body, search.query,
+				'StartSel="` + highlightStart + `", StopSel="` + highlightStop + `", MinWords=10, MaxWords=30, MaxFragments=2'),
+			score
+		FROM (
+			SELECT * FROM matches WHERE type = ANY($3) ORDER BY score DESC, title, id LIMIT $4 OFFSET $5
+		) page CROSS JOIN search
+		ORDER BY score DESC, title, id
+	`
+	rows, err := r.pool.Query(ctx, resultsQuery, query.Text, organizationID, types, page.Limit, page.Offset)
+	if err != nil {
+		return models.SearchResults{}, fmt.Errorf("failed to search: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var result models.SearchResult
+		err := rows.Scan(&result.Type, &result.ID, &result.Title, &result.Subtitle, &result.Snippet, &result.Score)
+		if err != nil {
+			return models.SearchResults{}, fmt.Errorf("failed to scan search result: %w", dbError(err))
+		}
+		result.Snippet = highlightSnippet(result.Snippet)
+		results.Results = append(results.Results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return models.SearchResults{}, fmt.Errorf("error during rows iteration: %w", dbError(err))
+	}
+
+	return results, nil
+}
+
+// highlightSnippet escapes a snippet for HTML, putting the matches delimited in it in mark elements
+func highlightSnippet(snippet string) string {
+	return strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>").Replace(html.EscapeString(snippet))
+}
@@ -4,19 +4,30 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
+	"apm/internal/apperr"
+	"apm/internal/auth"
 	"apm/internal/models"
+	"apm/internal/pagination"
+	"apm/internal/tenant"
 
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
 // Ensure implementation satisfies the interface
 var _ SoftwareRepository = (*PostgresSoftwareRepository)(nil)
 
-// PostgresSoftwareRepository implements SoftwareRepository using PostgreSQL
+// PostgresSoftwareRepository implements SoftwareRepository using PostgreSQL. All methods act
+// on the software of the organization in the context, leaving out archived software unless
+// stated otherwise.
 type PostgresSoftwareRepository struct {
 	pool   *pgxpool.Pool
 	logger *log.Logger
@@ -40,8 +51,29 @@ func generateID() string {
 	return hex.EncodeToString(bytes)
 }
 
-// Create inserts a new software record into the database
-func (r *PostgresSoftwareRepository) Create(ctx context.Context, software models.Software) (models.Software, error) {
+// currentUserID returns the ID of the authenticated user of the context, or an empty string
+func currentUserID(ctx context.Context) string {
+	user, _ := auth.UserFromContext(ctx)
+	return user.ID
+}
+
+// softwareColumns lists the columns read for a software record
+const softwareColumns = `
+	id, COALESCE(foreign_key, ''), display_name, COALESCE(description, ''),
+	software_type, COALESCE(software_subtype, ''), COALESCE(vendor, ''),
+	COALESCE(manufacturer, ''), COALESCE(install_type, ''), COALESCE(product_type, ''),
+	COALESCE(context, ''), COALESCE(lifecycle_status, ''), COALESCE(implementation_status, ''),
+	created_at, updated_at, deleted_at, COALESCE(deleted_by::text, '')
+`
+
+// Create inserts a new software record into the database, along with its first version and
+// the stakeholders it is created with
+func (r *PostgresSoftwareRepository) Create(ctx context.Context, software models.Software, stakeholders ...models.Stakeholder) (models.Software, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return models.Software{}, fmt.Errorf("failed to create software: %w", dbError(err))
+	}
+
 	// Generate a new ID if not provided
 	if software.ID == "" {
 		software.ID = generateID()
@@ -58,32 +90,35 @@ func (r *PostgresSoftwareRepository) Create(ctx context.Context, software models
 			id, foreign_key, display_name, description, software_type, 
 			software_subtype, vendor, manufacturer, install_type, 
 			product_type, context, lifecycle_status, implementation_status,
-			created_at, updated_at
+			created_at, updated_at, organization_id
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
-		) RETURNING *
-	`
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
+		) RETURNING ` + softwareColumns
 
-	// Execute the query
-	row := r.pool.QueryRow(ctx, query,
-		software.ID, software.ForeignKey, software.DisplayName, software.Description,
-		software.SoftwareType, software.SoftwareSubtype, software.Vendor,
-		software.Manufacturer, software.InstallType, software.ProductType,
-		software.Context, software.LifecycleStatus, software.ImplementationStatus,
-		software.CreatedAt, software.UpdatedAt,
-	)
-
-	// Scan the result into the software struct
+	// Insert the record, its stakeholders and its first version together
 	var result models.Software
-	err := row.Scan(
-		&result.ID, &result.ForeignKey, &result.DisplayName, &result.Description,
-		&result.SoftwareType, &result.SoftwareSubtype, &result.Vendor,
-		&result.Manufacturer, &result.InstallType, &result.ProductType,
-		&result.Context, &result.LifecycleStatus, &result.ImplementationStatus,
-		&result.CreatedAt, &result.UpdatedAt,
-	)
+	err = r.pool.BeginFunc(ctx, func(tx pgx.Tx) error {
+		row := tx.QueryRow(ctx, query,
+			software.ID, software.ForeignKey, software.DisplayName, software.Description,
+			software.SoftwareType, software.SoftwareSubtype, software.Vendor,
+			software.Manufacturer, software.InstallType, software.ProductType,
+			software.Context, software.LifecycleStatus, software.ImplementationStatus,
+			software.CreatedAt, software.UpdatedAt, organizationID,
+		)
+
+		var err error
+		if result, err = scanSoftware(row); err != nil {
+			return err
+		}
+		for _, stakeholder := range stakeholders {
+			if err := createStakeholder(ctx, tx, organizationID, result.ID, stakeholder, now); err != nil {
+				return err
+			}
+		}
+		return r.createVersion(ctx, tx, organizationID, result, models.SoftwareVersionActionCreate)
+	})
 	if err != nil {
-		return models.Software{}, fmt.Errorf("failed to create software: %w", err)
+		return models.Software{}, fmt.Errorf("failed to create software: %w", dbError(err))
 	}
 
 	return result, nil
@@ -91,58 +126,202 @@ func (r *PostgresSoftwareRepository) Create(ctx context.Context, software models
 
 // GetByID retrieves a software record by its ID
 func (r *PostgresSoftwareRepository) GetByID(ctx context.Context, id string) (models.Software, error) {
-	query := `SELECT * FROM software WHERE id = $1`
-	row := r.pool.QueryRow(ctx, query, id)
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return models.Software{}, fmt.Errorf("failed to get software by ID: %w", dbError(err))
+	}
 
-	var software models.Software
-	err := row.Scan(
-		&software.ID, &software.ForeignKey, &software.DisplayName, &software.Description,
-		&software.SoftwareType, &software.SoftwareSubtype, &software.Vendor,
-		&software.Manufacturer, &software.InstallType, &software.ProductType,
-		&software.Context, &software.LifecycleStatus, &software.ImplementationStatus,
-		&software.CreatedAt, &software.UpdatedAt,
-	)
+	query := `SELECT ` + softwareColumns + ` FROM software WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL`
+	row := r.pool.QueryRow(ctx, query, id, organizationID)
+
+	software, err := scanSoftware(row)
 	if err != nil {
-		return models.Software{}, fmt.Errorf("failed to get software by ID: %w", err)
+		return models.Software{}, fmt.Errorf("failed to get software by ID: %w", dbError(err))
 	}
 
 	return software, nil
 }
 
-// List retrieves a list of software records with pagination
-func (r *PostgresSoftwareRepository) List(ctx context.Context, limit, offset int) ([]models.Software, error) {
-	query := `SELECT * FROM software ORDER BY created_at DESC LIMIT $1 OFFSET $2`
-	rows, err := r.pool.Query(ctx, query, limit, offset)
+// GetByIDIncludingArchived retrieves a software record by its ID, whether it is archived or not
+func (r *PostgresSoftwareRepository) GetByIDIncludingArchived(ctx context.Context, id string) (models.Software, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return models.Software{}, fmt.Errorf("failed to get software by ID: %w", dbError(err))
+	}
+
+	query := `SELECT ` + softwareColumns + ` FROM software WHERE id = $1 AND organization_id = $2`
+	row := r.pool.QueryRow(ctx, query, id, organizationID)
+
+	software, err := scanSoftware(row)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list software: %w", err)
+		return models.Software{}, fmt.Errorf("failed to get software by ID: %w", dbError(err))
+	}
+
+	return software, nil
+}
+
+// softwareSortKey is a sort key of software: its expression, never NULL, and its value
+type softwareSortKey struct {
+	expr  string
+	value func(models.Software) string
+}
+
+// ErrUnknownSortField is returned when software is to be sorted by a field that is not one
+// of the softwareSortKeys
+var ErrUnknownSortField = apperr.Validation("unknown sort field")
+
+// softwareSortKeys maps the fields software can be sorted by onto their sort keys; they are
+// the only fields accepted for sorting
+var softwareSortKeys = map[string]softwareSortKey{
+	"display_name":          {"display_name", func(s models.Software) string { return s.DisplayName }},
+	"foreign_key":           {"COALESCE(foreign_key, '')", func(s models.Software) string { return s.ForeignKey }},
+	"software_type":         {"software_type", func(s models.Software) string { return string(s.SoftwareType) }},
+	"software_subtype":      {"COALESCE(software_subtype, '')", func(s models.Software) string { return s.SoftwareSubtype }},
+	"vendor":                {"COALESCE(vendor, '')", func(s models.Software) string { return s.Vendor }},
+	"manufacturer":          {"COALESCE(manufacturer, '')", func(s models.Software) string { return s.Manufacturer }},
+	"install_type":          {"COALESCE(install_type, '')", func(s models.Software) string { return s.InstallType }},
+	"product_type":          {"COALESCE(product_type, '')", func(s models.Software) string { return s.ProductType }},
+	"lifecycle_status":      {"COALESCE(lifecycle_status, '')", func(s models.Software) string { return s.LifecycleStatus }},
+	"implementation_status": {"COALESCE(implementation_status, '')", func(s models.Software) string { return s.ImplementationStatus }},
+	"created_at":            {"created_at", func(s models.Software) string { return timeKey(s.CreatedAt) }},
+	"updated_at":            {"updated_at", func(s models.Software) string { return timeKey(s.UpdatedAt) }},
+}
+
+// List retrieves a page of the software records matching the filter, in the order of the filter
+func (r *PostgresSoftwareRepository) List(ctx context.Context, filter models.SoftwareFilter, page pagination.Request) (pagination.Page[models.Software], error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return pagination.Page[models.Software]{}, fmt.Errorf("failed to list software: %w", dbError(err))
+	}
+
+	conds := newConditions(`organization_id = ?`, organizationID)
+	if !filter.IncludeArchived {
+		conds.add(`deleted_at IS NULL`)
+	}
+
+	// Software without a value in a nullable field does not have any of the excluded values
+	valueFilters := []struct {
+		filter           models.ValueFilter
+		include, exclude string
+	}{
+		{filter.DisplayName, `display_name = ANY(?)`, `display_name <> ALL(?)`},
+		{filter.SoftwareType, `software_type = ANY(?)`, `software_type <> ALL(?)`},
+		{filter.Vendor, `vendor = ANY(?)`, `(vendor IS NULL OR vendor <> ALL(?))`},
+		{filter.LifecycleStatus, `lifecycle_status = ANY(?)`, `(lifecycle_status IS NULL OR lifecycle_status <> ALL(?))`},
+	}
+	for _, valueFilter := range valueFilters {
+		if len(valueFilter.filter.Include) > 0 {
+			conds.add(valueFilter.include, valueFilter.filter.Include)
+		}
+		if len(valueFilter.filter.Exclude) > 0 {
+			conds.add(valueFilter.exclude, valueFilter.filter.Exclude)
+		}
+	}
+
+	if text := strings.TrimSpace(filter.Query); text != "" {
+		pattern := "%" + escapeLike(text) + "%"
+		conds.add(
+			`(display_name ILIKE ? OR description ILIKE ? OR vendor ILIKE ? OR manufacturer ILIKE ? OR foreign_key ILIKE ?)`,
+			pattern, pattern, pattern, pattern, pattern,
+		)
+	}
+
+	// Sort by known fields only, ending with the ID so that pages do not overlap
+	var order []sortKey
+	var values []func(models.Software) string
+	for _, sort := range filter.Sort {
+		key, ok := softwareSortKeys[sort.Field]
+		if !ok {
+			return pagination.Page[models.Software]{}, fmt.Errorf("failed to list software: %w: %q", ErrUnknownSortField, sort.Field)
+		}
+		order = append(order, sortKey{expr: key.expr, descending: sort.Descending})
+		values = append(values, key.value)
+	}
+	if len(order) == 0 {
+		order = append(order, sortKey{expr: "created_at", descending: true})
+		values = append(values, softwareSortKeys["created_at"].value)
+	}
+	order = append(order, sortKey{expr: "id"})
+	values = append(values, func(s models.Software) string { return s.ID })
+
+	query := pageQuery[models.Software]{
+		columns: softwareColumns,
+		from:    "software",
+		conds:   conds,
+		order:   order,
+		keys: func(software models.Software) []string {
+			keys := make([]string, len(values))
+			for i, value := range values {
+				keys[i] = value(software)
+			}
+			return keys
+		},
+		scan: func(row pgx.Row) (models.Software, error) {
+			return scanSoftware(row)
+		},
+	}
+
+	softwareList, err := listPage(ctx, r.pool, query, page)
+	if err != nil {
+		return pagination.Page[models.Software]{}, fmt.Errorf("failed to list software: %w", dbError(err))
+	}
+
+	return softwareList, nil
+}
+
+// FindDuplicates retrieves software sharing the foreign key, or whose display name and vendor
+// are similar to the given ones, best matches first
+func (r *PostgresSoftwareRepository) FindDuplicates(ctx context.Context, foreignKey, displayName, vendor string, minSimilarity float64) ([]models.SoftwareMatch, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicate software: %w", dbError(err))
+	}
+
+	query := `
+		SELECT ` + softwareColumns + `,
+			CASE WHEN $1 <> '' AND LOWER(foreign_key) = LOWER($1) THEN 'foreign_key' ELSE 'name_vendor' END AS matched_on,
+			CASE WHEN $1 <> '' AND LOWER(foreign_key) = LOWER($1) THEN 1.0
+				ELSE similarity(display_name, $2)::float8 END AS score
+		FROM software
+		WHERE organization_id = $5 AND deleted_at IS NULL
+			AND (($1 <> '' AND LOWER(foreign_key) = LOWER($1))
+				OR (display_name % $2
+					AND similarity(display_name, $2) >= $4
+					AND ((COALESCE(vendor, '') = '' AND $3 = '') OR similarity(COALESCE(vendor, ''), $3) >= $4)))
+		ORDER BY score DESC, created_at
+		LIMIT 5
+	`
+	rows, err := r.pool.Query(ctx, query, foreignKey, displayName, vendor, minSimilarity, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicate software: %w", dbError(err))
 	}
 	defer rows.Close()
 
-	var softwareList []models.Software
+	var matches []models.SoftwareMatch
 	for rows.Next() {
-		var software models.Software
-		err := rows.Scan(
-			&software.ID, &software.ForeignKey, &software.DisplayName, &software.Description,
-			&software.SoftwareType, &software.SoftwareSubtype, &software.Vendor,
-			&software.Manufacturer, &software.InstallType, &software.ProductType,
-			&software.Context, &software.LifecycleStatus, &software.ImplementationStatus,
-			&software.CreatedAt, &software.UpdatedAt,
-		)
+		var match models.SoftwareMatch
+		software, err := scanSoftware(rows, &match.MatchedOn, &match.Similarity)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan software: %w", err)
+			return nil, fmt.Errorf("failed to scan software match: %w", dbError(err))
 		}
-		softwareList = append(softwareList, software)
+		match.Software = software
+		matches = append(matches, match)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error during rows iteration: %w", err)
+		return nil, fmt.Errorf("error during rows iteration: %w", dbError(err))
 	}
 
-	return softwareList, nil
+	return matches, nil
 }
 
-// Update updates an existing software record
+// Update updates an existing software record, storing its new version
 func (r *PostgresSoftwareRepository) Update(ctx context.Context, software models.Software) error {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update software: %w", dbError(err))
+	}
+
 	// Update the UpdatedAt timestamp
 	software.UpdatedAt = time.Now().UTC()
 
@@ -161,30 +340,373 @@ func (r *PostgresSoftwareRepository) Update(ctx context.Context, software models
 			lifecycle_status = $12,
 			implementation_status = $13,
 			updated_at = $14
-		WHERE id = $1
-	`
+		WHERE id = $1 AND organization_id = $15 AND deleted_at IS NULL
+		RETURNING ` + softwareColumns
 
-	_, err := r.pool.Exec(ctx, query,
-		software.ID, software.ForeignKey, software.DisplayName, software.Description,
-		software.SoftwareType, software.SoftwareSubtype, software.Vendor,
-		software.Manufacturer, software.InstallType, software.ProductType,
-		software.Context, software.LifecycleStatus, software.ImplementationStatus,
-		software.UpdatedAt,
-	)
+	err = r.pool.BeginFunc(ctx, func(tx pgx.Tx) error {
+		row := tx.QueryRow(ctx, query,
+			software.ID, software.ForeignKey, software.DisplayName, software.Description,
+			software.SoftwareType, software.SoftwareSubtype, software.Vendor,
+			software.Manufacturer, software.InstallType, software.ProductType,
+			software.Context, software.LifecycleStatus, software.ImplementationStatus,
+			software.UpdatedAt, organizationID,
+		)
+
+		updated, err := scanSoftware(row)
+		if err != nil {
+			return err
+		}
+		return r.createVersion(ctx, tx, organizationID, updated, models.SoftwareVersionActionUpdate)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to update software: %w", err)
+		return fmt.Errorf("failed to update software: %w", dbError(err))
 	}
 
 	return nil
 }
 
-// Delete removes a software record by its ID
+// Delete archives a software record by its ID, along with its stakeholders, on behalf of the
+// authenticated user of the context. Its last state is stored as the version of the deletion.
+// Deleting software that does not exist does nothing.
 func (r *PostgresSoftwareRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM software WHERE id = $1`
-	_, err := r.pool.Exec(ctx, query, id)
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete software: %w", dbError(err))
+	}
+
+	// Stakeholders archived at the same time as their software are restored with it
+	deletedAt := time.Now().UTC()
+	deletedBy := currentUserID(ctx)
+
+	query := `
+		UPDATE software SET deleted_at = $3, deleted_by = NULLIF($4, '')::uuid
+		WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL
+		RETURNING ` + softwareColumns
+	stakeholdersQuery := `
+		UPDATE stakeholders SET deleted_at = $3, deleted_by = NULLIF($4, '')::uuid
+		WHERE software_id = $1 AND organization_id = $2 AND deleted_at IS NULL
+	`
+
+	err = r.pool.BeginFunc(ctx, func(tx pgx.Tx) error {
+		deleted, err := scanSoftware(tx.QueryRow(ctx, query, id, organizationID, deletedAt, deletedBy))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, stakeholdersQuery, id, organizationID, deletedAt, deletedBy); err != nil {
+			return err
+		}
+		return r.createVersion(ctx, tx, organizationID, deleted, models.SoftwareVersionActionDelete)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete software: %w", dbError(err))
+	}
+
+	return nil
+}
+
+// Undelete restores archived software, along with the stakeholders archived with it, and
+// stores the restored version
+func (r *PostgresSoftwareRepository) Undelete(ctx context.Context, id string) (models.Software, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return models.Software{}, fmt.Errorf("failed to undelete software: %w", dbError(err))
+	}
+
+	query := `
+		UPDATE software SET deleted_at = NULL, deleted_by = NULL
+		WHERE id = $1 AND organization_id = $2 AND deleted_at IS NOT NULL
+		RETURNING ` + softwareColumns
+
+	var result models.Software
+	err = r.pool.BeginFunc(ctx, func(tx pgx.Tx) error {
+		if err := r.undeleteStakeholders(ctx, tx, organizationID, id); err != nil {
+			return err
+		}
+
+		var err error
+		if result, err = scanSoftware(tx.QueryRow(ctx, query, id, organizationID)); err != nil {
+			return err
+		}
+		return r.createVersion(ctx, tx, organizationID, result, models.SoftwareVersionActionRestore)
+	})
+	if err != nil {
+		return models.Software{}, fmt.Errorf("failed to undelete software: %w", dbError(err))
+	}
+
+	return result, nil
+}
+
+// Restore brings software back to the state of a snapshot, restoring it if it is archived or
+// recreating it under its original ID if it was purged, and stores the restored version
+func (r *PostgresSoftwareRepository) Restore(ctx context.Context, software models.Software) (models.Software, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return models.Software{}, fmt.Errorf("failed to restore software: %w", dbError(err))
+	}
+
+	software.UpdatedAt = time.Now().UTC()
+
+	// Software of other organizations is never overwritten, even if it had the same ID
+	query := `
+		INSERT INTO software (
+			id, foreign_key, display_name, description, software_type,
+			software_subtype, vendor, manufacturer, install_type,
+			product_type, context, lifecycle_status, implementation_status,
+			created_at, updated_at, organization_id
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
+		)
+		ON CONFLICT (id) DO UPDATE SET
+			foreign_key = EXCLUDED.foreign_key,
+			display_name = EXCLUDED.display_name,
+			description = EXCLUDED.description,
+			software_type = EXCLUDED.software_type,
+			software_subtype = EXCLUDED.software_subtype,
+			vendor = EXCLUDED.vendor,
+			manufacturer = EXCLUDED.manufacturer,
+			install_type = EXCLUDED.install_type,
+			product_type = EXCLUDED.product_type,
+			context = EXCLUDED.context,
+			lifecycle_status = EXCLUDED.lifecycle_status,
+			implementation_status = EXCLUDED.implementation_status,
+			updated_at = EXCLUDED.updated_at,
+			deleted_at = NULL,
+			deleted_by = NULL
+		WHERE software.organization_id = EXCLUDED.organization_id
+		RETURNING ` + softwareColumns
+
+	var result models.Software
+	err = r.pool.BeginFunc(ctx, func(tx pgx.Tx) error {
+		if err := r.undeleteStakeholders(ctx, tx, organizationID, software.ID); err != nil {
+			return err
+		}
+
+		row := tx.QueryRow(ctx, query,
+			software.ID, software.ForeignKey, software.DisplayName, software.Description,
+			software.SoftwareType, software.SoftwareSubtype, software.Vendor,
+			software.Manufacturer, software.InstallType, software.ProductType,
+			software.Context, software.LifecycleStatus, software.ImplementationStatus,
+			software.CreatedAt, software.UpdatedAt, organizationID,
+		)
+
+		var err error
+		if result, err = scanSoftware(row); err != nil {
+			return err
+		}
+		return r.createVersion(ctx, tx, organizationID, result, models.SoftwareVersionActionRestore)
+	})
+	if err != nil {
+		return models.Software{}, fmt.Errorf("failed to restore software: %w", dbError(err))
+	}
+
+	return result, nil
+}
+
+// PurgeArchived permanently deletes the software of all organizations that was archived before
+// a time, along with its stakeholders, returning how much software was deleted. Its versions
+// are kept, so that purged software can still be restored from its history.
+func (r *PostgresSoftwareRepository) PurgeArchived(ctx context.Context, before time.Time) (int64, error) {
+	// Stakeholders are deleted with their software by the database
+	query := `DELETE FROM software WHERE deleted_at IS NOT NULL AND deleted_at < $1`
+	tag, err := r.pool.Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge archived software: %w", dbError(err))
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// softwareVersionColumns lists the columns read for a software version
+const softwareVersionColumns = `
+	id, software_id, version, action, snapshot, COALESCE(user_id::text, ''), created_at
+`
+
+// ListVersions retrieves a page of the versions of software, newest first; the versions of
+// deleted software are kept
+func (r *PostgresSoftwareRepository) ListVersions(ctx context.Context, softwareID string, page pagination.Request) (pagination.Page[models.SoftwareVersion], error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return pagination.Page[models.SoftwareVersion]{}, fmt.Errorf("failed to list software versions: %w", dbError(err))
+	}
+
+	conds := newConditions(`software_id = ?`, softwareID)
+	conds.add(`organization_id = ?`, organizationID)
+
+	query := pageQuery[models.SoftwareVersion]{
+		columns: softwareVersionColumns,
+		from:    "software_versions",
+		conds:   conds,
+		order:   []sortKey{{expr: "version", descending: true}},
+		keys: func(version models.SoftwareVersion) []string {
+			return []string{strconv.Itoa(version.Version)}
+		},
+		scan: scanSoftwareVersion,
+	}
+
+	versions, err := listPage(ctx, r.pool, query, page)
+	if err != nil {
+		return pagination.Page[models.SoftwareVersion]{}, fmt.Errorf("failed to list software versions: %w", dbError(err))
+	}
+
+	return versions, nil
+}
+
+// GetVersion retrieves a version of software by its number
+func (r *PostgresSoftwareRepository) GetVersion(ctx context.Context, softwareID string, version int) (models.SoftwareVersion, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return models.SoftwareVersion{}, fmt.Errorf("failed to get software version: %w", dbError(err))
+	}
+
+	query := `
+		SELECT ` + softwareVersionColumns + ` FROM software_versions
+		WHERE software_id = $1 AND version = $2 AND organization_id = $3
+	`
+	result, err := scanSoftwareVersion(r.pool.QueryRow(ctx, query, softwareID, version, organizationID))
+	if err != nil {
+		return models.SoftwareVersion{}, fmt.Errorf("failed to get software version: %w", dbError(err))
+	}
+
+	return result, nil
+}
+
+// GetVersionAt retrieves the version of software that was current at a time, that is the
+// last version stored at or before it
+func (r *PostgresSoftwareRepository) GetVersionAt(ctx context.Context, softwareID string, at time.Time) (models.SoftwareVersion, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return models.SoftwareVersion{}, fmt.Errorf("failed to get software version: %w", dbError(err))
+	}
+
+	query := `
+		SELECT ` + softwareVersionColumns + ` FROM software_versions
+		WHERE software_id = $1 AND created_at <= $2 AND organization_id = $3
+		ORDER BY version DESC LIMIT 1
+	`
+	result, err := scanSoftwareVersion(r.pool.QueryRow(ctx, query, softwareID, at, organizationID))
 	if err != nil {
-		return fmt.Errorf("failed to delete software: %w", err)
+		return models.SoftwareVersion{}, fmt.Errorf("failed to get software version: %w", dbError(err))
+	}
+
+	return result, nil
+}
+
+// GetLatestVersion retrieves the last version of software, which of deleted software is
+// the version of its deletion
+func (r *PostgresSoftwareRepository) GetLatestVersion(ctx context.Context, softwareID string) (models.SoftwareVersion, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return models.SoftwareVersion{}, fmt.Errorf("failed to get software version: %w", dbError(err))
+	}
+
+	query := `
+		SELECT ` + softwareVersionColumns + ` FROM software_versions
+		WHERE software_id = $1 AND organization_id = $2
+		ORDER BY version DESC LIMIT 1
+	`
+	result, err := scanSoftwareVersion(r.pool.QueryRow(ctx, query, softwareID, organizationID))
+	if err != nil {
+		return models.SoftwareVersion{}, fmt.Errorf("failed to get software version: %w", dbError(err))
+	}
+
+	return result, nil
+}
+
+// undeleteStakeholders restores the stakeholders that were archived along with archived
+// software, within the transaction restoring it
+func (r *PostgresSoftwareRepository) undeleteStakeholders(ctx context.Context, tx pgx.Tx, organizationID, softwareID string) error {
+	query := `
+		UPDATE stakeholders SET deleted_at = NULL, deleted_by = NULL
+		FROM software
+		WHERE software.id = $1 AND software.organization_id = $2 AND software.deleted_at IS NOT NULL
+			AND stakeholders.software_id = software.id AND stakeholders.deleted_at = software.deleted_at
+	`
+	if _, err := tx.Exec(ctx, query, softwareID, organizationID); err != nil {
+		return fmt.Errorf("failed to undelete stakeholders: %w", dbError(err))
+	}
+	return nil
+}
+
+// createStakeholder assigns a stakeholder to newly created software within its transaction
+func createStakeholder(ctx context.Context, tx pgx.Tx, organizationID, softwareID string, stakeholder models.Stakeholder, now time.Time) error {
+	if stakeholder.ID == "" {
+		stakeholder.ID = generateID()
+	}
+
+	query := `
+		INSERT INTO stakeholders (
+			id, organization_id, software_id, user_id, role, foreign_key, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, NULLIF($6, ''), $7, $7
+		)
+	`
+
+	_, err := tx.Exec(ctx, query,
+		stakeholder.ID, organizationID, softwareID, stakeholder.UserID, stakeholder.Role,
+		stakeholder.ForeignKey, now,
+	)
+	return err
+}
+
+// createVersion stores a snapshot of software as its next version, made by the authenticated
+// user of the context, within the transaction of the change
+func (r *PostgresSoftwareRepository) createVersion(ctx context.Context, tx pgx.Tx, organizationID string, software models.Software, action models.SoftwareVersionAction) error {
+	snapshot, err := json.Marshal(software)
+	if err != nil {
+		return fmt.Errorf("failed to encode software snapshot: %w", dbError(err))
+	}
+
+	// Changes of the same software are serialized by the lock on its row, so the next
+	// version number is free
+	query := `
+		INSERT INTO software_versions (
+			id, organization_id, software_id, version, action, snapshot, user_id, created_at
+		)
+		SELECT $1, $2, $3, COALESCE(MAX(version), 0) + 1, $4, $5, NULLIF($6, '')::uuid, $7
+		FROM software_versions WHERE software_id = $3
+	`
+	_, err = tx.Exec(ctx, query,
+		generateID(), organizationID, software.ID, action, snapshot, currentUserID(ctx), time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create software version: %w", dbError(err))
 	}
 
 	return nil
 }
+
+// scanSoftware scans the softwareColumns of a row, followed by any extra destinations
+func scanSoftware(row pgx.Row, extra ...interface{}) (models.Software, error) {
+	var software models.Software
+	dest := []interface{}{
+		&software.ID, &software.ForeignKey, &software.DisplayName, &software.Description,
+		&software.SoftwareType, &software.SoftwareSubtype, &software.Vendor,
+		&software.Manufacturer, &software.InstallType, &software.ProductType,
+		&software.Context, &software.LifecycleStatus, &software.ImplementationStatus,
+		&software.CreatedAt, &software.UpdatedAt, &software.DeletedAt, &software.DeletedBy,
+	}
+	err := row.Scan(append(dest, extra...)...)
+	return software, err
+}
+
+// scanSoftwareVersion scans the softwareVersionColumns of a row
+func scanSoftwareVersion(row pgx.Row) (models.SoftwareVersion, error) {
+	var version models.SoftwareVersion
+	var snapshot []byte
+	err := row.Scan(
+		&version.ID, &version.SoftwareID, &version.Version, &version.Action, &snapshot,
+		&version.UserID, &version.CreatedAt,
+	)
+	if err != nil {
+		return models.SoftwareVersion{}, err
+	}
+
+	if err := json.Unmarshal(snapshot, &version.Snapshot); err != nil {
+		return models.SoftwareVersion{}, fmt.Errorf("failed to decode software snapshot: %w", dbError(err))
+	}
+	return version, nil
+}
@@ -0,0 +1,296 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"apm/internal/models"
+	"apm/internal/pagination"
+	"apm/internal/tenant"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Ensure implementation satisfies the interface
+var _ StakeholderRepository = (*PostgresStakeholderRepository)(nil)
+
+// PostgresStakeholderRepository implements StakeholderRepository using PostgreSQL. All methods
+// act on the stakeholders of the organization in the context, leaving out archived ones
+// unless stated otherwise.
+type PostgresStakeholderRepository struct {
+	pool   *pgxpool.Pool
+	logger *log.Logger
+}
+
+// NewPostgresStakeholderRepository creates a new PostgreSQL stakeholder repository
+func NewPostgresStakeholderRepository(pool *pgxpool.Pool) StakeholderRepository {
+	return &PostgresStakeholderRepository{
+		pool:   pool,
+		logger: log.New(log.Writer(), "[StakeholderRepo] ", log.LstdFlags),
+	}
+}
+
+// stakeholderColumns lists the columns read for a stakeholder
+const stakeholderColumns = `
+	id, COALESCE(foreign_key, ''), software_id, user_id, role, created_at, updated_at,
+	deleted_at, COALESCE(deleted_by::text, '')
+`
+
+// Create inserts a new stakeholder into the database
+func (r *PostgresStakeholderRepository) Create(ctx context.Context, stakeholder models.Stakeholder) (models.Stakeholder, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return models.Stakeholder{}, fmt.Errorf("failed to create stakeholder: %w", dbError(err))
+	}
+
+	// Generate a new ID if not provided
+	if stakeholder.ID == "" {
+		stakeholder.ID = generateID()
+	}
+
+	// Set timestamps
+	now := time.Now().UTC()
+	stakeholder.CreatedAt = now
+	stakeholder.UpdatedAt = now
+
+	query := `
+		INSERT INTO stakeholders (
+			id, organization_id, software_id, user_id, role, foreign_key, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8
+		)
+	`
+
+	_, err = r.pool.Exec(ctx, query,
+		stakeholder.ID, organizationID, stakeholder.SoftwareID, stakeholder.UserID, stakeholder.Role,
+		stakeholder.ForeignKey, stakeholder.CreatedAt, stakeholder.UpdatedAt,
+	)
+	if err != nil {
+		return models.Stakeholder{}, fmt.Errorf("failed to create stakeholder: %w", dbError(err))
+	}
+
+	return stakeholder, nil
+}
+
+// GetByID retrieves a stakeholder by its ID
+func (r *PostgresStakeholderRepository) GetByID(ctx context.Context, id string) (models.Stakeholder, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return models.Stakeholder{}, fmt.Errorf("failed to get stakeholder by ID: %w", dbError(err))
+	}
+
+	query := `SELECT ` + stakeholderColumns + ` FROM stakeholders WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL`
+	row := r.pool.QueryRow(ctx, query, id, organizationID)
+
+	stakeholder, err := scanStakeholder(row)
+	if err != nil {
+		return models.Stakeholder{}, fmt.Errorf("failed to get stakeholder by ID: %w", dbError(err))
+	}
+
+	return stakeholder, nil
+}
+
+// GetByUserID retrieves the stakeholder assignments of a user
+func (r *PostgresStakeholderRepository) GetByUserID(ctx context.Context, userID string) ([]models.Stakeholder, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stakeholders by user: %w", dbError(err))
+	}
+
+	query := `
+		SELECT ` + stakeholderColumns + ` FROM stakeholders
+		WHERE user_id = $1 AND organization_id = $2 AND deleted_at IS NULL
+		ORDER BY created_at
+	`
+	return r.list(ctx, query, userID, organizationID)
+}
+
+// GetBySoftwareID retrieves the stakeholders of software
+func (r *PostgresStakeholderRepository) GetBySoftwareID(ctx context.Context, softwareID string) ([]models.Stakeholder, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stakeholders by software: %w", dbError(err))
+	}
+
+	query := `
+		SELECT ` + stakeholderColumns + ` FROM stakeholders
+		WHERE software_id = $1 AND organization_id = $2 AND deleted_at IS NULL
+		ORDER BY role, created_at
+	`
+	return r.list(ctx, query, softwareID, organizationID)
+}
+
+// CountBySoftwareAndRole counts the stakeholders of software in a role
+func (r *PostgresStakeholderRepository) CountBySoftwareAndRole(ctx context.Context, softwareID string, role models.StakeholderRole) (int, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count stakeholders: %w", dbError(err))
+	}
+
+	query := `SELECT COUNT(*) FROM stakeholders WHERE software_id = $1 AND role = $2 AND organization_id = $3 AND deleted_at IS NULL`
+
+	var count int
+	if err := r.pool.QueryRow(ctx, query, softwareID, role, organizationID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count stakeholders: %w", dbError(err))
+	}
+
+	return count, nil
+}
+
+// CountSoleBusinessOwnerships counts the active software of which the user is the only
+// business owner
+func (r *PostgresStakeholderRepository) CountSoleBusinessOwnerships(ctx context.Context, userID string) (int, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count business ownerships: %w", dbError(err))
+	}
+
+	query := `
+		SELECT COUNT(*) FROM software s
+		WHERE s.organization_id = $1 AND lower(trim(s.lifecycle_status)) = $2 AND s.deleted_at IS NULL
+			AND EXISTS (
+				SELECT 1 FROM stakeholders o
+				WHERE o.software_id = s.id AND o.role = $3 AND o.user_id = $4 AND o.deleted_at IS NULL
+			)
+			AND NOT EXISTS (
+				SELECT 1 FROM stakeholders o
+				WHERE o.software_id = s.id AND o.role = $3 AND o.user_id <> $4 AND o.deleted_at IS NULL
+			)
+	`
+
+	var count int
+	err = r.pool.QueryRow(ctx, query, organizationID, models.LifecycleStatusActive, models.StakeholderRoleBusinessOwner, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count business ownerships: %w", dbError(err))
+	}
+
+	return count, nil
+}
+
+// List retrieves a page of stakeholders, most recent first, including archived ones if asked to
+func (r *PostgresStakeholderRepository) List(ctx context.Context, includeArchived bool, page pagination.Request) (pagination.Page[models.Stakeholder], error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return pagination.Page[models.Stakeholder]{}, fmt.Errorf("failed to list stakeholders: %w", dbError(err))
+	}
+
+	conds := newConditions(`organization_id = ?`, organizationID)
+	if !includeArchived {
+		conds.add(`deleted_at IS NULL`)
+	}
+
+	query := pageQuery[models.Stakeholder]{
+		columns: stakeholderColumns,
+		from:    "stakeholders",
+		conds:   conds,
+		order:   []sortKey{{expr: "created_at", descending: true}, {expr: "id", descending: true}},
+		keys: func(stakeholder models.Stakeholder) []string {
+			return []string{timeKey(stakeholder.CreatedAt), stakeholder.ID}
+		},
+		scan: scanStakeholder,
+	}
+
+	stakeholders, err := listPage(ctx, r.pool, query, page)
+	if err != nil {
+		return pagination.Page[models.Stakeholder]{}, fmt.Errorf("failed to list stakeholders: %w", dbError(err))
+	}
+
+	return stakeholders, nil
+}
+
+// Update updates an existing stakeholder
+func (r *PostgresStakeholderRepository) Update(ctx context.Context, stakeholder models.Stakeholder) error {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update stakeholder: %w", dbError(err))
+	}
+
+	// Update the UpdatedAt timestamp
+	stakeholder.UpdatedAt = time.Now().UTC()
+
+	query := `
+		UPDATE stakeholders SET
+			role = $2,
+			foreign_key = NULLIF($3, ''),
+			updated_at = $4
+		WHERE id = $1 AND organization_id = $5 AND deleted_at IS NULL
+	`
+
+	_, err = r.pool.Exec(ctx, query,
+		stakeholder.ID, stakeholder.Role, stakeholder.ForeignKey, stakeholder.UpdatedAt, organizationID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update stakeholder: %w", dbError(err))
+	}
+
+	return nil
+}
+
+// Delete archives a stakeholder by its ID, on behalf of the authenticated user of the context
+func (r *PostgresStakeholderRepository) Delete(ctx context.Context, id string) error {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete stakeholder: %w", dbError(err))
+	}
+
+	query := `
+		UPDATE stakeholders SET deleted_at = $3, deleted_by = NULLIF($4, '')::uuid
+		WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL
+	`
+	_, err = r.pool.Exec(ctx, query, id, organizationID, time.Now().UTC(), currentUserID(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to delete stakeholder: %w", dbError(err))
+	}
+
+	return nil
+}
+
+// PurgeArchived permanently deletes the stakeholders of all organizations that were archived
+// before a time, returning how many were deleted
+func (r *PostgresStakeholderRepository) PurgeArchived(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM stakeholders WHERE deleted_at IS NOT NULL AND deleted_at < $1`
+	tag, err := r.pool.Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge archived stakeholders: %w", dbError(err))
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// list runs a query for stakeholders
+func (r *PostgresStakeholderRepository) list(ctx context.Context, query string, args ...interface{}) ([]models.Stakeholder, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stakeholders: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	var stakeholders []models.Stakeholder
+	for rows.Next() {
+		stakeholder, err := scanStakeholder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stakeholder: %w", dbError(err))
+		}
+		stakeholders = append(stakeholders, stakeholder)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", dbError(err))
+	}
+
+	return stakeholders, nil
+}
+
+// scanStakeholder scans the stakeholderColumns of a row
+func scanStakeholder(row pgx.Row) (models.Stakeholder, error) {
+	var stakeholder models.Stakeholder
+	err := row.Scan(
+		&stakeholder.ID, &stakeholder.ForeignKey, &stakeholder.SoftwareID, &stakeholder.UserID,
+		&stakeholder.Role, &stakeholder.CreatedAt, &stakeholder.UpdatedAt,
+		&stakeholder.DeletedAt, &stakeholder.DeletedBy,
+	)
+	return stakeholder, err
+}
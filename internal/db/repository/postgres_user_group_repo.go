@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"apm/internal/models"
+	"apm/internal/pagination"
+	"apm/internal/tenant"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Ensure implementation satisfies the interface
+var _ UserGroupRepository = (*PostgresUserGroupRepository)(nil)
+
+// PostgresUserGroupRepository implements UserGroupRepository using PostgreSQL. All methods act
+// on the user groups of the organization in the context.
+type PostgresUserGroupRepository struct {
+	pool   *pgxpool.Pool
+	logger *log.Logger
+}
+
+// NewPostgresUserGroupRepository creates a new PostgreSQL user group repository
+func NewPostgresUserGroupRepository(pool *pgxpool.Pool) UserGroupRepository {
+	return &PostgresUserGroupRepository{
+		pool:   pool,
+		logger: log.New(log.Writer(), "[UserGroupRepo] ", log.LstdFlags),
+	}
+}
+
+// userGroupColumns lists the columns read for a user group
+const userGroupColumns = `id, display_name, created_at, updated_at`
+
+// Create inserts a new user group into the database
+func (r *PostgresUserGroupRepository) Create(ctx context.Context, group models.UserGroup) (models.UserGroup, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return models.UserGroup{}, fmt.Errorf("failed to create user group: %w", dbError(err))
+	}
+
+	// Generate a new ID if not provided
+	if group.ID == "" {
+		group.ID = generateID()
+	}
+
+	// Set timestamps
+	now := time.Now().UTC()
+	group.CreatedAt = now
+	group.UpdatedAt = now
+
+	query := `
+		INSERT INTO user_groups (id, organization_id, display_name, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err = r.pool.Exec(ctx, query, group.ID, organizationID, group.DisplayName, group.CreatedAt, group.UpdatedAt)
+	if err != nil {
+		return models.UserGroup{}, fmt.Errorf("failed to create user group: %w", dbError(err))
+	}
+
+	return group, nil
+}
+
+// GetByID retrieves a user group by its ID
+func (r *PostgresUserGroupRepository) GetByID(ctx context.Context, id string) (models.UserGroup, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return models.UserGroup{}, fmt.Errorf("failed to get user group by ID: %w", dbError(err))
+	}
+
+	query := `SELECT ` + userGroupColumns + ` FROM user_groups WHERE id = $1 AND organization_id = $2`
+	row := r.pool.QueryRow(ctx, query, id, organizationID)
+
+	group, err := scanUserGroup(row)
+	if err != nil {
+		return models.UserGroup{}, fmt.Errorf("failed to get user group by ID: %w", dbError(err))
+	}
+
+	return group, nil
+}
+
+// List retrieves a page of user groups ordered by name
+func (r *PostgresUserGroupRepository) List(ctx context.Context, page pagination.Request) (pagination.Page[models.UserGroup], error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return pagination.Page[models.UserGroup]{}, fmt.Errorf("failed to list user groups: %w", dbError(err))
+	}
+
+	query := pageQuery[models.UserGroup]{
+		columns: userGroupColumns,
+		from:    "user_groups",
+		conds:   newConditions(`organization_id = ?`, organizationID),
+		order:   []sortKey{{expr: "display_name"}, {expr: "id"}},
+		keys: func(group models.UserGroup) []string {
+			return []string{group.DisplayName, group.ID}
+		},
+		scan: scanUserGroup,
+	}
+
+	groups, err := listPage(ctx, r.pool, query, page)
+	if err != nil {
+		return pagination.Page[models.UserGroup]{}, fmt.Errorf("failed to list user groups: %w", dbError(err))
+	}
+
+	return groups, nil
+}
+
+// Update updates an existing user group
+func (r *PostgresUserGroupRepository) Update(ctx context.Context, group models.UserGroup) error {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update user group: %w", dbError(err))
+	}
+
+	// Update the UpdatedAt timestamp
+	group.UpdatedAt = time.Now().UTC()
+
+	query := `UPDATE user_groups SET display_name = $2, updated_at = $3 WHERE id = $1 AND organization_id = $4`
+	_, err = r.pool.Exec(ctx, query, group.ID, group.DisplayName, group.UpdatedAt, organizationID)
+	if err != nil {
+		return fmt.Errorf("failed to update user group: %w", dbError(err))
+	}
+
+	return nil
+}
+
+// Delete removes a user group by its ID, together with its memberships
+func (r *PostgresUserGroupRepository) Delete(ctx context.Context, id string) error {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete user group: %w", dbError(err))
+	}
+
+	query := `DELETE FROM user_groups WHERE id = $1 AND organization_id = $2`
+	_, err = r.pool.Exec(ctx, query, id, organizationID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user group: %w", dbError(err))
+	}
+
+	return nil
+}
+
+// AddMember adds a user to a user group; adding a member again has no effect. Both must
+// belong to the organization in the context.
+func (r *PostgresUserGroupRepository) AddMember(ctx context.Context, groupID, userID string) error {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to add user group member: %w", dbError(err))
+	}
+
+	query := `
+		INSERT INTO user_to_groups (user_id, user_group_id, created_at)
+		SELECT u.id, g.id, $4
+		FROM users u, user_groups g
+		WHERE u.id = $1 AND g.id = $2 AND u.organization_id = $3 AND g.organization_id = $3
+		ON CONFLICT DO NOTHING
+	`
+	_, err = r.pool.Exec(ctx, query, userID, groupID, organizationID, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to add user group member: %w", dbError(err))
+	}
+
+	return nil
+}
+
+// RemoveMember removes a user from a user group, reporting whether they were a member
+func (r *PostgresUserGroupRepository) RemoveMember(ctx context.Context, groupID, userID string) (bool, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove user group member: %w", dbError(err))
+	}
+
+	query := `
+		DELETE FROM user_to_groups
+		WHERE user_id = $1 AND user_group_id = (
+			SELECT id FROM user_groups WHERE id = $2 AND organization_id = $3
+		)
+	`
+	tag, err := r.pool.Exec(ctx, query, userID, groupID, organizationID)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove user group member: %w", dbError(err))
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// ListMembers retrieves a page of the users in a user group ordered by name
+func (r *PostgresUserGroupRepository) ListMembers(ctx context.Context, groupID string, page pagination.Request) (pagination.Page[models.User], error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return pagination.Page[models.User]{}, fmt.Errorf("failed to list user group members: %w", dbError(err))
+	}
+
+	conds := newConditions(`organization_id = ?`, organizationID)
+	conds.add(`id IN (SELECT user_id FROM user_to_groups WHERE user_group_id = ?)`, groupID)
+
+	users, err := listPage(ctx, r.pool, userPageQuery(conds), page)
+	if err != nil {
+		return pagination.Page[models.User]{}, fmt.Errorf("failed to list user group members: %w", dbError(err))
+	}
+
+	return users, nil
+}
+
+// ListByUser retrieves the user groups a user is a member of, ordered by name
+func (r *PostgresUserGroupRepository) ListByUser(ctx context.Context, userID string) ([]models.UserGroup, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user groups of user: %w", dbError(err))
+	}
+
+	query := `
+		SELECT ` + userGroupColumns + ` FROM user_groups
+		WHERE organization_id = $1 AND id IN (
+			SELECT user_group_id FROM user_to_groups WHERE user_id = $2
+		)
+		ORDER BY display_name
+	`
+	return r.list(ctx, query, organizationID, userID)
+}
+
+// list runs a query for user groups
+func (r *PostgresUserGroupRepository) list(ctx context.Context, query string, args ...interface{}) ([]models.UserGroup, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user groups: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	var groups []models.UserGroup
+	for rows.Next() {
+		group, err := scanUserGroup(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user group: %w", dbError(err))
+		}
+		groups = append(groups, group)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", dbError(err))
+	}
+
+	return groups, nil
+}
+
+// scanUserGroup scans the userGroupColumns of a row
+func scanUserGroup(row pgx.Row) (models.UserGroup, error) {
+	var group models.UserGroup
+	err := row.Scan(&group.ID, &group.DisplayName, &group.CreatedAt, &group.UpdatedAt)
+	return group, err
+}
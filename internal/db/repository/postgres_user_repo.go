@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"apm/internal/models"
+	"apm/internal/pagination"
+	"apm/internal/tenant"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Ensure implementation satisfies the interface
+var _ UserRepository = (*PostgresUserRepository)(nil)
+
+// PostgresUserRepository implements UserRepository using PostgreSQL
+type PostgresUserRepository struct {
+	pool   *pgxpool.Pool
+	logger *log.Logger
+}
+
+// NewPostgresUserRepository creates a new PostgreSQL user repository
+func NewPostgresUserRepository(pool *pgxpool.Pool) UserRepository {
+	return &PostgresUserRepository{
+		pool:   pool,
+		logger: log.New(log.Writer(), "[UserRepo] ", log.LstdFlags),
+	}
+}
+
+// userColumns lists the columns read for a user
+const userColumns = `
+	id, organization_id, email, password_hash, first_name, last_name, role,
+	COALESCE(avatar_url, ''), mfa_enabled, COALESCE(mfa_secret, ''), mfa_last_step, mfa_failed_attempts,
+	mfa_locked_until, deactivated_at, created_at, updated_at
+`
+
+// Create inserts a new user into the database
+func (r *PostgresUserRepository) Create(ctx context.Context, user models.User) (models.User, error) {
+	// Generate a new ID if not provided
+	if user.ID == "" {
+		user.ID = generateID()
+	}
+
+	// Set timestamps
+	now := time.Now().UTC()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	query := `
+		INSERT INTO users (
+			id, organization_id, email, password_hash, first_name, last_name, role,
+			avatar_url, mfa_enabled, mfa_secret, deactivated_at, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9, NULLIF($10, ''), $11, $12, $13
+		) RETURNING ` + userColumns
+
+	row := r.pool.QueryRow(ctx, query,
+		user.ID, user.OrganizationID, user.Email, user.PasswordHash, user.FirstName, user.LastName,
+		user.Role, user.AvatarURL, user.MFAEnabled, user.MFASecret, user.DeactivatedAt,
+		user.CreatedAt, user.UpdatedAt,
+	)
+
+	created, err := scanUser(row)
+	if err != nil {
+		return models.User{}, fmt.Errorf("failed to create user: %w", dbError(err))
+	}
+
+	return created, nil
+}
+
+// GetByID retrieves a user by their ID
+func (r *PostgresUserRepository) GetByID(ctx context.Context, id string) (models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+	row := r.pool.QueryRow(ctx, query, id)
+
+	user, err := scanUser(row)
+	if err != nil {
+		return models.User{}, fmt.Errorf("failed to get user by ID: %w", dbError(err))
+	}
+
+	return user, nil
+}
+
+// GetByEmail retrieves a user by their email address, ignoring case
+func (r *PostgresUserRepository) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE lower(email) = lower($1)`
+	row := r.pool.QueryRow(ctx, query, email)
+
+	user, err := scanUser(row)
+	if err != nil {
+		return models.User{}, fmt.Errorf("failed to get user by email: %w", dbError(err))
+	}
+
+	return user, nil
+}
+
+// List retrieves a page of the users of the organization in the context, ordered by name
+func (r *PostgresUserRepository) List(ctx context.Context, page pagination.Request) (pagination.Page[models.User], error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return pagination.Page[models.User]{}, fmt.Errorf("failed to list users: %w", dbError(err))
+	}
+
+	users, err := listPage(ctx, r.pool, userPageQuery(newConditions(`organization_id = ?`, organizationID)), page)
+	if err != nil {
+		return pagination.Page[models.User]{}, fmt.Errorf("failed to list users: %w", dbError(err))
+	}
+
+	return users, nil
+}
+
+// Update updates an existing user in the database
+func (r *PostgresUserRepository) Update(ctx context.Context, user models.User) error {
+	// Update the UpdatedAt timestamp
+	user.UpdatedAt = time.Now().UTC()
+
+	query := `
+		UPDATE users SET
+			organization_id = $2,
+			email = $3,
+			password_hash = $4,
+			first_name = $5,
+			last_name = $6,
+			role = $7,
+			avatar_url = NULLIF($8, ''),
+			mfa_enabled = $9,
+			mfa_secret = NULLIF($10, ''),
+			deactivated_at = $11,
+			updated_at = $12
+		WHERE id = $1
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		user.ID, user.OrganizationID, user.Email, user.PasswordHash, user.FirstName, user.LastName,
+		user.Role, user.AvatarURL, user.MFAEnabled, user.MFASecret, user.DeactivatedAt, user.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", dbError(err))
+	}
+
+	return nil
+}
+
+// Delete removes a user from the database
+func (r *PostgresUserRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM users WHERE id = $1`
+	_, err := r.pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", dbError(err))
+	}
+
+	return nil
+}
+
+// CountAll counts all users of an organization, deactivated users included
+func (r *PostgresUserRepository) CountAll(ctx context.Context, organizationID string) (int, error) {
+	query := `SELECT COUNT(*) FROM users WHERE organization_id = $1`
+
+	var count int
+	if err := r.pool.QueryRow(ctx, query, organizationID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", dbError(err))
+	}
+
+	return count, nil
+}
+
+// CountByRole counts the active users of an organization per role
+func (r *PostgresUserRepository) CountByRole(ctx context.Context, organizationID string) (map[models.UserRole]int, error) {
+	query := `
+		SELECT role, COUNT(*) FROM users
+		WHERE organization_id = $1 AND deactivated_at IS NULL
+		GROUP BY role
+	`
+	rows, err := r.pool.Query(ctx, query, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count users: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	counts := make(map[models.UserRole]int)
+	for rows.Next() {
+		var role models.UserRole
+		var count int
+		if err := rows.Scan(&role, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan user count: %w", dbError(err))
+		}
+		counts[role] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", dbError(err))
+	}
+
+	return counts, nil
+}
+
+// UseMFAStep records the TOTP time step of an accepted code, ending any failed attempts. It
+// reports false, recording nothing, if a code of the step or a later one was accepted before.
+func (r *PostgresUserRepository) UseMFAStep(ctx context.Context, id string, step int64) (bool, error) {
+	query := `
+		UPDATE users SET mfa_last_step = $2, mfa_failed_attempts = 0, mfa_locked_until = NULL
+		WHERE id = $1 AND mfa_last_step < $2
+	`
+	tag, err := r.pool.Exec(ctx, query, id, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to use MFA step: %w", dbError(err))
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// RecordMFAFailure counts a failed MFA verification of a user. The user is locked until
+// lockedUntil once the failures reach maxFailures, and counting starts over.
+func (r *PostgresUserRepository) RecordMFAFailure(ctx context.Context, id string, maxFailures int, lockedUntil time.Time) error {
+	query := `
+		UPDATE users SET
+			mfa_failed_attempts = CASE WHEN mfa_failed_attempts + 1 >= $2 THEN 0 ELSE mfa_failed_attempts + 1 END,
+			mfa_locked_until = CASE WHEN mfa_failed_attempts + 1 >= $2 THEN $3 ELSE mfa_locked_until END
+		WHERE id = $1
+	`
+	_, err := r.pool.Exec(ctx, query, id, maxFailures, lockedUntil.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to record MFA failure: %w", dbError(err))
+	}
+
+	return nil
+}
+
+// ResetMFAFailures ends the failed MFA verifications of a user
+func (r *PostgresUserRepository) ResetMFAFailures(ctx context.Context, id string) error {
+	query := `UPDATE users SET mfa_failed_attempts = 0, mfa_locked_until = NULL WHERE id = $1`
+	_, err := r.pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to reset MFA failures: %w", dbError(err))
+	}
+
+	return nil
+}
+
+// scanUser scans the userColumns of a row
+func scanUser(row pgx.Row) (models.User, error) {
+	var user models.User
+	err := row.Scan(
+		&user.ID, &user.OrganizationID, &user.Email, &user.PasswordHash, &user.FirstName, &user.LastName,
+		&user.Role, &user.AvatarURL, &user.MFAEnabled, &user.MFASecret, &user.MFALastStep, &user.MFAFailures,
+		&user.MFALockedUntil, &user.DeactivatedAt, &user.CreatedAt, &user.UpdatedAt,
+	)
+	return user, err
+}
+
+// userPageQuery returns the query for a page of the users matching conditions, ordered by name
+func userPageQuery(conds *conditions) pageQuery[models.User] {
+	return pageQuery[models.User]{
+		columns: userColumns,
+		from:    "users",
+		conds:   conds,
+		order:   []sortKey{{expr: "last_name"}, {expr: "first_name"}, {expr: "id"}},
+		keys: func(user models.User) []string {
+			return []string{user.LastName, user.FirstName, user.ID}
+		},
+		scan: scanUser,
+	}
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// conditions collects the conditions of a WHERE clause and their arguments. Conditions are
+// fixed SQL written with ? placeholders, which are numbered after the arguments of the
+// conditions before them, so values only ever reach the database as arguments.
+type conditions struct {
+	clauses []string
+	args    []interface{}
+}
+
+// newConditions creates conditions starting with a first condition
+func newConditions(clause string, args ...interface{}) *conditions {
+	c := &conditions{}
+	c.add(clause, args...)
+	return c
+}
+
+// add adds a condition, with an argument for each of its placeholders
+func (c *conditions) add(clause string, args ...interface{}) {
+	c.clauses = append(c.clauses, c.bind(clause, args...))
+}
+
+// bind numbers the placeholders of a fragment of SQL, adding their arguments
+func (c *conditions) bind(fragment string, args ...interface{}) string {
+	if count := strings.Count(fragment, "?"); count != len(args) {
+		panic(fmt.Sprintf("query fragment %q has %d placeholders but %d arguments", fragment, count, len(args)))
+	}
+
+	var bound strings.Builder
+	for _, arg := range args {
+		before, after, _ := strings.Cut(fragment, "?")
+		c.args = append(c.args, arg)
+		bound.WriteString(before)
+		bound.WriteString("$" + strconv.Itoa(len(c.args)))
+		fragment = after
+	}
+	bound.WriteString(fragment)
+	return bound.String()
+}
+
+// where returns the WHERE clause of the conditions
+func (c *conditions) where() string {
+	if len(c.clauses) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(c.clauses, " AND ")
+}
+
+// escapeLike escapes the wildcards of LIKE patterns in a value, for matching it literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
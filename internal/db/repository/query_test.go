@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConditions(t *testing.T) {
+	conds := newConditions("organization_id = ?", "org-a")
+	conds.add("(name ILIKE ? OR vendor ILIKE ?)", "%led%", "%led%")
+	conds.add("deleted_at IS NULL")
+	limit := conds.bind(" LIMIT ? OFFSET ?", 11, 20)
+
+	if want := " WHERE organization_id = $1 AND (name ILIKE $2 OR vendor ILIKE $3) AND deleted_at IS NULL"; conds.where() != want {
+		t.Errorf("where() = %q, want %q", conds.where(), want)
+	}
+	if want := " LIMIT $4 OFFSET $5"; limit != want {
+		t.Errorf("bind() = %q, want %q", limit, want)
+	}
+	if want := []interface{}{"org-a", "%led%", "%led%", 11, 20}; !reflect.DeepEqual(conds.args, want) {
+		t.Errorf("args = %v, want %v", conds.args, want)
+	}
+
+	if got := (&conditions{}).where(); got != "" {
+		t.Errorf("where() without conditions = %q, want none", got)
+	}
+}
+
+func TestConditionsBindPanicsOnArgumentCount(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("bind() with fewer arguments than placeholders did not panic")
+		}
+	}()
+	(&conditions{}).bind("name = ? AND id = ?", "Ledger")
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"ledger", "ledger"},
+		{"100%", `100\%`},
+		{"snake_case", `snake\_case`},
+		{`back\slash`, `back\\slash`},
+		{`\%_`, `\\\%\_`},
+	}
+
+	for _, tt := range tests {
+		if got := escapeLike(tt.value); got != tt.want {
+			t.Errorf("escapeLike(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Package mail sends the emails of the application, such as password reset links.
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Message is an email to a single recipient
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Ensure implementations satisfy the interface
+var (
+	_ Mailer = (*LogMailer)(nil)
+	_ Mailer = (*FileMailer)(nil)
+)
+
+// LogMailer writes emails to a logger instead of sending them, for local development
+type LogMailer struct {
+	logger *log.Logger
+}
+
+// NewLogMailer creates a mailer that writes emails to the logger
+func NewLogMailer(logger *log.Logger) *LogMailer {
+	return &LogMailer{logger: logger}
+}
+
+// Send writes the email to the log
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	m.logger.Printf("Email to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+// FileMailer writes each email to a file in a directory instead of sending it, so that tests
+// and local setups can read the links in them
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+// NewFileMailer creates a mailer that writes emails as .eml files to the directory, which is
+// created if it does not exist
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+// Send writes the email to a new file named after the time and recipient
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	now := time.Now().UTC()
+	recipient := strings.NewReplacer("@", "_at_", "/", "_", "\\", "_").Replace(msg.To)
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102T150405.000000000"), recipient)
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "From: %s\r\n", m.from)
+	fmt.Fprintf(&content, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&content, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&content, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	content.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	content.WriteString(msg.Body)
+
+	if err := os.WriteFile(filepath.Join(m.dir, name), []byte(content.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	return nil
+}
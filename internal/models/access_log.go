@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+)
+
+// AccessEvent represents the kind of access recorded in the access log
+type AccessEvent string
+
+const (
+	AccessEventLogin   AccessEvent = "login"
+	AccessEventRequest AccessEvent = "request"
+)
+
+// AccessLog represents a login, successful or failed, or an authenticated request. Failed
+// logins with the email of no user have neither a user nor a role.
+type AccessLog struct {
+	ID             string      `json:"id"`
+	OrganizationID string      `json:"organization_id"`
+	UserID         string      `json:"user_id"`
+	Role           UserRole    `json:"role"`
+	Event          AccessEvent `json:"event"`
+	Email          string      `json:"email"`
+	Success        bool        `json:"success"`
+	IPAddress      string      `json:"ip_address"`
+	UserAgent      string      `json:"user_agent"`
+	Method         string      `json:"method"`
+	Path           string      `json:"path"`
+	Status         int         `json:"status"`
+	Timestamp      time.Time   `json:"timestamp"`
+}
+
+// AccessLogFilter narrows the access history to a user, an IP address and a time range;
+// empty fields do not narrow it
+type AccessLogFilter struct {
+	UserID    string
+	IPAddress string
+	From      *time.Time
+	To        *time.Time
+}
+
+// AccessLogResponse represents the response when returning access log entries
+type AccessLogResponse struct {
+	ID        string      `json:"id"`
+	UserID    string      `json:"user_id,omitempty"`
+	Role      UserRole    `json:"role,omitempty"`
+	Event     AccessEvent `json:"event"`
+	Email     string      `json:"email,omitempty"`
+	Success   bool        `json:"success"`
+	IPAddress string      `json:"ip_address"`
+	UserAgent string      `json:"user_agent,omitempty"`
+	Method    string      `json:"method,omitempty"`
+	Path      string      `json:"path,omitempty"`
+	Status    int         `json:"status,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+)
+
+// ClassificationField identifies the software field a classification suggestion applies to
+type ClassificationField string
+
+// Classification fields
+const (
+	ClassificationFieldSoftwareType       ClassificationField = "software_type"
+	ClassificationFieldSoftwareSubtype    ClassificationField = "software_subtype"
+	ClassificationFieldFunctionalCategory ClassificationField = "functional_category"
+	ClassificationFieldVendor             ClassificationField = "vendor"
+)
+
+// SuggestionStatus represents the review state of a classification suggestion
+type SuggestionStatus string
+
+// Suggestion statuses
+const (
+	SuggestionPending  SuggestionStatus = "pending"
+	SuggestionAccepted SuggestionStatus = "accepted"
+	SuggestionRejected SuggestionStatus = "rejected"
+)
+
+// ClassificationSuggestion represents a classifier's proposed value for a field of a software record
+type ClassificationSuggestion struct {
+	ID         string              `json:"id"`
+	SoftwareID string              `json:"software_id"`
+	Classifier string              `json:"classifier"`
+	Field      ClassificationField `json:"field"`
+	Value      string              `json:"value"`
+	Confidence float64             `json:"confidence"`
+	Status     SuggestionStatus    `json:"status"`
+	ReviewedBy string              `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time          `json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time           `json:"created_at"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+}
+
+// ClassificationSuggestionResponse represents the response when returning classification suggestion data
+type ClassificationSuggestionResponse struct {
+	ID         string              `json:"id"`
+	SoftwareID string              `json:"software_id"`
+	Classifier string              `json:"classifier"`
+	Field      ClassificationField `json:"field"`
+	Value      string              `json:"value"`
+	Confidence float64             `json:"confidence"`
+	Status     SuggestionStatus    `json:"status"`
+	ReviewedBy string              `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time          `json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time           `json:"created_at"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+}
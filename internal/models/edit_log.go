@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+)
+
+// EditAction represents the kind of change recorded in the audit trail
+type EditAction string
+
+const (
+	EditActionCreate EditAction = "create"
+	EditActionUpdate EditAction = "update"
+	EditActionDelete EditAction = "delete"
+)
+
+// EditLog represents the change of a single field of a record in the audit trail. The fields
+// changed together share a change ID.
+type EditLog struct {
+	ID         string     `json:"id"`
+	ChangeID   string     `json:"change_id"`
+	UserID     string     `json:"user_id"`
+	EntityType string     `json:"entity_type"`
+	EntityID   string     `json:"entity_id"`
+	Action     EditAction `json:"action"`
+	FieldName  string     `json:"field_name"`
+	OldValue   string     `json:"old_value"`
+	NewValue   string     `json:"new_value"`
+	Timestamp  time.Time  `json:"timestamp"`
+}
+
+// EditLogResponse represents the response when returning audit trail entries
+type EditLogResponse struct {
+	ID         string     `json:"id"`
+	ChangeID   string     `json:"change_id,omitempty"`
+	UserID     string     `json:"user_id,omitempty"`
+	EntityType string     `json:"entity_type"`
+	EntityID   string     `json:"entity_id"`
+	Action     EditAction `json:"action"`
+	FieldName  string     `json:"field_name"`
+	OldValue   string     `json:"old_value,omitempty"`
+	NewValue   string     `json:"new_value,omitempty"`
+	Timestamp  time.Time  `json:"timestamp"`
+}
@@ -0,0 +1,208 @@
+package models
+
+import (
+	"time"
+)
+
+// ImportRowStatus represents the outcome of importing a single row
+type ImportRowStatus string
+
+// Import row statuses
+const (
+	ImportRowCreated ImportRowStatus = "created"
+	ImportRowUpdated ImportRowStatus = "updated"
+	ImportRowSkipped ImportRowStatus = "skipped"
+	ImportRowFlagged ImportRowStatus = "flagged"
+	ImportRowFailed  ImportRowStatus = "failed"
+)
+
+// DuplicateAction represents what an import does with a row that matches existing software
+type DuplicateAction string
+
+// Duplicate actions
+const (
+	DuplicateActionSkip   DuplicateAction = "skip"
+	DuplicateActionUpdate DuplicateAction = "update"
+	DuplicateActionFlag   DuplicateAction = "flag"
+)
+
+// ImportOptions represents the settings applied while importing a file
+type ImportOptions struct {
+	MappingProfileID string          `json:"mapping_profile_id,omitempty"`
+	DuplicateAction  DuplicateAction `json:"duplicate_action,omitempty" validate:"omitempty,oneof=skip update flag"`
+}
+
+// ImportRowMatch represents existing software that an imported row may duplicate
+type ImportRowMatch struct {
+	SoftwareID  string  `json:"software_id"`
+	DisplayName string  `json:"display_name"`
+	Vendor      string  `json:"vendor,omitempty"`
+	MatchedOn   string  `json:"matched_on"`
+	Similarity  float64 `json:"similarity"`
+}
+
+// ImportRowResult represents the result of importing a single CSV row
+type ImportRowResult struct {
+	Row         int              `json:"row"`
+	Status      ImportRowStatus  `json:"status"`
+	SoftwareID  string           `json:"software_id,omitempty"`
+	DisplayName string           `json:"display_name,omitempty"`
+	Matches     []ImportRowMatch `json:"matches,omitempty"`
+	Errors      []string         `json:"errors,omitempty"`
+}
+
+// ImportReport represents the per-row report of a software import
+type ImportReport struct {
+	TotalRows      int               `json:"total_rows"`
+	Created        int               `json:"created"`
+	Updated        int               `json:"updated"`
+	Skipped        int               `json:"skipped"`
+	Flagged        int               `json:"flagged"`
+	Failed         int               `json:"failed"`
+	IgnoredColumns []string          `json:"ignored_columns,omitempty"`
+	Rows           []ImportRowResult `json:"rows"`
+}
+
+// AddRow appends a row result to the report and updates the counters
+func (r *ImportReport) AddRow(row ImportRowResult) {
+	r.TotalRows++
+	switch row.Status {
+	case ImportRowCreated:
+		r.Created++
+	case ImportRowUpdated:
+		r.Updated++
+	case ImportRowSkipped:
+		r.Skipped++
+	case ImportRowFlagged:
+		r.Flagged++
+	case ImportRowFailed:
+		r.Failed++
+	}
+	r.Rows = append(r.Rows, row)
+}
+
+// ImportJobStatus represents the processing state of an import job
+type ImportJobStatus string
+
+// Import job statuses
+const (
+	ImportJobPending ImportJobStatus = "pending"
+	ImportJobRunning ImportJobStatus = "running"
+	ImportJobDone    ImportJobStatus = "done"
+	ImportJobFailed  ImportJobStatus = "failed"
+)
+
+// ImportJob represents an uploaded import file and the result of processing it
+type ImportJob struct {
+	ID               string          `json:"id"`
+	FileName         string          `json:"file_name"`
+	FileContent      []byte          `json:"-"`
+	UploadedBy       string          `json:"uploaded_by,omitempty"`
+	Status           ImportJobStatus `json:"status"`
+	TotalRows        int             `json:"total_rows"`
+	CreatedRows      int             `json:"created_rows"`
+	UpdatedRows      int             `json:"updated_rows"`
+	SkippedRows      int             `json:"skipped_rows"`
+	FlaggedRows      int             `json:"flagged_rows"`
+	FailedRows       int             `json:"failed_rows"`
+	Report           *ImportReport   `json:"report,omitempty"`
+	ErrorMessage     string          `json:"error_message,omitempty"`
+	RerunOf          string          `json:"rerun_of,omitempty"`
+	MappingProfileID string          `json:"mapping_profile_id,omitempty"`
+	DuplicateAction  DuplicateAction `json:"duplicate_action"`
+	StartedAt        *time.Time      `json:"started_at,omitempty"`
+	FinishedAt       *time.Time      `json:"finished_at,omitempty"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+// CreateImportJobRequest represents the request to start a new import job
+type CreateImportJobRequest struct {
+	FileName         string          `json:"file_name" validate:"required"`
+	FileContent      []byte          `json:"-" validate:"required"`
+	UploadedBy       string          `json:"uploaded_by,omitempty"`
+	MappingProfileID string          `json:"mapping_profile_id,omitempty"`
+	DuplicateAction  DuplicateAction `json:"duplicate_action,omitempty" validate:"omitempty,oneof=skip update flag"`
+}
+
+// ImportJobResponse represents the response when returning import job data
+type ImportJobResponse struct {
+	ID               string          `json:"id"`
+	FileName         string          `json:"file_name"`
+	UploadedBy       string          `json:"uploaded_by,omitempty"`
+	Status           ImportJobStatus `json:"status"`
+	TotalRows        int             `json:"total_rows"`
+	CreatedRows      int             `json:"created_rows"`
+	UpdatedRows      int             `json:"updated_rows"`
+	SkippedRows      int             `json:"skipped_rows"`
+	FlaggedRows      int             `json:"flagged_rows"`
+	FailedRows       int             `json:"failed_rows"`
+	Report           *ImportReport   `json:"report,omitempty"`
+	ErrorMessage     string          `json:"error_message,omitempty"`
+	RerunOf          string          `json:"rerun_of,omitempty"`
+	MappingProfileID string          `json:"mapping_profile_id,omitempty"`
+	DuplicateAction  DuplicateAction `json:"duplicate_action"`
+	StartedAt        *time.Time      `json:"started_at,omitempty"`
+	FinishedAt       *time.Time      `json:"finished_at,omitempty"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+// ImportFile represents the original file uploaded for an import job
+type ImportFile struct {
+	FileName string `json:"file_name"`
+	Content  []byte `json:"-"`
+}
+
+// ImportMappingProfile represents a saved translation of a source file's columns and values onto software fields
+type ImportMappingProfile struct {
+	ID            string                       `json:"id"`
+	Name          string                       `json:"name"`
+	Description   string                       `json:"description"`
+	ColumnMapping map[string]string            `json:"column_mapping"`
+	ValueMapping  map[string]map[string]string `json:"value_mapping"`
+	CreatedAt     time.Time                    `json:"created_at"`
+	UpdatedAt     time.Time                    `json:"updated_at"`
+}
+
+// CreateImportMappingProfileRequest represents the request to create a new mapping profile
+type CreateImportMappingProfileRequest struct {
+	Name          string                       `json:"name" validate:"required"`
+	Description   string                       `json:"description,omitempty"`
+	ColumnMapping map[string]string            `json:"column_mapping" validate:"required,min=1"`
+	ValueMapping  map[string]map[string]string `json:"value_mapping,omitempty"`
+}
+
+// UpdateImportMappingProfileRequest represents the request to update a mapping profile
+type UpdateImportMappingProfileRequest struct {
+	Name          string                       `json:"name,omitempty"`
+	Description   string                       `json:"description,omitempty"`
+	ColumnMapping map[string]string            `json:"column_mapping,omitempty"`
+	ValueMapping  map[string]map[string]string `json:"value_mapping,omitempty"`
+}
+
+// ImportMappingProfileResponse represents the response when returning mapping profile data
+type ImportMappingProfileResponse struct {
+	ID            string                       `json:"id"`
+	Name          string                       `json:"name"`
+	Description   string                       `json:"description,omitempty"`
+	ColumnMapping map[string]string            `json:"column_mapping"`
+	ValueMapping  map[string]map[string]string `json:"value_mapping,omitempty"`
+	CreatedAt     time.Time                    `json:"created_at"`
+	UpdatedAt     time.Time                    `json:"updated_at"`
+}
+
+// ImportPreviewRow represents a single CSV row after mapping, before anything is written
+type ImportPreviewRow struct {
+	Row      int                   `json:"row"`
+	Software CreateSoftwareRequest `json:"software"`
+	Errors   []string              `json:"errors,omitempty"`
+}
+
+// ImportPreview represents the dry-run result of mapping the first rows of an import file
+type ImportPreview struct {
+	MappingProfileID string             `json:"mapping_profile_id,omitempty"`
+	Columns          map[string]string  `json:"columns"`
+	IgnoredColumns   []string           `json:"ignored_columns,omitempty"`
+	Rows             []ImportPreviewRow `json:"rows"`
+}
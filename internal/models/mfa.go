@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+)
+
+// MFAEnrollment represents the secret of a pending multi-factor authentication enrolment
+type MFAEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// ConfirmMFARequest represents the request to confirm an enrolment with a first code
+type ConfirmMFARequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// DisableMFARequest represents the request to disable multi-factor authentication
+type DisableMFARequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"` // TOTP code or recovery code
+}
+
+// MFARecoveryCodesResponse represents the recovery codes shown once after enrolment
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// MFARecoveryCode represents a single-use recovery code; only a hash of the code is stored
+type MFARecoveryCode struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	CodeHash  string     `json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
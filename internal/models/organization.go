@@ -21,8 +21,10 @@ type CreateOrganizationRequest struct {
 	Subdomain   string `json:"subdomain" validate:"required,alphanum,min=3,max=30"`
 }
 
-// UpdateOrganizationRequest represents the request to update an organization
+// UpdateOrganizationRequest represents the request to update an organization; the subdomain
+// cannot be changed, as links and bookmarks of its users point to it
 type UpdateOrganizationRequest struct {
+	Name        string `json:"name,omitempty"`
 	DisplayName string `json:"display_name,omitempty"`
 }
 
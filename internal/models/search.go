@@ -0,0 +1,44 @@
+package models
+
+// SearchResultType is the kind of record a search result is
+type SearchResultType string
+
+const (
+	SearchResultSoftware SearchResultType = "software"
+	SearchResultEntity   SearchResultType = "entity"
+	SearchResultCategory SearchResultType = "category"
+)
+
+// SearchResultTypes lists the kinds of records searched
+var SearchResultTypes = []SearchResultType{SearchResultSoftware, SearchResultEntity, SearchResultCategory}
+
+// SearchQuery represents a search for records by their text
+type SearchQuery struct {
+	Text  string             // Text searched for; its words may be misspelled
+	Types []SearchResultType // Kinds of records searched
+}
+
+// SearchResult represents a record matching a search
+type SearchResult struct {
+	Type     SearchResultType `json:"type"`
+	ID       string           `json:"id"`
+	Title    string           `json:"title"`
+	Subtitle string           `json:"subtitle,omitempty"` // Vendor of software, or kind of entity
+	Snippet  string           `json:"snippet,omitempty"`  // HTML-escaped excerpt of the description, matches in <mark> elements
+	Score    float64          `json:"score"`
+}
+
+// SearchResults represents a page of the results of a search, with the number of matches of
+// each kind of record, whether searched or not
+type SearchResults struct {
+	Results []SearchResult
+	Facets  map[SearchResultType]int
+}
+
+// SearchResponse represents the response when returning search results
+type SearchResponse struct {
+	Query   string                   `json:"query"`
+	Results []SearchResult           `json:"data"`
+	Facets  map[SearchResultType]int `json:"facets"`
+	Total   int                      `json:"total"` // Matches of the kinds of records searched
+}
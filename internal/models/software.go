@@ -17,6 +17,9 @@ const (
 	SoftwareTypeLibrary    SoftwareType = "library"
 )
 
+// LifecycleStatusActive is the lifecycle status of software in use, which must have a business owner
+const LifecycleStatusActive = "active"
+
 // Software represents a software application in the system
 type Software struct {
 	ID                   string       `json:"id"`
@@ -34,6 +37,33 @@ type Software struct {
 	ImplementationStatus string       `json:"implementation_status"`
 	CreatedAt            time.Time    `json:"created_at"`
 	UpdatedAt            time.Time    `json:"updated_at"`
+	DeletedAt            *time.Time   `json:"deleted_at"` // Set while the software is archived
+	DeletedBy            string       `json:"deleted_by"`
+}
+
+// ValueFilter matches a field against values: the field must equal one of the included
+// values, if there are any, and none of the excluded ones
+type ValueFilter struct {
+	Include []string
+	Exclude []string
+}
+
+// SoftwareSort orders a list of software by a field; the software repository declares the
+// fields it can be sorted by
+type SoftwareSort struct {
+	Field      string
+	Descending bool
+}
+
+// SoftwareFilter narrows and orders a list of software
+type SoftwareFilter struct {
+	IncludeArchived bool // Include archived software, which is left out by default
+	DisplayName     ValueFilter
+	SoftwareType    ValueFilter
+	Vendor          ValueFilter
+	LifecycleStatus ValueFilter
+	Query           string         // Free text searched for in the names, description, vendor and manufacturer
+	Sort            []SoftwareSort // Newest first if empty
 }
 
 // CreateSoftwareRequest represents the request to create new software
@@ -50,6 +80,7 @@ type CreateSoftwareRequest struct {
 	Context              string       `json:"context,omitempty"`
 	LifecycleStatus      string       `json:"lifecycle_status,omitempty"`
 	ImplementationStatus string       `json:"implementation_status,omitempty"`
+	BusinessOwnerID      string       `json:"business_owner_id,omitempty"` // Required to create active software
 }
 
 // UpdateSoftwareRequest represents the request to update software
@@ -85,4 +116,19 @@ type SoftwareResponse struct {
 	ImplementationStatus string       `json:"implementation_status,omitempty"`
 	CreatedAt            time.Time    `json:"created_at"`
 	UpdatedAt            time.Time    `json:"updated_at"`
+	DeletedAt            *time.Time   `json:"deleted_at,omitempty"`
+	DeletedBy            string       `json:"deleted_by,omitempty"`
+}
+
+// Software match kinds
+const (
+	SoftwareMatchForeignKey = "foreign_key"
+	SoftwareMatchNameVendor = "name_vendor"
+)
+
+// SoftwareMatch represents existing software that may duplicate another record
+type SoftwareMatch struct {
+	Software   Software `json:"software"`
+	MatchedOn  string   `json:"matched_on"`
+	Similarity float64  `json:"similarity"`
 }
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+)
+
+// SoftwareVersionAction represents the change that produced a version of software
+type SoftwareVersionAction string
+
+const (
+	SoftwareVersionActionCreate  SoftwareVersionAction = "create"
+	SoftwareVersionActionUpdate  SoftwareVersionAction = "update"
+	SoftwareVersionActionDelete  SoftwareVersionAction = "delete"
+	SoftwareVersionActionRestore SoftwareVersionAction = "restore"
+)
+
+// SoftwareVersion represents a full snapshot of software after a change. Versions are numbered
+// per software from 1; the version of a deletion keeps the last state of the deleted software.
+type SoftwareVersion struct {
+	ID         string                `json:"id"`
+	SoftwareID string                `json:"software_id"`
+	Version    int                   `json:"version"`
+	Action     SoftwareVersionAction `json:"action"`
+	Snapshot   Software              `json:"snapshot"`
+	UserID     string                `json:"user_id"`
+	CreatedAt  time.Time             `json:"created_at"`
+}
+
+// SoftwareVersionResponse represents the response when returning versions of software
+type SoftwareVersionResponse struct {
+	ID         string                `json:"id"`
+	SoftwareID string                `json:"software_id"`
+	Version    int                   `json:"version"`
+	Action     SoftwareVersionAction `json:"action"`
+	Snapshot   SoftwareResponse      `json:"snapshot"`
+	UserID     string                `json:"user_id,omitempty"`
+	CreatedAt  time.Time             `json:"created_at"`
+}
+
+// FieldChange represents the change of a single field between two versions of a record
+type FieldChange struct {
+	Field    string `json:"field"`
+	OldValue string `json:"old_value,omitempty"`
+	NewValue string `json:"new_value,omitempty"`
+}
+
+// SoftwareVersionDiff represents the response when comparing two versions of software
+type SoftwareVersionDiff struct {
+	SoftwareID  string        `json:"software_id"`
+	FromVersion int           `json:"from_version"`
+	ToVersion   int           `json:"to_version"`
+	Changes     []FieldChange `json:"changes"`
+}
@@ -4,36 +4,58 @@ import (
 	"time"
 )
 
-// Stakeholder represents a stakeholder in the system
+// StakeholderRole represents the responsibility of a stakeholder for software
+type StakeholderRole string
+
+const (
+	StakeholderRoleBusinessOwner   StakeholderRole = "business_owner"
+	StakeholderRoleTechnicalOwner  StakeholderRole = "technical_owner"
+	StakeholderRoleDataSteward     StakeholderRole = "data_steward"
+	StakeholderRoleProductOwner    StakeholderRole = "product_owner"
+	StakeholderRoleSecurityOfficer StakeholderRole = "security_officer"
+	StakeholderRoleSupportContact  StakeholderRole = "support_contact"
+)
+
+// Stakeholder represents the assignment of a user to software in a stakeholder role
 type Stakeholder struct {
-	ID         string    `json:"id"`
-	ForeignKey string    `json:"foreign_key"`
-	UserID     string    `json:"user_id"`
-	Role       string    `json:"role"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID         string          `json:"id"`
+	ForeignKey string          `json:"foreign_key"`
+	SoftwareID string          `json:"software_id"`
+	UserID     string          `json:"user_id"`
+	Role       StakeholderRole `json:"role"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+	DeletedAt  *time.Time      `json:"deleted_at"` // Set while the assignment is archived
+	DeletedBy  string          `json:"deleted_by"`
 }
 
 // CreateStakeholderRequest represents the request to create a new stakeholder
 type CreateStakeholderRequest struct {
-	ForeignKey string `json:"foreign_key,omitempty"`
-	UserID     string `json:"user_id" validate:"required"`
-	Role       string `json:"role" validate:"required"`
+	ForeignKey string          `json:"foreign_key,omitempty"`
+	SoftwareID string          `json:"software_id" validate:"required"`
+	UserID     string          `json:"user_id" validate:"required"`
+	Role       StakeholderRole `json:"role" validate:"required,oneof=business_owner technical_owner data_steward product_owner security_officer support_contact"`
 }
 
 // UpdateStakeholderRequest represents the request to update a stakeholder
 type UpdateStakeholderRequest struct {
-	ForeignKey string `json:"foreign_key,omitempty"`
-	Role       string `json:"role,omitempty"`
+	ForeignKey string          `json:"foreign_key,omitempty"`
+	Role       StakeholderRole `json:"role,omitempty" validate:"omitempty,oneof=business_owner technical_owner data_steward product_owner security_officer support_contact"`
 }
 
-// StakeholderResponse represents the response when returning stakeholder data
+// StakeholderResponse represents the response when returning stakeholder data. Lists of the
+// stakeholders of software include the users, and lists of the assignments of a user include
+// the software.
 type StakeholderResponse struct {
-	ID         string    `json:"id"`
-	ForeignKey string    `json:"foreign_key,omitempty"`
-	UserID     string    `json:"user_id"`
-	User       User      `json:"user,omitempty"`
-	Role       string    `json:"role"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID         string            `json:"id"`
+	ForeignKey string            `json:"foreign_key,omitempty"`
+	SoftwareID string            `json:"software_id"`
+	Software   *SoftwareResponse `json:"software,omitempty"`
+	UserID     string            `json:"user_id"`
+	User       *UserResponse     `json:"user,omitempty"`
+	Role       StakeholderRole   `json:"role"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+	DeletedAt  *time.Time        `json:"deleted_at,omitempty"`
+	DeletedBy  string            `json:"deleted_by,omitempty"`
 }
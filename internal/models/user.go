@@ -12,31 +12,41 @@ const (
 	RoleOrganizationAdmin           UserRole = "organization_admin"
 	RoleApplicationPortfolioManager UserRole = "application_portfolio_manager"
 	RoleStakeholder                 UserRole = "stakeholder"
+
+	// RolePlatformAdmin is the role of the operators of the platform, who manage the
+	// organizations; it cannot be given through the API
+	RolePlatformAdmin UserRole = "platform_admin"
 )
 
 // User represents a user in the system
 type User struct {
-	ID             string    `json:"id"`
-	OrganizationID string    `json:"organization_id"`
-	Email          string    `json:"email"`
-	PasswordHash   string    `json:"-"`
-	FirstName      string    `json:"first_name"`
-	LastName       string    `json:"last_name"`
-	Role           UserRole  `json:"role"`
-	AvatarURL      string    `json:"avatar_url,omitempty"`
-	MFAEnabled     bool      `json:"mfa_enabled"`
-	MFASecret      string    `json:"-"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID             string     `json:"id"`
+	OrganizationID string     `json:"organization_id"`
+	Email          string     `json:"email"`
+	PasswordHash   string     `json:"-"`
+	FirstName      string     `json:"first_name"`
+	LastName       string     `json:"last_name"`
+	Role           UserRole   `json:"role"`
+	AvatarURL      string     `json:"avatar_url,omitempty"`
+	MFAEnabled     bool       `json:"mfa_enabled"`
+	MFASecret      string     `json:"-"`
+	MFALastStep    int64      `json:"-"`                        // TOTP time step of the last accepted code
+	MFAFailures    int        `json:"-"`                        // Failed MFA verifications since the last success or lockout
+	MFALockedUntil *time.Time `json:"-"`                        // Set while MFA verification is locked after too many failures
+	DeactivatedAt  *time.Time `json:"deactivated_at,omitempty"` // Set while the user may not log in
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
 }
 
-// CreateUserRequest represents the request to create a new user
+// CreateUserRequest represents the request to create a new user. Without a password, the user
+// cannot log in until they choose one through the link in their invitation.
 type CreateUserRequest struct {
-	Email     string   `json:"email" validate:"required,email"`
-	Password  string   `json:"password" validate:"required,min=8"`
-	FirstName string   `json:"first_name" validate:"required"`
-	LastName  string   `json:"last_name" validate:"required"`
-	Role      UserRole `json:"role" validate:"required,oneof=organization_admin application_portfolio_manager stakeholder"`
+	OrganizationID string   `json:"-"` // The organization of the context if empty
+	Email          string   `json:"email" validate:"required,email"`
+	Password       string   `json:"password,omitempty" validate:"omitempty,min=8,max=72"`
+	FirstName      string   `json:"first_name" validate:"required"`
+	LastName       string   `json:"last_name" validate:"required"`
+	Role           UserRole `json:"role" validate:"required,oneof=organization_admin application_portfolio_manager stakeholder"`
 }
 
 // UpdateUserRequest represents the request to update a user
@@ -49,28 +59,90 @@ type UpdateUserRequest struct {
 
 // UserResponse represents the response when returning user data
 type UserResponse struct {
-	ID         string    `json:"id"`
-	Email      string    `json:"email"`
-	FirstName  string    `json:"first_name"`
-	LastName   string    `json:"last_name"`
-	Role       UserRole  `json:"role"`
-	AvatarURL  string    `json:"avatar_url,omitempty"`
-	MFAEnabled bool      `json:"mfa_enabled"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID             string     `json:"id"`
+	OrganizationID string     `json:"organization_id"`
+	Email          string     `json:"email"`
+	FirstName      string     `json:"first_name"`
+	LastName       string     `json:"last_name"`
+	Role           UserRole   `json:"role"`
+	AvatarURL      string     `json:"avatar_url,omitempty"`
+	MFAEnabled     bool       `json:"mfa_enabled"`
+	Active         bool       `json:"active"`
+	DeactivatedAt  *time.Time `json:"deactivated_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
 }
 
 // LoginRequest represents the request to login
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
-	MFACode  string `json:"mfa_code,omitempty"`
+	MFACode  string `json:"mfa_code,omitempty"` // TOTP code or recovery code, for users with MFA enabled
 }
 
-// LoginResponse represents the response when logging in
+// LoginResponse represents the response when logging in. When RequiresMFA is set, the
+// credentials were valid but the login must be repeated with an MFA code to get the tokens.
 type LoginResponse struct {
-	User         UserResponse `json:"user"`
-	AccessToken  string       `json:"access_token"`
-	RefreshToken string       `json:"refresh_token,omitempty"`
-	RequiresMFA  bool         `json:"requires_mfa,omitempty"`
+	User         *UserResponse `json:"user,omitempty"`
+	AccessToken  string        `json:"access_token,omitempty"`
+	RefreshToken string        `json:"refresh_token,omitempty"`
+	ExpiresIn    int           `json:"expires_in,omitempty"` // Seconds until the access token expires
+	RequiresMFA  bool          `json:"requires_mfa,omitempty"`
+}
+
+// RefreshTokenRequest represents the request to refresh or revoke a session
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// ChangePasswordRequest represents the request of a user to change their password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,max=72"`
+}
+
+// RegisterRequest represents the request to sign up, creating a new organization with the
+// registering user as its administrator
+type RegisterRequest struct {
+	FirstName    string `json:"firstname" validate:"required,max=255"`
+	LastName     string `json:"lastname" validate:"required,max=255"`
+	Email        string `json:"email" validate:"required,email,max=255"`
+	Organization string `json:"organisation" validate:"required,max=255"`
+	Password     string `json:"password" validate:"required,min=8,max=72"`
+}
+
+// ForgotPasswordRequest represents the request to email a password reset link
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordRequest represents the request to set a new password with a reset token
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+// DeleteAccountRequest represents the request of a user to delete their account, which is
+// confirmed through a link sent by email
+type DeleteAccountRequest struct {
+	Reason  string `json:"reason" validate:"required,max=255"`
+	Comment string `json:"comment,omitempty" validate:"max=2000"`
+}
+
+// ConfirmAccountDeletionRequest represents the confirmation of an account deletion with the
+// token from the email
+type ConfirmAccountDeletionRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// RefreshToken represents a refresh token of a login session. Only a hash of the token is
+// stored; each refresh replaces the token with a new one in the same session.
+type RefreshToken struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	SessionID string     `json:"session_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
 }
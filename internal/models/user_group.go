@@ -30,6 +30,11 @@ type UserGroupResponse struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+// AddUserGroupMemberRequest represents the request to add a user to a group
+type AddUserGroupMemberRequest struct {
+	UserID string `json:"user_id" validate:"required"`
+}
+
 // UserToGroup represents the many-to-many relationship between users and groups
 type UserToGroup struct {
 	UserID      string    `json:"user_id"`
@@ -0,0 +1,96 @@
+// Package pagination pages through lists, either by offset or from keyset cursors. A cursor
+// holds the sort key values of the item at the edge of a page, so the pages after it do not
+// shift when items are added or removed before it.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"apm/internal/apperr"
+)
+
+const (
+	// DefaultLimit is the number of items on a page unless asked otherwise
+	DefaultLimit = 10
+	// MaxLimit is the largest number of items on a page
+	MaxLimit = 100
+)
+
+// ErrInvalidCursor is returned for a cursor that is malformed or belongs to another list
+var ErrInvalidCursor = apperr.Validation("invalid cursor")
+
+// Cursor is the position in a list a page starts after or, paging backward, ends before: the
+// sort key values of the item at the edge of the page next to it
+type Cursor struct {
+	Order    string   `json:"o"`           // Identifies the order of the list the keys belong to
+	Keys     []string `json:"k"`           // Sort key values, ending with a unique key
+	Backward bool     `json:"b,omitempty"` // Whether the page ends before the position
+}
+
+// Encode returns the opaque token of the cursor handed to clients
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a token returned by Encode
+func DecodeCursor(token string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || len(cursor.Keys) == 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// Request is a request for a page of a list
+type Request struct {
+	Limit        int     // Items on the page
+	Offset       int     // Items skipped before the page, when there is no cursor
+	Cursor       *Cursor // Position the page starts from
+	IncludeTotal bool    // Whether to count the items of the whole list
+}
+
+// Normalized returns the request with its limit and offset within bounds; the offset is
+// ignored when paging from a cursor
+func (r Request) Normalized() Request {
+	if r.Limit < 1 {
+		r.Limit = DefaultLimit
+	}
+	if r.Limit > MaxLimit {
+		r.Limit = MaxLimit
+	}
+	if r.Offset < 0 || r.Cursor != nil {
+		r.Offset = 0
+	}
+	return r
+}
+
+// Page is a page of a list. The cursors are the tokens of the pages before and after it, empty
+// when there is no such page; the total is only counted when requested.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+	PrevCursor string
+	Total      *int
+}
+
+// Map converts the items of a page, keeping its cursors and total
+func Map[T, U any](page Page[T], convert func(T) U) Page[U] {
+	items := make([]U, 0, len(page.Items))
+	for _, item := range page.Items {
+		items = append(items, convert(item))
+	}
+
+	return Page[U]{
+		Items:      items,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+		Total:      page.Total,
+	}
+}
@@ -0,0 +1,85 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCursorEncodeDecode(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{"forward", Cursor{Order: "0badf00d", Keys: []string{"Ledger", "4f9c2a"}}},
+		{"backward", Cursor{Order: "0badf00d", Keys: []string{"2024-01-02T03:04:05.123456Z", "4f9c2a"}, Backward: true}},
+		{"keys needing escapes", Cursor{Order: "0badf00d", Keys: []string{`a "quoted", comma'd value`, "ünïcode/+="}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := DecodeCursor(tt.cursor.Encode())
+			if err != nil {
+				t.Fatalf("DecodeCursor() = %v", err)
+			}
+			if !reflect.DeepEqual(*decoded, tt.cursor) {
+				t.Errorf("DecodeCursor() = %+v, want %+v", *decoded, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	encode := func(data string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(data))
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"not base64", "not a cursor!"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"o":"x","k":["1"]}`))},
+		{"not JSON", encode("not json")},
+		{"keys of the wrong type", encode(`{"o":"x","k":[1]}`)},
+		{"no keys", encode(`{"o":"x"}`)},
+		{"empty keys", encode(`{"o":"x","k":[]}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tt.token); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCursor(%q) = %v, want %v", tt.token, err, ErrInvalidCursor)
+			}
+		})
+	}
+}
+
+func TestRequestNormalized(t *testing.T) {
+	cursor := &Cursor{Order: "0badf00d", Keys: []string{"1"}}
+
+	tests := []struct {
+		name string
+		req  Request
+		want Request
+	}{
+		{"within bounds", Request{Limit: 25, Offset: 50}, Request{Limit: 25, Offset: 50}},
+		{"no limit", Request{}, Request{Limit: DefaultLimit}},
+		{"negative limit", Request{Limit: -5}, Request{Limit: DefaultLimit}},
+		{"limit above maximum", Request{Limit: MaxLimit + 1}, Request{Limit: MaxLimit}},
+		{"maximum limit", Request{Limit: MaxLimit}, Request{Limit: MaxLimit}},
+		{"negative offset", Request{Limit: 10, Offset: -1}, Request{Limit: 10}},
+		{"offset with a cursor", Request{Limit: 10, Offset: 30, Cursor: cursor}, Request{Limit: 10, Cursor: cursor}},
+		{"total kept", Request{Limit: 10, IncludeTotal: true}, Request{Limit: 10, IncludeTotal: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.req.Normalized(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Normalized() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"apm/internal/db/repository"
+	"apm/internal/models"
+	"apm/internal/pagination"
+)
+
+// Ensure implementation satisfies the interface
+var _ AccessLogService = (*accessLogService)(nil)
+
+// accessLogService implements AccessLogService
+type accessLogService struct {
+	repo   repository.AccessLogRepository
+	logger *log.Logger
+}
+
+// NewAccessLogService creates a new service for querying the access history
+func NewAccessLogService(repo repository.AccessLogRepository, logger *log.Logger) AccessLogService {
+	return &accessLogService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// List retrieves the access history matching the filter with pagination, newest first
+func (s *accessLogService) List(ctx context.Context, filter models.AccessLogFilter, page pagination.Request) (pagination.Page[models.AccessLogResponse], error) {
+	s.logger.Printf("Listing access logs (limit: %d, offset: %d)", page.Limit, page.Offset)
+
+	logs, err := s.repo.List(ctx, filter, page)
+	if err != nil {
+		s.logger.Printf("Error listing access logs: %v", err)
+		return pagination.Page[models.AccessLogResponse]{}, fmt.Errorf("failed to list access logs: %w", err)
+	}
+
+	return pagination.Map(logs, mapAccessLogToResponse), nil
+}
+
+// mapAccessLogToResponse maps an access log to its response
+func mapAccessLogToResponse(entry models.AccessLog) models.AccessLogResponse {
+	return models.AccessLogResponse{
+		ID:        entry.ID,
+		UserID:    entry.UserID,
+		Role:      entry.Role,
+		Event:     entry.Event,
+		Email:     entry.Email,
+		Success:   entry.Success,
+		IPAddress: entry.IPAddress,
+		UserAgent: entry.UserAgent,
+		Method:    entry.Method,
+		Path:      entry.Path,
+		Status:    entry.Status,
+		Timestamp: entry.Timestamp,
+	}
+}
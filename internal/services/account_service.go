@@ -0,0 +1,457 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+
+	"apm/internal/apperr"
+	"apm/internal/auth"
+	"apm/internal/db/repository"
+	"apm/internal/mail"
+	"apm/internal/models"
+	"apm/internal/tenant"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// Ensure implementation satisfies the interface
+var _ AccountService = (*accountService)(nil)
+
+const (
+	// passwordResetTTL is how long a password reset link stays valid
+	passwordResetTTL = time.Hour
+
+	// accountDeletionTTL is how long an account deletion link stays valid
+	accountDeletionTTL = 24 * time.Hour
+
+	// invitationTTL is how long the link to choose a password in an invitation stays valid
+	invitationTTL = 7 * 24 * time.Hour
+
+	// Length limits of organization subdomains, matching CreateOrganizationRequest
+	minSubdomainLength = 3
+	maxSubdomainLength = 30
+)
+
+// ErrInvalidEmailToken is returned for emailed links that are malformed, expired or used
+var ErrInvalidEmailToken = apperr.Validation("invalid or expired link")
+
+// accountService implements AccountService
+type accountService struct {
+	users         UserService
+	userRepo      repository.UserRepository
+	stakeholders  repository.StakeholderRepository
+	organizations repository.OrganizationRepository
+	tokens        repository.RefreshTokenRepository
+	mailer        mail.Mailer
+	jwtSecret     []byte
+	appURL        string
+	logger        *log.Logger
+}
+
+// NewAccountService creates a new account service; links in the emails it sends point to the
+// UI at appURL and carry tokens signed with the secret
+func NewAccountService(
+	users UserService,
+	userRepo repository.UserRepository,
+	stakeholders repository.StakeholderRepository,
+	organizations repository.OrganizationRepository,
+	tokens repository.RefreshTokenRepository,
+	mailer mail.Mailer,
+	jwtSecret string,
+	appURL string,
+	logger *log.Logger,
+) AccountService {
+	return &accountService{
+		users:         users,
+		userRepo:      userRepo,
+		stakeholders:  stakeholders,
+		organizations: organizations,
+		tokens:        tokens,
+		mailer:        mailer,
+		jwtSecret:     []byte(jwtSecret),
+		appURL:        strings.TrimRight(appURL, "/"),
+		logger:        logger,
+	}
+}
+
+// Register signs up a new user together with a new organization, which they administer
+func (s *accountService) Register(ctx context.Context, req models.RegisterRequest) (models.UserResponse, error) {
+	s.logger.Println("Registering new user:", req.Email)
+
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	req.Organization = strings.TrimSpace(req.Organization)
+	if err := validate.Struct(req); err != nil {
+		return models.UserResponse{}, validationError("registration", err)
+	}
+
+	// Checked before creating the organization, which would otherwise be left without users
+	if _, err := s.userRepo.GetByEmail(ctx, req.Email); err == nil {
+		return models.UserResponse{}, fmt.Errorf("%w: %s", ErrEmailTaken, req.Email)
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		s.logger.Printf("Error checking user email: %v", err)
+		return models.UserResponse{}, fmt.Errorf("failed to check user email: %w", err)
+	}
+
+	subdomain, err := s.availableSubdomain(ctx, req.Organization)
+	if err != nil {
+		return models.UserResponse{}, err
+	}
+
+	organization, err := s.organizations.Create(ctx, models.Organization{
+		Name:        req.Organization,
+		DisplayName: req.Organization,
+		Subdomain:   subdomain,
+	})
+	if err != nil {
+		s.logger.Printf("Error creating organization: %v", err)
+		return models.UserResponse{}, fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	user, err := s.users.Create(ctx, models.CreateUserRequest{
+		OrganizationID: organization.ID,
+		Email:          req.Email,
+		Password:       req.Password,
+		FirstName:      req.FirstName,
+		LastName:       req.LastName,
+		Role:           models.RoleOrganizationAdmin,
+	})
+	if err != nil {
+		if deleteErr := s.organizations.Delete(ctx, organization.ID); deleteErr != nil {
+			s.logger.Printf("Error removing organization of failed registration: %v", deleteErr)
+		}
+		return models.UserResponse{}, err
+	}
+
+	return user, nil
+}
+
+// RequestPasswordReset emails a password reset link to the user with the email. Whether the
+// email belongs to a user is not revealed: unknown emails are ignored.
+func (s *accountService) RequestPasswordReset(ctx context.Context, req models.ForgotPasswordRequest) error {
+	if err := validate.Struct(req); err != nil {
+		return validationError("request", err)
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, strings.TrimSpace(req.Email))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			s.logger.Println("Password reset requested for unknown email")
+			return nil
+		}
+		s.logger.Printf("Error getting user for password reset: %v", err)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.Role == models.RoleStakeholder {
+		s.logger.Println("Password reset requested for stakeholder:", user.ID)
+		return nil
+	}
+	if user.DeactivatedAt != nil {
+		s.logger.Println("Password reset requested for deactivated user:", user.ID)
+		return nil
+	}
+
+	s.logger.Println("Sending password reset link to user:", user.ID)
+
+	token, err := auth.NewEmailToken(auth.PurposePasswordReset, user.ID, accountFingerprint(user), s.jwtSecret, passwordResetTTL)
+	if err != nil {
+		s.logger.Printf("Error signing password reset token: %v", err)
+		return fmt.Errorf("failed to generate password reset token: %w", err)
+	}
+
+	return s.send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the link below to choose a new password. It is valid for %s.\n\n%s\n\n"+
+				"If you did not ask to reset your password, you can ignore this email.\n",
+			user.FirstName, passwordResetTTL, s.link("/reset-password", token),
+		),
+	})
+}
+
+// Invite adds a user to the organization the context acts for. Users who may log in and were
+// given no password are emailed a link to choose one.
+func (s *accountService) Invite(ctx context.Context, req models.CreateUserRequest) (models.UserResponse, error) {
+	s.logger.Println("Inviting user:", req.Email)
+
+	created, err := s.users.Create(ctx, req)
+	if err != nil {
+		return models.UserResponse{}, err
+	}
+	if req.Password != "" || created.Role == models.RoleStakeholder {
+		return created, nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, created.ID)
+	if err != nil {
+		s.logger.Printf("Error getting invited user: %v", err)
+		return models.UserResponse{}, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	token, err := auth.NewEmailToken(auth.PurposePasswordReset, user.ID, accountFingerprint(user), s.jwtSecret, invitationTTL)
+	if err != nil {
+		s.logger.Printf("Error signing invitation token: %v", err)
+		return models.UserResponse{}, fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+
+	err = s.send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "You have been invited to APM",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nAn account has been created for you. Use the link below to choose your password. "+
+				"It is valid for %s.\n\n%s\n",
+			user.FirstName, invitationTTL, s.link("/reset-password", token),
+		),
+	})
+	if err != nil {
+		return models.UserResponse{}, err
+	}
+
+	return created, nil
+}
+
+// ResetPassword sets a new password with the token of a reset link, and ends all sessions of
+// the user
+func (s *accountService) ResetPassword(ctx context.Context, req models.ResetPasswordRequest) error {
+	if err := validate.Struct(req); err != nil {
+		return validationError("password reset", err)
+	}
+
+	user, err := s.userFromToken(ctx, auth.PurposePasswordReset, req.Token)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Println("Resetting password of user:", user.ID)
+
+	passwordHash, err := hashPassword(req.Password)
+	if err != nil {
+		s.logger.Printf("Error hashing password: %v", err)
+		return err
+	}
+	user.PasswordHash = passwordHash
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		s.logger.Printf("Error updating password: %v", err)
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	if err := s.tokens.RevokeAllForUser(ctx, user.ID); err != nil {
+		s.logger.Printf("Error revoking sessions of user: %v", err)
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return nil
+}
+
+// RequestAccountDeletion emails the user a link to confirm the deletion of their account
+func (s *accountService) RequestAccountDeletion(ctx context.Context, userID string, req models.DeleteAccountRequest) error {
+	s.logger.Println("Account deletion requested by user:", userID)
+
+	if err := validate.Struct(req); err != nil {
+		return validationError("request", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		s.logger.Printf("Error getting user for account deletion: %v", err)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Refused now rather than after the user confirms the deletion
+	if _, err := s.isLastUser(ctx, user); err != nil {
+		return err
+	}
+
+	s.logger.Printf("Account deletion reason of user %s: %s (%s)", user.ID, req.Reason, req.Comment)
+
+	token, err := auth.NewEmailToken(auth.PurposeAccountDeletion, user.ID, accountFingerprint(user), s.jwtSecret, accountDeletionTTL)
+	if err != nil {
+		s.logger.Printf("Error signing account deletion token: %v", err)
+		return fmt.Errorf("failed to generate account deletion token: %w", err)
+	}
+
+	return s.send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Confirm the deletion of your account",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the link below to delete your account. It is valid for %s.\n\n%s\n\n"+
+				"After removal, your account information will be lost. If you did not ask to delete "+
+				"your account, you can ignore this email.\n",
+			user.FirstName, accountDeletionTTL, s.link("/account/delete", token),
+		),
+	})
+}
+
+// ConfirmAccountDeletion deletes the account of a deletion link. The last user of an
+// organization takes the organization, and all of its data, with them.
+func (s *accountService) ConfirmAccountDeletion(ctx context.Context, req models.ConfirmAccountDeletionRequest) error {
+	if err := validate.Struct(req); err != nil {
+		return validationError("request", err)
+	}
+
+	user, err := s.userFromToken(ctx, auth.PurposeAccountDeletion, req.Token)
+	if err != nil {
+		return err
+	}
+
+	lastUser, err := s.isLastUser(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	if lastUser {
+		s.logger.Printf("Deleting account of user %s and their organization %s", user.ID, user.OrganizationID)
+		if err := s.organizations.Delete(ctx, user.OrganizationID); err != nil {
+			s.logger.Printf("Error deleting organization: %v", err)
+			return fmt.Errorf("failed to delete organization: %w", err)
+		}
+		return nil
+	}
+
+	// Deletion links are followed without signing in, so the deletion is made, and recorded in
+	// the audit trail, in the organization of the user. It is attributed to nobody, as the user
+	// no longer exists once it is recorded.
+	s.logger.Println("Deleting account of user:", user.ID)
+	if err := s.users.Delete(tenant.WithOrganizationID(ctx, user.OrganizationID), user.ID); err != nil {
+		s.logger.Printf("Error deleting user: %v", err)
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	return nil
+}
+
+// isLastUser reports whether the user is the only user of their organization, counting
+// deactivated users, whose accounts would go with the organization. It returns ErrLastAdmin
+// for the only active administrator of an organization that has other users, who would
+// leave it without anyone to manage it, and ErrSoleBusinessOwner for the only business
+// owner of active software, which would be left without one.
+func (s *accountService) isLastUser(ctx context.Context, user models.User) (bool, error) {
+	total, err := s.userRepo.CountAll(ctx, user.OrganizationID)
+	if err != nil {
+		s.logger.Printf("Error counting users of organization: %v", err)
+		return false, fmt.Errorf("failed to count users: %w", err)
+	}
+	if total <= 1 {
+		return true, nil
+	}
+
+	if user.Role == models.RoleOrganizationAdmin && user.DeactivatedAt == nil {
+		counts, err := s.userRepo.CountByRole(ctx, user.OrganizationID)
+		if err != nil {
+			s.logger.Printf("Error counting users of organization: %v", err)
+			return false, fmt.Errorf("failed to count users: %w", err)
+		}
+		if counts[models.RoleOrganizationAdmin] <= 1 {
+			return false, ErrLastAdmin
+		}
+	}
+
+	// Deletion links are followed without signing in, so there is no organization in the context
+	owned, err := s.stakeholders.CountSoleBusinessOwnerships(tenant.WithOrganizationID(ctx, user.OrganizationID), user.ID)
+	if err != nil {
+		s.logger.Printf("Error counting business ownerships of user: %v", err)
+		return false, fmt.Errorf("failed to count business ownerships: %w", err)
+	}
+	if owned > 0 {
+		return false, ErrSoleBusinessOwner
+	}
+	return false, nil
+}
+
+// userFromToken returns the user of an emailed token, which must still match the account
+func (s *accountService) userFromToken(ctx context.Context, purpose, token string) (models.User, error) {
+	claims, err := auth.ParseEmailToken(token, purpose, s.jwtSecret)
+	if err != nil {
+		s.logger.Printf("Invalid %s token: %v", purpose, err)
+		return models.User{}, ErrInvalidEmailToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.Subject)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.User{}, ErrInvalidEmailToken
+		}
+		s.logger.Printf("Error getting user of %s token: %v", purpose, err)
+		return models.User{}, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// A used token no longer matches: the password it was issued for has changed
+	if claims.Fingerprint != accountFingerprint(user) {
+		s.logger.Printf("Used or outdated %s token of user %s", purpose, user.ID)
+		return models.User{}, ErrInvalidEmailToken
+	}
+
+	return user, nil
+}
+
+// availableSubdomain derives a subdomain for a new organization from its name, numbering it
+// if the subdomain is taken
+func (s *accountService) availableSubdomain(ctx context.Context, name string) (string, error) {
+	base := subdomainFromName(name)
+	candidate := base
+	for i := 2; ; i++ {
+		_, err := s.organizations.GetBySubdomain(ctx, candidate)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return candidate, nil
+		}
+		if err != nil {
+			s.logger.Printf("Error checking organization subdomain: %v", err)
+			return "", fmt.Errorf("failed to check organization subdomain: %w", err)
+		}
+
+		suffix := fmt.Sprint(i)
+		candidate = base
+		if len(candidate)+len(suffix) > maxSubdomainLength {
+			candidate = candidate[:maxSubdomainLength-len(suffix)]
+		}
+		candidate += suffix
+	}
+}
+
+// link returns the URL of a page of the UI with a token
+func (s *accountService) link(path, token string) string {
+	return s.appURL + path + "?token=" + url.QueryEscape(token)
+}
+
+// send sends an email, logging failures
+func (s *accountService) send(ctx context.Context, msg mail.Message) error {
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		s.logger.Printf("Error sending email: %v", err)
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// subdomainFromName turns an organization name into a lowercase alphanumeric subdomain
+func subdomainFromName(name string) string {
+	var subdomain strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			subdomain.WriteRune(r)
+		}
+		if subdomain.Len() == maxSubdomainLength {
+			break
+		}
+	}
+
+	result := subdomain.String()
+	for len(result) < minSubdomainLength {
+		result += "org"
+	}
+	if len(result) > maxSubdomainLength {
+		result = result[:maxSubdomainLength]
+	}
+	return result
+}
+
+// accountFingerprint describes the state of an account that emailed tokens are tied to
+func accountFingerprint(user models.User) string {
+	return auth.Fingerprint(user.ID, user.PasswordHash)
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"apm/internal/db/repository"
+	"apm/internal/models"
+)
+
+// noStakeholders is a stakeholder repository without any business owners
+type noStakeholders struct {
+	repository.StakeholderRepository
+}
+
+func (noStakeholders) CountSoleBusinessOwnerships(ctx context.Context, userID string) (int, error) {
+	return 0, nil
+}
+
+func TestAccountServiceIsLastUser(t *testing.T) {
+	deactivatedAt := time.Now()
+	admin := models.User{ID: "admin", OrganizationID: "org", Role: models.RoleOrganizationAdmin}
+	manager := models.User{ID: "manager", OrganizationID: "org", Role: models.RoleApplicationPortfolioManager}
+	deactivated := models.User{ID: "deactivated", OrganizationID: "org", Role: models.RoleApplicationPortfolioManager, DeactivatedAt: &deactivatedAt}
+	deactivatedAdmin := models.User{ID: "deactivated-admin", OrganizationID: "org", Role: models.RoleOrganizationAdmin, DeactivatedAt: &deactivatedAt}
+
+	tests := []struct {
+		name    string
+		users   []models.User
+		user    models.User
+		want    bool
+		wantErr error
+	}{
+		{"only user", []models.User{admin}, admin, true, nil},
+		{"only active admin with deactivated users", []models.User{admin, deactivated}, admin, false, ErrLastAdmin},
+		{"user besides the admin", []models.User{admin, manager, deactivated}, manager, false, nil},
+		{"deactivated admin besides the active admin", []models.User{admin, deactivatedAdmin}, deactivatedAdmin, false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := &memoryUserRepository{users: map[string]models.User{}}
+			for _, user := range tt.users {
+				users.users[user.ID] = user
+			}
+			service := &accountService{
+				userRepo:     users,
+				stakeholders: noStakeholders{},
+				logger:       log.New(io.Discard, "", 0),
+			}
+
+			got, err := service.isLastUser(context.Background(), tt.user)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("isLastUser() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("isLastUser() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
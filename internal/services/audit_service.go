@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"apm/internal/apperr"
+	"apm/internal/audit"
+	"apm/internal/db/repository"
+	"apm/internal/models"
+	"apm/internal/pagination"
+)
+
+// Ensure implementation satisfies the interface
+var _ AuditService = (*auditService)(nil)
+
+// ErrUnknownEntityType is returned when browsing the history of a kind of record that is not recorded
+var ErrUnknownEntityType = apperr.Validation("unknown entity type")
+
+// auditService implements AuditService
+type auditService struct {
+	repo   repository.EditLogRepository
+	logger *log.Logger
+}
+
+// NewAuditService creates a new service for browsing the audit trail
+func NewAuditService(repo repository.EditLogRepository, logger *log.Logger) AuditService {
+	return &auditService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// ListByEntity retrieves the history of a record with pagination, newest first
+func (s *auditService) ListByEntity(ctx context.Context, entityType, entityID string, page pagination.Request) (pagination.Page[models.EditLogResponse], error) {
+	s.logger.Printf("Listing history of %s %s (limit: %d, offset: %d)", entityType, entityID, page.Limit, page.Offset)
+
+	if !audit.IsEntityType(entityType) {
+		return pagination.Page[models.EditLogResponse]{}, fmt.Errorf("%w: %s", ErrUnknownEntityType, entityType)
+	}
+
+	logs, err := s.repo.ListByEntity(ctx, entityType, entityID, page)
+	if err != nil {
+		s.logger.Printf("Error listing history: %v", err)
+		return pagination.Page[models.EditLogResponse]{}, fmt.Errorf("failed to list history: %w", err)
+	}
+
+	return pagination.Map(logs, s.mapEditLogToResponse), nil
+}
+
+// ListByUser retrieves the changes made by a user with pagination, newest first
+func (s *auditService) ListByUser(ctx context.Context, userID string, page pagination.Request) (pagination.Page[models.EditLogResponse], error) {
+	s.logger.Printf("Listing changes of user %s (limit: %d, offset: %d)", userID, page.Limit, page.Offset)
+
+	logs, err := s.repo.ListByUser(ctx, userID, page)
+	if err != nil {
+		s.logger.Printf("Error listing changes of user: %v", err)
+		return pagination.Page[models.EditLogResponse]{}, fmt.Errorf("failed to list changes of user: %w", err)
+	}
+
+	return pagination.Map(logs, s.mapEditLogToResponse), nil
+}
+
+// mapEditLogToResponse maps an edit log to its response
+func (s *auditService) mapEditLogToResponse(entry models.EditLog) models.EditLogResponse {
+	return models.EditLogResponse{
+		ID:         entry.ID,
+		ChangeID:   entry.ChangeID,
+		UserID:     entry.UserID,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Action:     entry.Action,
+		FieldName:  entry.FieldName,
+		OldValue:   entry.OldValue,
+		NewValue:   entry.NewValue,
+		Timestamp:  entry.Timestamp,
+	}
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"apm/internal/accesslog"
+	"apm/internal/apperr"
+	"apm/internal/auth"
+	"apm/internal/db/repository"
+	"apm/internal/models"
+	"apm/internal/tenant"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// Ensure implementation satisfies the interface
+var _ AuthService = (*authService)(nil)
+
+// ErrInvalidRefreshToken is returned for refresh tokens that are unknown, expired or revoked
+var ErrInvalidRefreshToken = apperr.Unauthorized("invalid or expired refresh token")
+
+// authService implements AuthService
+type authService struct {
+	users           UserService
+	mfa             MFAService
+	tokens          repository.RefreshTokenRepository
+	jwtSecret       []byte
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+	access          *accesslog.Recorder
+	logger          *log.Logger
+}
+
+// NewAuthService creates a new auth service issuing access tokens signed with the secret and
+// refresh tokens stored in the repository, recording logins in the access log
+func NewAuthService(
+	users UserService,
+	mfa MFAService,
+	tokens repository.RefreshTokenRepository,
+	jwtSecret string,
+	accessTokenTTL, refreshTokenTTL time.Duration,
+	access *accesslog.Recorder,
+	logger *log.Logger,
+) AuthService {
+	return &authService{
+		users:           users,
+		mfa:             mfa,
+		tokens:          tokens,
+		jwtSecret:       []byte(jwtSecret),
+		accessTokenTTL:  accessTokenTTL,
+		refreshTokenTTL: refreshTokenTTL,
+		access:          access,
+		logger:          logger,
+	}
+}
+
+// Login verifies the credentials of a user and starts a new session. Users with MFA enabled
+// log in in two steps: without an MFA code the response only reports that one is required.
+func (s *authService) Login(ctx context.Context, req models.LoginRequest) (models.LoginResponse, error) {
+	resp, user, err := s.login(ctx, req)
+
+	// The first step of a login with MFA is recorded once the login is completed, or fails
+	if !resp.RequiresMFA {
+		s.access.RecordLogin(ctx, req.Email, user, err == nil)
+	}
+
+	return resp, err
+}
+
+// login performs a login, returning the user the credentials belong to, if they are valid
+func (s *authService) login(ctx context.Context, req models.LoginRequest) (models.LoginResponse, *models.UserResponse, error) {
+	user, err := s.users.Authenticate(ctx, req.Email, req.Password)
+	if err != nil {
+		return models.LoginResponse{}, nil, err
+	}
+
+	// Users log in at the subdomain of their own organization, if at a subdomain at all
+	if !belongsToTenant(ctx, user) {
+		s.logger.Println("Login of user at the subdomain of another organization:", user.ID)
+		return models.LoginResponse{}, &user, ErrInvalidCredentials
+	}
+
+	if user.MFAEnabled {
+		if req.MFACode == "" {
+			return models.LoginResponse{RequiresMFA: true}, &user, nil
+		}
+		if err := s.mfa.Verify(ctx, user.ID, req.MFACode); err != nil {
+			s.logger.Println("Failed MFA verification for user:", user.ID)
+			return models.LoginResponse{}, &user, err
+		}
+	}
+
+	// Expired tokens are no longer of use to anyone; failing to remove them does not stop the login
+	if err := s.tokens.DeleteExpired(ctx, time.Now().UTC()); err != nil {
+		s.logger.Printf("Error deleting expired refresh tokens: %v", err)
+	}
+
+	s.logger.Println("User logged in:", user.ID)
+	resp, err := s.issueTokens(ctx, user, generateSessionID())
+	return resp, &user, err
+}
+
+// Refresh exchanges a refresh token for a new access token and refresh token. A refresh token
+// that was exchanged before is evidence of theft, so presenting one ends its whole session.
+func (s *authService) Refresh(ctx context.Context, refreshToken string) (models.LoginResponse, error) {
+	token, err := s.tokens.GetByHash(ctx, auth.HashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.LoginResponse{}, ErrInvalidRefreshToken
+		}
+		s.logger.Printf("Error getting refresh token: %v", err)
+		return models.LoginResponse{}, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	if token.RevokedAt != nil {
+		s.revokeReusedSession(ctx, token)
+		return models.LoginResponse{}, ErrInvalidRefreshToken
+	}
+	if time.Now().After(token.ExpiresAt) {
+		return models.LoginResponse{}, ErrInvalidRefreshToken
+	}
+
+	// Users of other organizations are not found when refreshing at the subdomain of another
+	user, err := s.users.GetByID(ctx, token.UserID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return models.LoginResponse{}, ErrInvalidRefreshToken
+		}
+		return models.LoginResponse{}, err
+	}
+
+	revoked, err := s.tokens.Revoke(ctx, token.ID)
+	if err != nil {
+		s.logger.Printf("Error revoking refresh token: %v", err)
+		return models.LoginResponse{}, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	if !revoked {
+		// Another request exchanged the same token in the meantime
+		s.revokeReusedSession(ctx, token)
+		return models.LoginResponse{}, ErrInvalidRefreshToken
+	}
+
+	if user.Role == models.RoleStakeholder || !user.Active {
+		return models.LoginResponse{}, ErrLoginNotAllowed
+	}
+
+	return s.issueTokens(ctx, user, token.SessionID)
+}
+
+// Logout ends the session of a refresh token; unknown tokens are ignored
+func (s *authService) Logout(ctx context.Context, refreshToken string) error {
+	token, err := s.tokens.GetByHash(ctx, auth.HashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		s.logger.Printf("Error getting refresh token: %v", err)
+		return fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	if err := s.tokens.RevokeSession(ctx, token.SessionID); err != nil {
+		s.logger.Printf("Error revoking session: %v", err)
+		return fmt.Errorf("failed to log out: %w", err)
+	}
+
+	s.logger.Println("User logged out:", token.UserID)
+	return nil
+}
+
+// issueTokens creates an access token and a refresh token for the user in the session
+func (s *authService) issueTokens(ctx context.Context, user models.UserResponse, sessionID string) (models.LoginResponse, error) {
+	accessToken, err := auth.NewAccessToken(user, s.jwtSecret, s.accessTokenTTL)
+	if err != nil {
+		s.logger.Printf("Error signing access token: %v", err)
+		return models.LoginResponse{}, fmt.Errorf("failed to generate access token: %w", err)
+	}
+
+	refreshToken, hash, err := auth.NewRefreshToken()
+	if err != nil {
+		s.logger.Printf("Error generating refresh token: %v", err)
+		return models.LoginResponse{}, err
+	}
+
+	_, err = s.tokens.Create(ctx, models.RefreshToken{
+		UserID:    user.ID,
+		SessionID: sessionID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().UTC().Add(s.refreshTokenTTL),
+	})
+	if err != nil {
+		s.logger.Printf("Error storing refresh token: %v", err)
+		return models.LoginResponse{}, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	return models.LoginResponse{
+		User:         &user,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(s.accessTokenTTL.Seconds()),
+	}, nil
+}
+
+// revokeReusedSession ends the session of a refresh token that was presented after it had
+// already been exchanged
+func (s *authService) revokeReusedSession(ctx context.Context, token models.RefreshToken) {
+	s.logger.Printf("Refresh token reused, revoking session %s of user %s", token.SessionID, token.UserID)
+	if err := s.tokens.RevokeSession(ctx, token.SessionID); err != nil {
+		s.logger.Printf("Error revoking session: %v", err)
+	}
+}
+
+// belongsToTenant reports whether the user belongs to the organization the context acts for,
+// if it acts for one
+func belongsToTenant(ctx context.Context, user models.UserResponse) bool {
+	organizationID, err := tenant.OrganizationID(ctx)
+	return err != nil || organizationID == user.OrganizationID
+}
+
+// generateSessionID creates a random ID for a new login session
+func generateSessionID() string {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return fmt.Sprintf("session-%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(bytes)
+}
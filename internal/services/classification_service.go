@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"apm/internal/apperr"
+	"apm/internal/audit"
+	"apm/internal/classification"
+	"apm/internal/db/repository"
+	"apm/internal/models"
+	"apm/internal/pagination"
+)
+
+// Ensure implementation satisfies the interface
+var _ ClassificationService = (*classificationService)(nil)
+
+// classificationService implements ClassificationService
+type classificationService struct {
+	registry     *classification.Registry
+	repo         repository.ClassificationSuggestionRepository
+	softwareRepo repository.SoftwareRepository
+	audit        *audit.Trail
+	logger       *log.Logger
+}
+
+// NewClassificationService creates a new classification service running the classifiers of the
+// registry; accepted suggestions are recorded in the audit trail as changes to the software
+func NewClassificationService(
+	registry *classification.Registry,
+	repo repository.ClassificationSuggestionRepository,
+	softwareRepo repository.SoftwareRepository,
+	trail *audit.Trail,
+	logger *log.Logger,
+) ClassificationService {
+	return &classificationService{
+		registry:     registry,
+		repo:         repo,
+		softwareRepo: softwareRepo,
+		audit:        trail,
+		logger:       logger,
+	}
+}
+
+// Classify runs the classifiers on a stored software record
+func (s *classificationService) Classify(ctx context.Context, softwareID string) ([]models.ClassificationSuggestionResponse, error) {
+	software, err := s.softwareRepo.GetByID(ctx, softwareID)
+	if err != nil {
+		s.logger.Printf("Error getting software to classify: %v", err)
+		return nil, fmt.Errorf("failed to get software: %w", err)
+	}
+
+	return s.ClassifySoftware(ctx, software)
+}
+
+// ClassifySoftware runs the classifiers on a software record and stores their suggestions
+// for review, replacing earlier pending ones. Suggestions matching the current value of a
+// field, or a value that was already reviewed, are left out.
+func (s *classificationService) ClassifySoftware(ctx context.Context, software models.Software) ([]models.ClassificationSuggestionResponse, error) {
+	s.logger.Println("Classifying software:", software.ID)
+
+	suggestions, classifyErr := s.registry.Classify(ctx, software)
+	if classifyErr != nil {
+		s.logger.Printf("Error classifying software: %v", classifyErr)
+	}
+
+	existing, err := s.repo.ListBySoftware(ctx, software.ID)
+	if err != nil {
+		s.logger.Printf("Error listing classification suggestions: %v", err)
+		return nil, fmt.Errorf("failed to list classification suggestions: %w", err)
+	}
+
+	reviewed := make(map[string]bool)
+	for _, suggestion := range existing {
+		if suggestion.Status != models.SuggestionPending {
+			reviewed[suggestionKey(suggestion.Field, suggestion.Value)] = true
+		}
+	}
+
+	if err := s.repo.DeletePending(ctx, software.ID); err != nil {
+		s.logger.Printf("Error deleting pending classification suggestions: %v", err)
+		return nil, fmt.Errorf("failed to replace classification suggestions: %w", err)
+	}
+
+	var responseList []models.ClassificationSuggestionResponse
+	stored := make(map[string]bool)
+	for _, suggestion := range suggestions {
+		key := suggestionKey(suggestion.Field, suggestion.Value)
+		if reviewed[key] || stored[key] || normalizeName(currentValue(software, suggestion.Field)) == normalizeName(suggestion.Value) {
+			continue
+		}
+
+		created, err := s.repo.Create(ctx, models.ClassificationSuggestion{
+			SoftwareID: software.ID,
+			Classifier: suggestion.Classifier,
+			Field:      suggestion.Field,
+			Value:      suggestion.Value,
+			Confidence: suggestion.Confidence,
+			Status:     models.SuggestionPending,
+		})
+		if err != nil {
+			s.logger.Printf("Error storing classification suggestion: %v", err)
+			return responseList, fmt.Errorf("failed to store classification suggestion: %w", err)
+		}
+
+		stored[key] = true
+		responseList = append(responseList, s.mapSuggestionToResponse(created))
+	}
+
+	if classifyErr != nil {
+		return responseList, fmt.Errorf("failed to classify software: %w", classifyErr)
+	}
+	return responseList, nil
+}
+
+// ListBySoftware retrieves all classification suggestions of a software record
+func (s *classificationService) ListBySoftware(ctx context.Context, softwareID string) ([]models.ClassificationSuggestionResponse, error) {
+	s.logger.Println("Listing classification suggestions of software:", softwareID)
+
+	suggestions, err := s.repo.ListBySoftware(ctx, softwareID)
+	if err != nil {
+		s.logger.Printf("Error listing classification suggestions: %v", err)
+		return nil, fmt.Errorf("failed to list classification suggestions: %w", err)
+	}
+
+	return s.mapSuggestionsToResponse(suggestions), nil
+}
+
+// ListByStatus retrieves classification suggestions in a review state with pagination
+func (s *classificationService) ListByStatus(ctx context.Context, status models.SuggestionStatus, page pagination.Request) (pagination.Page[models.ClassificationSuggestionResponse], error) {
+	s.logger.Printf("Listing %s classification suggestions (limit: %d, offset: %d)", status, page.Limit, page.Offset)
+
+	if err := validate.Var(status, "oneof=pending accepted rejected"); err != nil {
+		return pagination.Page[models.ClassificationSuggestionResponse]{}, apperr.Validation(fmt.Sprintf("invalid suggestion status %q", status))
+	}
+
+	suggestions, err := s.repo.ListByStatus(ctx, status, page)
+	if err != nil {
+		s.logger.Printf("Error listing classification suggestions: %v", err)
+		return pagination.Page[models.ClassificationSuggestionResponse]{}, fmt.Errorf("failed to list classification suggestions: %w", err)
+	}
+
+	return pagination.Map(suggestions, s.mapSuggestionToResponse), nil
+}
+
+// Accept applies a pending suggestion to its software record. Accepting a suggestion for a
+// single-valued field rejects the other pending suggestions for that field.
+func (s *classificationService) Accept(ctx context.Context, id string, reviewedBy string) error {
+	s.logger.Println("Accepting classification suggestion:", id)
+
+	suggestion, err := s.getPending(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if suggestion.Field != models.ClassificationFieldFunctionalCategory {
+		software, err := s.softwareRepo.GetByID(ctx, suggestion.SoftwareID)
+		if err != nil {
+			s.logger.Printf("Error getting software of suggestion: %v", err)
+			return fmt.Errorf("failed to get software: %w", err)
+		}
+		before := software
+
+		switch suggestion.Field {
+		case models.ClassificationFieldSoftwareType:
+			if err := validate.Var(suggestion.Value, "oneof=api web mobile desktop embedded middleware library"); err != nil {
+				return apperr.Validation(fmt.Sprintf("invalid software type %q", suggestion.Value))
+			}
+			software.SoftwareType = models.SoftwareType(suggestion.Value)
+		case models.ClassificationFieldSoftwareSubtype:
+			software.SoftwareSubtype = suggestion.Value
+		case models.ClassificationFieldVendor:
+			software.Vendor = suggestion.Value
+		}
+
+		if err := s.softwareRepo.Update(ctx, software); err != nil {
+			s.logger.Printf("Error applying classification suggestion: %v", err)
+			return fmt.Errorf("failed to update software: %w", err)
+		}
+
+		// The suggestion is applied even if recording it fails
+		if err := s.audit.RecordUpdate(ctx, audit.EntitySoftware, software.ID, before, software); err != nil {
+			s.logger.Printf("Error recording accepted classification suggestion: %v", err)
+		}
+
+		if err := s.rejectAlternatives(ctx, suggestion, reviewedBy); err != nil {
+			return err
+		}
+	}
+
+	return s.review(ctx, suggestion, models.SuggestionAccepted, reviewedBy)
+}
+
+// Reject marks a pending suggestion as rejected, so it is not suggested again
+func (s *classificationService) Reject(ctx context.Context, id string, reviewedBy string) error {
+	s.logger.Println("Rejecting classification suggestion:", id)
+
+	suggestion, err := s.getPending(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.review(ctx, suggestion, models.SuggestionRejected, reviewedBy)
+}
+
+// getPending retrieves a suggestion that has not been reviewed yet
+func (s *classificationService) getPending(ctx context.Context, id string) (models.ClassificationSuggestion, error) {
+	suggestion, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Printf("Error getting classification suggestion: %v", err)
+		return models.ClassificationSuggestion{}, fmt.Errorf("failed to get classification suggestion: %w", err)
+	}
+	if suggestion.Status != models.SuggestionPending {
+		return models.ClassificationSuggestion{}, apperr.Conflict("classification suggestion has already been " + string(suggestion.Status))
+	}
+	return suggestion, nil
+}
+
+// rejectAlternatives rejects the other pending suggestions for the same field of the same software
+func (s *classificationService) rejectAlternatives(ctx context.Context, accepted models.ClassificationSuggestion, reviewedBy string) error {
+	suggestions, err := s.repo.ListBySoftware(ctx, accepted.SoftwareID)
+	if err != nil {
+		s.logger.Printf("Error listing classification suggestions: %v", err)
+		return fmt.Errorf("failed to list classification suggestions: %w", err)
+	}
+
+	for _, suggestion := range suggestions {
+		if suggestion.ID == accepted.ID || suggestion.Field != accepted.Field || suggestion.Status != models.SuggestionPending {
+			continue
+		}
+		if err := s.review(ctx, suggestion, models.SuggestionRejected, reviewedBy); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// review records the outcome of reviewing a suggestion
+func (s *classificationService) review(ctx context.Context, suggestion models.ClassificationSuggestion, status models.SuggestionStatus, reviewedBy string) error {
+	now := time.Now().UTC()
+	suggestion.Status = status
+	suggestion.ReviewedBy = reviewedBy
+	suggestion.ReviewedAt = &now
+
+	if err := s.repo.Update(ctx, suggestion); err != nil {
+		s.logger.Printf("Error updating classification suggestion: %v", err)
+		return fmt.Errorf("failed to update classification suggestion: %w", err)
+	}
+
+	return nil
+}
+
+// suggestionKey identifies a suggested value of a field
+func suggestionKey(field models.ClassificationField, value string) string {
+	return string(field) + ":" + normalizeName(value)
+}
+
+// currentValue returns the value a software record already has for a classification field
+func currentValue(software models.Software, field models.ClassificationField) string {
+	switch field {
+	case models.ClassificationFieldSoftwareType:
+		return string(software.SoftwareType)
+	case models.ClassificationFieldSoftwareSubtype:
+		return software.SoftwareSubtype
+	case models.ClassificationFieldVendor:
+		return software.Vendor
+	}
+	return ""
+}
+
+// mapSuggestionsToResponse maps a list of suggestions to response models
+func (s *classificationService) mapSuggestionsToResponse(suggestions []models.ClassificationSuggestion) []models.ClassificationSuggestionResponse {
+	var responseList []models.ClassificationSuggestionResponse
+	for _, suggestion := range suggestions {
+		responseList = append(responseList, s.mapSuggestionToResponse(suggestion))
+	}
+	return responseList
+}
+
+// Helper function to map ClassificationSuggestion to ClassificationSuggestionResponse
+func (s *classificationService) mapSuggestionToResponse(suggestion models.ClassificationSuggestion) models.ClassificationSuggestionResponse {
+	return models.ClassificationSuggestionResponse{
+		ID:         suggestion.ID,
+		SoftwareID: suggestion.SoftwareID,
+		Classifier: suggestion.Classifier,
+		Field:      suggestion.Field,
+		Value:      suggestion.Value,
+		Confidence: suggestion.Confidence,
+		Status:     suggestion.Status,
+		ReviewedBy: suggestion.ReviewedBy,
+		ReviewedAt: suggestion.ReviewedAt,
+		CreatedAt:  suggestion.CreatedAt,
+		UpdatedAt:  suggestion.UpdatedAt,
+	}
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"apm/internal/apperr"
+	"apm/internal/audit"
+	"apm/internal/db/repository"
+	"apm/internal/models"
+	"apm/internal/pagination"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// Ensure implementation satisfies the interface
+var _ ImportMappingProfileService = (*importMappingProfileService)(nil)
+
+// importMappingProfileService implements ImportMappingProfileService
+type importMappingProfileService struct {
+	repo   repository.ImportMappingProfileRepository
+	audit  *audit.Trail
+	logger *log.Logger
+}
+
+// NewImportMappingProfileService creates a new import mapping profile service recording its
+// changes in the audit trail
+func NewImportMappingProfileService(repo repository.ImportMappingProfileRepository, trail *audit.Trail, logger *log.Logger) ImportMappingProfileService {
+	return &importMappingProfileService{
+		repo:   repo,
+		audit:  trail,
+		logger: logger,
+	}
+}
+
+// Create creates a new mapping profile
+func (s *importMappingProfileService) Create(ctx context.Context, req models.CreateImportMappingProfileRequest) (models.ImportMappingProfileResponse, error) {
+	s.logger.Println("Creating new import mapping profile:", req.Name)
+
+	if err := validate.Struct(req); err != nil {
+		return models.ImportMappingProfileResponse{}, validationError("mapping profile", err)
+	}
+	if err := validateImportMappings(req.ColumnMapping, req.ValueMapping); err != nil {
+		return models.ImportMappingProfileResponse{}, err
+	}
+
+	profile, err := s.repo.Create(ctx, models.ImportMappingProfile{
+		Name:          req.Name,
+		Description:   req.Description,
+		ColumnMapping: req.ColumnMapping,
+		ValueMapping:  req.ValueMapping,
+	})
+	if err != nil {
+		s.logger.Printf("Error creating import mapping profile: %v", err)
+		return models.ImportMappingProfileResponse{}, fmt.Errorf("failed to create import mapping profile: %w", err)
+	}
+
+	// The change is made even if recording it fails
+	if err := s.audit.RecordCreate(ctx, audit.EntityImportMappingProfile, profile.ID, profile); err != nil {
+		s.logger.Printf("Error recording created import mapping profile: %v", err)
+	}
+
+	return s.mapProfileToResponse(profile), nil
+}
+
+// GetByID retrieves a mapping profile by ID
+func (s *importMappingProfileService) GetByID(ctx context.Context, id string) (models.ImportMappingProfileResponse, error) {
+	s.logger.Println("Getting import mapping profile by ID:", id)
+
+	profile, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Printf("Error getting import mapping profile by ID: %v", err)
+		return models.ImportMappingProfileResponse{}, fmt.Errorf("failed to get import mapping profile: %w", err)
+	}
+
+	return s.mapProfileToResponse(profile), nil
+}
+
+// List retrieves a list of mapping profiles with pagination
+func (s *importMappingProfileService) List(ctx context.Context, page pagination.Request) (pagination.Page[models.ImportMappingProfileResponse], error) {
+	s.logger.Printf("Listing import mapping profiles (limit: %d, offset: %d)", page.Limit, page.Offset)
+
+	profiles, err := s.repo.List(ctx, page)
+	if err != nil {
+		s.logger.Printf("Error listing import mapping profiles: %v", err)
+		return pagination.Page[models.ImportMappingProfileResponse]{}, fmt.Errorf("failed to list import mapping profiles: %w", err)
+	}
+
+	return pagination.Map(profiles, s.mapProfileToResponse), nil
+}
+
+// Update updates an existing mapping profile; provided mappings replace the stored ones
+func (s *importMappingProfileService) Update(ctx context.Context, id string, req models.UpdateImportMappingProfileRequest) error {
+	s.logger.Println("Updating import mapping profile with ID:", id)
+
+	if err := validateImportMappings(req.ColumnMapping, req.ValueMapping); err != nil {
+		return err
+	}
+
+	existingProfile, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Printf("Error getting import mapping profile to update: %v", err)
+		return fmt.Errorf("failed to get import mapping profile for update: %w", err)
+	}
+	before := existingProfile
+
+	// Update fields if they are provided
+	if req.Name != "" {
+		existingProfile.Name = req.Name
+	}
+	if req.Description != "" {
+		existingProfile.Description = req.Description
+	}
+	if req.ColumnMapping != nil {
+		existingProfile.ColumnMapping = req.ColumnMapping
+	}
+	if req.ValueMapping != nil {
+		existingProfile.ValueMapping = req.ValueMapping
+	}
+
+	if err := s.repo.Update(ctx, existingProfile); err != nil {
+		s.logger.Printf("Error updating import mapping profile: %v", err)
+		return fmt.Errorf("failed to update import mapping profile: %w", err)
+	}
+
+	if err := s.audit.RecordUpdate(ctx, audit.EntityImportMappingProfile, id, before, existingProfile); err != nil {
+		s.logger.Printf("Error recording updated import mapping profile: %v", err)
+	}
+
+	return nil
+}
+
+// Delete removes a mapping profile
+func (s *importMappingProfileService) Delete(ctx context.Context, id string) error {
+	s.logger.Println("Deleting import mapping profile with ID:", id)
+
+	profile, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		s.logger.Printf("Error getting import mapping profile to delete: %v", err)
+		return fmt.Errorf("failed to get import mapping profile for deletion: %w", err)
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		s.logger.Printf("Error deleting import mapping profile: %v", err)
+		return fmt.Errorf("failed to delete import mapping profile: %w", err)
+	}
+
+	if err := s.audit.RecordDelete(ctx, audit.EntityImportMappingProfile, id, profile); err != nil {
+		s.logger.Printf("Error recording deleted import mapping profile: %v", err)
+	}
+
+	return nil
+}
+
+// validateImportMappings ensures every mapping targets a software field that can be imported
+func validateImportMappings(columns map[string]string, values map[string]map[string]string) error {
+	var unknown []string
+	for source, field := range columns {
+		if _, ok := softwareColumns[field]; !ok {
+			unknown = append(unknown, fmt.Sprintf("column %q maps to unknown field %q", source, field))
+		}
+	}
+	for field := range values {
+		if _, ok := softwareColumns[field]; !ok {
+			unknown = append(unknown, fmt.Sprintf("value mapping for unknown field %q", field))
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	sort.Strings(unknown)
+	return apperr.Validation("invalid mapping profile: " + strings.Join(unknown, ", "))
+}
+
+// Helper function to map ImportMappingProfile to ImportMappingProfileResponse
+func (s *importMappingProfileService) mapProfileToResponse(profile models.ImportMappingProfile) models.ImportMappingProfileResponse {
+	return models.ImportMappingProfileResponse{
+		ID:            profile.ID,
+		Name:          profile.Name,
+		Description:   profile.Description,
+		ColumnMapping: profile.ColumnMapping,
+		ValueMapping:  profile.ValueMapping,
+		CreatedAt:     profile.CreatedAt,
+		UpdatedAt:     profile.UpdatedAt,
+	}
+}
@@ -0,0 +1,607 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"apm/internal/apperr"
+	"apm/internal/db/repository"
+	"apm/internal/models"
+	"apm/internal/pagination"
+)
+
+// Ensure implementation satisfies the interface
+var _ ImportService = (*importService)(nil)
+
+// softwareColumns maps the supported CSV columns onto CreateSoftwareRequest fields
+var softwareColumns = map[string]func(req *models.CreateSoftwareRequest, value string){
+	"foreign_key":           func(req *models.CreateSoftwareRequest, v string) { req.ForeignKey = v },
+	"display_name":          func(req *models.CreateSoftwareRequest, v string) { req.DisplayName = v },
+	"description":           func(req *models.CreateSoftwareRequest, v string) { req.Description = v },
+	"software_type":         func(req *models.CreateSoftwareRequest, v string) { req.SoftwareType = models.SoftwareType(v) },
+	"software_subtype":      func(req *models.CreateSoftwareRequest, v string) { req.SoftwareSubtype = v },
+	"vendor":                func(req *models.CreateSoftwareRequest, v string) { req.Vendor = v },
+	"manufacturer":          func(req *models.CreateSoftwareRequest, v string) { req.Manufacturer = v },
+	"install_type":          func(req *models.CreateSoftwareRequest, v string) { req.InstallType = v },
+	"product_type":          func(req *models.CreateSoftwareRequest, v string) { req.ProductType = v },
+	"context":               func(req *models.CreateSoftwareRequest, v string) { req.Context = v },
+	"lifecycle_status":      func(req *models.CreateSoftwareRequest, v string) { req.LifecycleStatus = v },
+	"implementation_status": func(req *models.CreateSoftwareRequest, v string) { req.ImplementationStatus = v },
+	"business_owner_id":     func(req *models.CreateSoftwareRequest, v string) { req.BusinessOwnerID = v },
+}
+
+// importService implements ImportService
+type importService struct {
+	softwareService SoftwareService
+	jobRepo         repository.ImportJobRepository
+	profileRepo     repository.ImportMappingProfileRepository
+	logger          *log.Logger
+}
+
+// NewImportService creates a new import service
+func NewImportService(
+	softwareService SoftwareService,
+	jobRepo repository.ImportJobRepository,
+	profileRepo repository.ImportMappingProfileRepository,
+	logger *log.Logger,
+) ImportService {
+	return &importService{
+		softwareService: softwareService,
+		jobRepo:         jobRepo,
+		profileRepo:     profileRepo,
+		logger:          logger,
+	}
+}
+
+// ImportSoftware parses a CSV file and creates a software record for every valid row,
+// translating its columns and values through the given mapping profile, if any, and
+// resolving rows that match existing software with the given duplicate action
+func (s *importService) ImportSoftware(ctx context.Context, r io.Reader, opts models.ImportOptions) (models.ImportReport, error) {
+	s.logger.Println("Importing software from CSV")
+
+	if err := validate.Struct(opts); err != nil {
+		return models.ImportReport{}, validationError("import options", err)
+	}
+	if opts.DuplicateAction == "" {
+		opts.DuplicateAction = models.DuplicateActionSkip
+	}
+
+	mapping, err := s.loadMapping(ctx, opts.MappingProfileID)
+	if err != nil {
+		return models.ImportReport{}, err
+	}
+
+	reader, _, columns, ignored, err := openImportCSV(r, mapping)
+	if err != nil {
+		return models.ImportReport{}, err
+	}
+
+	report := models.ImportReport{IgnoredColumns: ignored}
+	seen := make(map[string]int)
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				report.AddRow(models.ImportRowResult{
+					Row:    parseErr.StartLine,
+					Status: models.ImportRowFailed,
+					Errors: []string{parseErr.Err.Error()},
+				})
+				continue
+			}
+			return report, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		// Rows are reported by their line number in the file, the header being line 1
+		line, _ := reader.FieldPos(0)
+		if isBlankRecord(record) {
+			report.AddRow(models.ImportRowResult{Row: line, Status: models.ImportRowSkipped, Errors: []string{"row is empty"}})
+			continue
+		}
+		report.AddRow(s.importRow(ctx, line, mapping.request(columns, record), opts.DuplicateAction, seen))
+	}
+
+	s.logger.Printf("Software import finished (created: %d, updated: %d, skipped: %d, flagged: %d, failed: %d)",
+		report.Created, report.Updated, report.Skipped, report.Flagged, report.Failed)
+
+	return report, nil
+}
+
+// PreviewImport maps and validates the first rows of a CSV file without writing anything
+func (s *importService) PreviewImport(ctx context.Context, r io.Reader, mappingProfileID string, limit int) (models.ImportPreview, error) {
+	s.logger.Printf("Previewing import (mapping profile: %q, limit: %d)", mappingProfileID, limit)
+
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	mapping, err := s.loadMapping(ctx, mappingProfileID)
+	if err != nil {
+		return models.ImportPreview{}, err
+	}
+
+	reader, header, columns, ignored, err := openImportCSV(r, mapping)
+	if err != nil {
+		return models.ImportPreview{}, err
+	}
+
+	preview := models.ImportPreview{
+		MappingProfileID: mappingProfileID,
+		Columns:          make(map[string]string, len(columns)),
+		IgnoredColumns:   ignored,
+	}
+	for field, index := range columns {
+		preview.Columns[header[index]] = field
+	}
+
+	for len(preview.Rows) < limit {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				preview.Rows = append(preview.Rows, models.ImportPreviewRow{
+					Row:    parseErr.StartLine,
+					Errors: []string{parseErr.Err.Error()},
+				})
+				continue
+			}
+			return preview, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		if isBlankRecord(record) {
+			continue
+		}
+
+		row := models.ImportPreviewRow{Row: line, Software: mapping.request(columns, record)}
+		if err := validate.Struct(row.Software); err != nil {
+			row.Errors = validationMessages(err)
+		}
+		preview.Rows = append(preview.Rows, row)
+	}
+
+	return preview, nil
+}
+
+// CreateJob stores an uploaded file as a new import job and processes it
+func (s *importService) CreateJob(ctx context.Context, req models.CreateImportJobRequest) (models.ImportJobResponse, error) {
+	s.logger.Println("Creating import job for file:", req.FileName)
+
+	if err := validate.Struct(req); err != nil {
+		return models.ImportJobResponse{}, validationError("import job", err)
+	}
+
+	// Reject unknown profiles up front rather than recording a failed job
+	if _, err := s.loadMapping(ctx, req.MappingProfileID); err != nil {
+		return models.ImportJobResponse{}, err
+	}
+
+	job, err := s.jobRepo.Create(ctx, models.ImportJob{
+		FileName:         req.FileName,
+		FileContent:      req.FileContent,
+		UploadedBy:       req.UploadedBy,
+		Status:           models.ImportJobPending,
+		MappingProfileID: req.MappingProfileID,
+		DuplicateAction:  req.DuplicateAction,
+	})
+	if err != nil {
+		s.logger.Printf("Error creating import job: %v", err)
+		return models.ImportJobResponse{}, fmt.Errorf("failed to create import job: %w", err)
+	}
+
+	return s.runJob(ctx, job)
+}
+
+// GetJob retrieves an import job and its report by ID
+func (s *importService) GetJob(ctx context.Context, id string) (models.ImportJobResponse, error) {
+	s.logger.Println("Getting import job by ID:", id)
+
+	job, err := s.jobRepo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Printf("Error getting import job by ID: %v", err)
+		return models.ImportJobResponse{}, fmt.Errorf("failed to get import job: %w", err)
+	}
+
+	return s.mapImportJobToResponse(job), nil
+}
+
+// ListJobs retrieves the most recent import jobs with pagination
+func (s *importService) ListJobs(ctx context.Context, page pagination.Request) (pagination.Page[models.ImportJobResponse], error) {
+	s.logger.Printf("Listing import jobs (limit: %d, offset: %d)", page.Limit, page.Offset)
+
+	jobs, err := s.jobRepo.List(ctx, page)
+	if err != nil {
+		s.logger.Printf("Error listing import jobs: %v", err)
+		return pagination.Page[models.ImportJobResponse]{}, fmt.Errorf("failed to list import jobs: %w", err)
+	}
+
+	return pagination.Map(jobs, s.mapImportJobToResponse), nil
+}
+
+// GetJobFile retrieves the original file uploaded for an import job
+func (s *importService) GetJobFile(ctx context.Context, id string) (models.ImportFile, error) {
+	s.logger.Println("Getting file of import job:", id)
+
+	job, err := s.jobRepo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Printf("Error getting import job file: %v", err)
+		return models.ImportFile{}, fmt.Errorf("failed to get import job: %w", err)
+	}
+
+	return models.ImportFile{FileName: job.FileName, Content: job.FileContent}, nil
+}
+
+// RerunJob processes the file of an earlier import job again as a new job, with the same
+// mapping profile and, unless another one is given, the same duplicate action
+func (s *importService) RerunJob(ctx context.Context, id string, uploadedBy string, duplicateAction models.DuplicateAction) (models.ImportJobResponse, error) {
+	s.logger.Println("Re-running import job:", id)
+
+	if err := validate.Var(duplicateAction, "omitempty,oneof=skip update flag"); err != nil {
+		return models.ImportJobResponse{}, apperr.Validation(fmt.Sprintf("invalid duplicate action %q", duplicateAction))
+	}
+
+	previous, err := s.jobRepo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Printf("Error getting import job to re-run: %v", err)
+		return models.ImportJobResponse{}, fmt.Errorf("failed to get import job: %w", err)
+	}
+	if duplicateAction == "" {
+		duplicateAction = previous.DuplicateAction
+	}
+
+	job, err := s.jobRepo.Create(ctx, models.ImportJob{
+		FileName:         previous.FileName,
+		FileContent:      previous.FileContent,
+		UploadedBy:       uploadedBy,
+		Status:           models.ImportJobPending,
+		RerunOf:          previous.ID,
+		MappingProfileID: previous.MappingProfileID,
+		DuplicateAction:  duplicateAction,
+	})
+	if err != nil {
+		s.logger.Printf("Error creating import job: %v", err)
+		return models.ImportJobResponse{}, fmt.Errorf("failed to create import job: %w", err)
+	}
+
+	return s.runJob(ctx, job)
+}
+
+// runJob imports the file of a pending job and records the outcome on the job
+func (s *importService) runJob(ctx context.Context, job models.ImportJob) (models.ImportJobResponse, error) {
+	startedAt := time.Now().UTC()
+	job.Status = models.ImportJobRunning
+	job.StartedAt = &startedAt
+	if err := s.jobRepo.Update(ctx, job); err != nil {
+		s.logger.Printf("Error updating import job: %v", err)
+		return models.ImportJobResponse{}, fmt.Errorf("failed to start import job: %w", err)
+	}
+
+	report, importErr := s.ImportSoftware(ctx, bytes.NewReader(job.FileContent), models.ImportOptions{
+		MappingProfileID: job.MappingProfileID,
+		DuplicateAction:  job.DuplicateAction,
+	})
+
+	finishedAt := time.Now().UTC()
+	job.FinishedAt = &finishedAt
+	job.Report = &report
+	job.TotalRows = report.TotalRows
+	job.CreatedRows = report.Created
+	job.UpdatedRows = report.Updated
+	job.SkippedRows = report.Skipped
+	job.FlaggedRows = report.Flagged
+	job.FailedRows = report.Failed
+	job.Status = models.ImportJobDone
+	if importErr != nil {
+		job.Status = models.ImportJobFailed
+		job.ErrorMessage = importErr.Error()
+	}
+
+	if err := s.jobRepo.Update(ctx, job); err != nil {
+		s.logger.Printf("Error updating import job: %v", err)
+		return models.ImportJobResponse{}, fmt.Errorf("failed to finish import job: %w", err)
+	}
+
+	return s.mapImportJobToResponse(job), nil
+}
+
+// importRow validates the software request of a single CSV record and creates it, unless it
+// matches existing software, in which case the duplicate action decides what happens
+func (s *importService) importRow(ctx context.Context, line int, req models.CreateSoftwareRequest, action models.DuplicateAction, seen map[string]int) models.ImportRowResult {
+	result := models.ImportRowResult{Row: line, DisplayName: req.DisplayName}
+
+	if err := validate.Struct(req); err != nil {
+		result.Status = models.ImportRowFailed
+		result.Errors = validationMessages(err)
+		return result
+	}
+
+	// Skip rows that repeat an earlier row of the same file
+	key := importRowKey(req)
+	if previous, ok := seen[key]; ok {
+		result.Status = models.ImportRowSkipped
+		result.Errors = []string{fmt.Sprintf("duplicate of row %d", previous)}
+		return result
+	}
+
+	matches, err := s.softwareService.FindDuplicates(ctx, req)
+	if err != nil {
+		result.Status = models.ImportRowFailed
+		result.Errors = s.rowErrors(line, err)
+		return result
+	}
+	if len(matches) > 0 {
+		result = s.resolveDuplicate(ctx, result, req, matches, action)
+		if result.Status == models.ImportRowUpdated {
+			seen[key] = line
+		}
+		return result
+	}
+
+	created, err := s.softwareService.Create(ctx, req)
+	if err != nil {
+		result.Status = models.ImportRowFailed
+		result.Errors = s.rowErrors(line, err)
+		return result
+	}
+
+	seen[key] = line
+	result.Status = models.ImportRowCreated
+	result.SoftwareID = created.ID
+	return result
+}
+
+// resolveDuplicate skips, updates or flags a row that matches existing software
+func (s *importService) resolveDuplicate(ctx context.Context, result models.ImportRowResult, req models.CreateSoftwareRequest, matches []models.SoftwareMatch, action models.DuplicateAction) models.ImportRowResult {
+	for _, match := range matches {
+		result.Matches = append(result.Matches, models.ImportRowMatch{
+			SoftwareID:  match.Software.ID,
+			DisplayName: match.Software.DisplayName,
+			Vendor:      match.Software.Vendor,
+			MatchedOn:   match.MatchedOn,
+			Similarity:  match.Similarity,
+		})
+	}
+
+	switch action {
+	case models.DuplicateActionUpdate:
+		// Only update in place when the match is unambiguous
+		best := matches[0]
+		if best.MatchedOn != models.SoftwareMatchForeignKey && len(matches) > 1 {
+			result.Status = models.ImportRowFlagged
+			result.Errors = []string{"matches several existing software records, flagged for review"}
+			return result
+		}
+		if err := s.softwareService.Update(ctx, best.Software.ID, updateRequestFromCreate(req)); err != nil {
+			result.Status = models.ImportRowFailed
+			result.Errors = s.rowErrors(result.Row, err)
+			return result
+		}
+		result.Status = models.ImportRowUpdated
+		result.SoftwareID = best.Software.ID
+	case models.DuplicateActionFlag:
+		result.Status = models.ImportRowFlagged
+		result.Errors = []string{"possible duplicate of existing software, flagged for review"}
+	default:
+		result.Status = models.ImportRowSkipped
+		result.Errors = []string{fmt.Sprintf("duplicate of existing software %s", matches[0].Software.ID)}
+	}
+
+	return result
+}
+
+// rowErrors returns the errors reported for a row that failed. Reports are returned to
+// clients and stored with their jobs, so only the messages of errors of a kind are reported;
+// the full error is logged.
+func (s *importService) rowErrors(line int, err error) []string {
+	s.logger.Printf("Error importing row %d: %v", line, err)
+	if message := apperr.MessageOf(err); message != "" {
+		return []string{message}
+	}
+	return []string{"failed to import row"}
+}
+
+// updateRequestFromCreate converts an imported row into an update of existing software
+func updateRequestFromCreate(req models.CreateSoftwareRequest) models.UpdateSoftwareRequest {
+	return models.UpdateSoftwareRequest{
+		ForeignKey:           req.ForeignKey,
+		DisplayName:          req.DisplayName,
+		Description:          req.Description,
+		SoftwareType:         req.SoftwareType,
+		SoftwareSubtype:      req.SoftwareSubtype,
+		Vendor:               req.Vendor,
+		Manufacturer:         req.Manufacturer,
+		InstallType:          req.InstallType,
+		ProductType:          req.ProductType,
+		Context:              req.Context,
+		LifecycleStatus:      req.LifecycleStatus,
+		ImplementationStatus: req.ImplementationStatus,
+	}
+}
+
+// loadMapping returns the mapping of a saved profile, or the default mapping when no profile is given
+func (s *importService) loadMapping(ctx context.Context, mappingProfileID string) (importMapping, error) {
+	if mappingProfileID == "" {
+		return newImportMapping(nil), nil
+	}
+
+	profile, err := s.profileRepo.GetByID(ctx, mappingProfileID)
+	if err != nil {
+		s.logger.Printf("Error getting import mapping profile: %v", err)
+		return importMapping{}, fmt.Errorf("failed to get import mapping profile: %w", err)
+	}
+
+	return newImportMapping(&profile), nil
+}
+
+// openImportCSV reads the header of a CSV file and resolves its columns through the mapping
+func openImportCSV(r io.Reader, mapping importMapping) (*csv.Reader, []string, map[string]int, []string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, nil, nil, apperr.Validation("CSV file is empty")
+		}
+		return nil, nil, nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns, ignored := parseImportHeader(header, mapping)
+	if _, ok := columns["display_name"]; !ok {
+		return nil, nil, nil, nil, apperr.Validation("CSV file must contain a column mapped to display_name")
+	}
+
+	return reader, header, columns, ignored, nil
+}
+
+// parseImportHeader maps the columns of a header onto software fields and returns the unknown columns
+func parseImportHeader(header []string, mapping importMapping) (map[string]int, []string) {
+	columns := make(map[string]int)
+	var ignored []string
+
+	for i, name := range header {
+		field, ok := mapping.field(name)
+		if !ok {
+			ignored = append(ignored, name)
+			continue
+		}
+		if _, duplicate := columns[field]; !duplicate {
+			columns[field] = i
+		}
+	}
+
+	return columns, ignored
+}
+
+// importMapping translates the columns and values of a CSV file onto software fields
+type importMapping struct {
+	columns map[string]string            // normalized source column -> software field
+	values  map[string]map[string]string // software field -> lower-cased source value -> field value
+}
+
+// newImportMapping creates the mapping described by a profile; a nil profile only accepts the software field names
+func newImportMapping(profile *models.ImportMappingProfile) importMapping {
+	mapping := importMapping{
+		columns: make(map[string]string),
+		values:  make(map[string]map[string]string),
+	}
+	if profile == nil {
+		return mapping
+	}
+
+	for source, field := range profile.ColumnMapping {
+		mapping.columns[normalizeColumnName(source)] = field
+	}
+	for field, vocabulary := range profile.ValueMapping {
+		values := make(map[string]string, len(vocabulary))
+		for source, value := range vocabulary {
+			values[strings.ToLower(strings.TrimSpace(source))] = value
+		}
+		mapping.values[field] = values
+	}
+
+	return mapping
+}
+
+// field returns the software field a source column maps onto
+func (m importMapping) field(column string) (string, bool) {
+	name := normalizeColumnName(column)
+	if field, ok := m.columns[name]; ok {
+		return field, true
+	}
+	if _, ok := softwareColumns[name]; ok {
+		return name, true
+	}
+	return "", false
+}
+
+// value translates a source value through the vocabulary of its software field
+func (m importMapping) value(field, value string) string {
+	if mapped, ok := m.values[field][strings.ToLower(value)]; ok {
+		return mapped
+	}
+	return value
+}
+
+// request builds the software request described by a CSV record
+func (m importMapping) request(columns map[string]int, record []string) models.CreateSoftwareRequest {
+	var req models.CreateSoftwareRequest
+	for field, index := range columns {
+		if index < len(record) {
+			softwareColumns[field](&req, m.value(field, strings.TrimSpace(record[index])))
+		}
+	}
+	req.SoftwareType = models.SoftwareType(strings.ToLower(string(req.SoftwareType)))
+	return req
+}
+
+// normalizeColumnName converts a header such as "Display Name" into "display_name"
+func normalizeColumnName(name string) string {
+	name = strings.TrimPrefix(name, "\ufeff")
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return r == ' ' || r == '-' || r == '_'
+	}), "_")
+}
+
+// importRowKey identifies a row by its foreign key, or by display name and vendor
+func importRowKey(req models.CreateSoftwareRequest) string {
+	if req.ForeignKey != "" {
+		return "fk:" + strings.ToLower(req.ForeignKey)
+	}
+	return "name:" + strings.ToLower(req.DisplayName) + "|" + strings.ToLower(req.Vendor)
+}
+
+// isBlankRecord reports whether every cell of a record is empty
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Helper function to map ImportJob to ImportJobResponse
+func (s *importService) mapImportJobToResponse(job models.ImportJob) models.ImportJobResponse {
+	return models.ImportJobResponse{
+		ID:               job.ID,
+		FileName:         job.FileName,
+		UploadedBy:       job.UploadedBy,
+		Status:           job.Status,
+		TotalRows:        job.TotalRows,
+		CreatedRows:      job.CreatedRows,
+		UpdatedRows:      job.UpdatedRows,
+		SkippedRows:      job.SkippedRows,
+		FlaggedRows:      job.FlaggedRows,
+		FailedRows:       job.FailedRows,
+		Report:           job.Report,
+		ErrorMessage:     job.ErrorMessage,
+		RerunOf:          job.RerunOf,
+		MappingProfileID: job.MappingProfileID,
+		DuplicateAction:  job.DuplicateAction,
+		StartedAt:        job.StartedAt,
+		FinishedAt:       job.FinishedAt,
+		CreatedAt:        job.CreatedAt,
+		UpdatedAt:        job.UpdatedAt,
+	}
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"apm/internal/apperr"
+	"apm/internal/auth"
+	"apm/internal/db/repository"
+	"apm/internal/models"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Ensure implementation satisfies the interface
+var _ MFAService = (*mfaService)(nil)
+
+const (
+	// mfaIssuer names the application in authenticator apps
+	mfaIssuer = "APM"
+
+	// recoveryCodeCount is the number of recovery codes a user receives when enabling MFA
+	recoveryCodeCount = 10
+
+	// mfaMaxFailures is the number of failed verifications after which a user is locked out
+	// for mfaLockout, which keeps codes from being guessed
+	mfaMaxFailures = 5
+	mfaLockout     = 15 * time.Minute
+)
+
+// Errors returned by multi-factor authentication
+var (
+	ErrInvalidMFACode    = apperr.Unauthorized("invalid authentication code")
+	ErrMFAAlreadyEnabled = apperr.Conflict("multi-factor authentication is already enabled")
+	ErrMFANotEnrolled    = apperr.Conflict("multi-factor authentication enrolment has not been started")
+	ErrMFANotEnabled     = apperr.Conflict("multi-factor authentication is not enabled")
+	ErrMFALocked         = apperr.RateLimited("too many invalid authentication codes, try again later")
+)
+
+// mfaService implements MFAService
+type mfaService struct {
+	users         repository.UserRepository
+	recoveryCodes repository.MFARecoveryCodeRepository
+	logger        *log.Logger
+}
+
+// NewMFAService creates a new service for TOTP multi-factor authentication
+func NewMFAService(users repository.UserRepository, recoveryCodes repository.MFARecoveryCodeRepository, logger *log.Logger) MFAService {
+	return &mfaService{
+		users:         users,
+		recoveryCodes: recoveryCodes,
+		logger:        logger,
+	}
+}
+
+// Enroll starts enrolment by generating a new secret for the user. MFA is not enabled until
+// the user confirms the enrolment with a code from their authenticator app.
+func (s *mfaService) Enroll(ctx context.Context, userID string) (models.MFAEnrollment, error) {
+	s.logger.Println("Enrolling user in MFA:", userID)
+
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		s.logger.Printf("Error getting user to enroll in MFA: %v", err)
+		return models.MFAEnrollment{}, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.MFAEnabled {
+		return models.MFAEnrollment{}, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := auth.NewTOTPSecret()
+	if err != nil {
+		return models.MFAEnrollment{}, err
+	}
+
+	user.MFASecret = secret
+	if err := s.users.Update(ctx, user); err != nil {
+		s.logger.Printf("Error storing MFA secret: %v", err)
+		return models.MFAEnrollment{}, fmt.Errorf("failed to store MFA secret: %w", err)
+	}
+
+	return models.MFAEnrollment{
+		Secret:     secret,
+		OTPAuthURI: auth.TOTPURI(secret, mfaIssuer, user.Email),
+	}, nil
+}
+
+// Confirm enables MFA once the user proves their authenticator app works, returning the
+// recovery codes; they are not stored in readable form and cannot be shown again
+func (s *mfaService) Confirm(ctx context.Context, userID string, req models.ConfirmMFARequest) (models.MFARecoveryCodesResponse, error) {
+	s.logger.Println("Confirming MFA enrolment of user:", userID)
+
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		s.logger.Printf("Error getting user to confirm MFA: %v", err)
+		return models.MFARecoveryCodesResponse{}, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.MFAEnabled {
+		return models.MFARecoveryCodesResponse{}, ErrMFAAlreadyEnabled
+	}
+	if user.MFASecret == "" {
+		return models.MFARecoveryCodesResponse{}, ErrMFANotEnrolled
+	}
+	step, ok := auth.ValidateTOTP(user.MFASecret, req.Code, time.Now(), user.MFALastStep)
+	if !ok {
+		return models.MFARecoveryCodesResponse{}, ErrInvalidMFACode
+	}
+	if err := s.useStep(ctx, user.ID, step); err != nil {
+		return models.MFARecoveryCodesResponse{}, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := auth.NewRecoveryCode()
+		if err != nil {
+			return models.MFARecoveryCodesResponse{}, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, auth.HashRecoveryCode(code))
+	}
+
+	if err := s.recoveryCodes.ReplaceForUser(ctx, user.ID, hashes); err != nil {
+		s.logger.Printf("Error storing recovery codes: %v", err)
+		return models.MFARecoveryCodesResponse{}, fmt.Errorf("failed to store recovery codes: %w", err)
+	}
+
+	user.MFAEnabled = true
+	if err := s.users.Update(ctx, user); err != nil {
+		s.logger.Printf("Error enabling MFA: %v", err)
+		return models.MFARecoveryCodesResponse{}, fmt.Errorf("failed to enable MFA: %w", err)
+	}
+
+	return models.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable turns MFA off after verifying the password of the user and a code
+func (s *mfaService) Disable(ctx context.Context, userID string, req models.DisableMFARequest) error {
+	s.logger.Println("Disabling MFA of user:", userID)
+
+	if err := validate.Struct(req); err != nil {
+		return validationError("request", err)
+	}
+
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		s.logger.Printf("Error getting user to disable MFA: %v", err)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if !user.MFAEnabled {
+		return ErrMFANotEnabled
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		return ErrInvalidCredentials
+	}
+	if err := s.Verify(ctx, user.ID, req.Code); err != nil {
+		return err
+	}
+
+	user.MFAEnabled = false
+	user.MFASecret = ""
+	if err := s.users.Update(ctx, user); err != nil {
+		s.logger.Printf("Error disabling MFA: %v", err)
+		return fmt.Errorf("failed to disable MFA: %w", err)
+	}
+
+	if err := s.recoveryCodes.DeleteForUser(ctx, user.ID); err != nil {
+		s.logger.Printf("Error deleting recovery codes: %v", err)
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	return nil
+}
+
+// Verify checks a TOTP code, or a recovery code which is then used up, of a user with MFA
+// enabled, returning ErrInvalidMFACode if it is not valid. A TOTP code is only accepted once.
+// After too many invalid codes the user is locked out for a while, getting ErrMFALocked
+// whatever the code.
+func (s *mfaService) Verify(ctx context.Context, userID string, code string) error {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		s.logger.Printf("Error getting user to verify MFA code: %v", err)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if !user.MFAEnabled {
+		return ErrMFANotEnabled
+	}
+
+	now := time.Now()
+	if user.MFALockedUntil != nil && now.Before(*user.MFALockedUntil) {
+		return ErrMFALocked
+	}
+
+	if step, ok := auth.ValidateTOTP(user.MFASecret, code, now, user.MFALastStep); ok {
+		err := s.useStep(ctx, user.ID, step)
+		if errors.Is(err, ErrInvalidMFACode) {
+			return s.recordFailure(ctx, user.ID, now)
+		}
+		return err
+	}
+
+	used, err := s.recoveryCodes.Use(ctx, user.ID, auth.HashRecoveryCode(code))
+	if err != nil {
+		s.logger.Printf("Error using recovery code: %v", err)
+		return fmt.Errorf("failed to verify recovery code: %w", err)
+	}
+	if !used {
+		return s.recordFailure(ctx, user.ID, now)
+	}
+
+	s.logger.Println("Recovery code used by user:", user.ID)
+	if err := s.users.ResetMFAFailures(ctx, user.ID); err != nil {
+		s.logger.Printf("Error resetting MFA failures: %v", err)
+		return fmt.Errorf("failed to reset MFA failures: %w", err)
+	}
+	return nil
+}
+
+// useStep records the time step of an accepted TOTP code, returning ErrInvalidMFACode if a
+// code of the step was accepted in the meantime
+func (s *mfaService) useStep(ctx context.Context, userID string, step int64) error {
+	used, err := s.users.UseMFAStep(ctx, userID, step)
+	if err != nil {
+		s.logger.Printf("Error recording MFA step: %v", err)
+		return fmt.Errorf("failed to record MFA step: %w", err)
+	}
+	if !used {
+		s.logger.Println("Reused TOTP code of user:", userID)
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+// recordFailure counts a failed verification, returning ErrInvalidMFACode
+func (s *mfaService) recordFailure(ctx context.Context, userID string, now time.Time) error {
+	if err := s.users.RecordMFAFailure(ctx, userID, mfaMaxFailures, now.Add(mfaLockout)); err != nil {
+		s.logger.Printf("Error recording MFA failure: %v", err)
+		return fmt.Errorf("failed to record MFA failure: %w", err)
+	}
+	return ErrInvalidMFACode
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"apm/internal/apperr"
+	"apm/internal/auth"
+	"apm/internal/db/repository"
+	"apm/internal/models"
+	"apm/internal/pagination"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// Ensure implementation satisfies the interface
+var _ OrganizationService = (*organizationService)(nil)
+
+// Errors returned by organization operations
+var (
+	ErrOrganizationNotFound  = apperr.NotFound("organization not found")
+	ErrSubdomainTaken        = apperr.Conflict("subdomain is already in use")
+	ErrDeleteOwnOrganization = apperr.Conflict("the organization of the current user cannot be deleted")
+)
+
+// organizationService implements OrganizationService
+type organizationService struct {
+	repo   repository.OrganizationRepository
+	logger *log.Logger
+}
+
+// NewOrganizationService creates a new organization service
+func NewOrganizationService(repo repository.OrganizationRepository, logger *log.Logger) OrganizationService {
+	return &organizationService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Create creates a new organization with a unique subdomain
+func (s *organizationService) Create(ctx context.Context, req models.CreateOrganizationRequest) (models.OrganizationResponse, error) {
+	s.logger.Println("Creating new organization:", req.Name)
+
+	req.Subdomain = strings.ToLower(strings.TrimSpace(req.Subdomain))
+	if err := validate.Struct(req); err != nil {
+		return models.OrganizationResponse{}, validationError("organization", err)
+	}
+
+	if _, err := s.repo.GetBySubdomain(ctx, req.Subdomain); err == nil {
+		return models.OrganizationResponse{}, fmt.Errorf("%w: %s", ErrSubdomainTaken, req.Subdomain)
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		s.logger.Printf("Error checking organization subdomain: %v", err)
+		return models.OrganizationResponse{}, fmt.Errorf("failed to check organization subdomain: %w", err)
+	}
+
+	organization, err := s.repo.Create(ctx, models.Organization{
+		Name:        req.Name,
+		DisplayName: req.DisplayName,
+		Subdomain:   req.Subdomain,
+	})
+	if err != nil {
+		s.logger.Printf("Error creating organization: %v", err)
+		return models.OrganizationResponse{}, fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	return s.mapOrganizationToResponse(organization), nil
+}
+
+// GetByID retrieves an organization by ID
+func (s *organizationService) GetByID(ctx context.Context, id string) (models.OrganizationResponse, error) {
+	s.logger.Println("Getting organization by ID:", id)
+
+	organization, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Printf("Error getting organization by ID: %v", err)
+		return models.OrganizationResponse{}, fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	return s.mapOrganizationToResponse(organization), nil
+}
+
+// GetBySubdomain retrieves an organization by subdomain, returning ErrOrganizationNotFound if
+// no organization has it
+func (s *organizationService) GetBySubdomain(ctx context.Context, subdomain string) (models.OrganizationResponse, error) {
+	organization, err := s.repo.GetBySubdomain(ctx, subdomain)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.OrganizationResponse{}, fmt.Errorf("%w: %s", ErrOrganizationNotFound, subdomain)
+		}
+		s.logger.Printf("Error getting organization by subdomain: %v", err)
+		return models.OrganizationResponse{}, fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	return s.mapOrganizationToResponse(organization), nil
+}
+
+// List retrieves a list of organizations with pagination
+func (s *organizationService) List(ctx context.Context, page pagination.Request) (pagination.Page[models.OrganizationResponse], error) {
+	s.logger.Printf("Listing organizations (limit: %d, offset: %d)", page.Limit, page.Offset)
+
+	organizations, err := s.repo.List(ctx, page)
+	if err != nil {
+		s.logger.Printf("Error listing organizations: %v", err)
+		return pagination.Page[models.OrganizationResponse]{}, fmt.Errorf("failed to list organizations: %w", err)
+	}
+
+	return pagination.Map(organizations, s.mapOrganizationToResponse), nil
+}
+
+// Update updates the names of an existing organization
+func (s *organizationService) Update(ctx context.Context, id string, req models.UpdateOrganizationRequest) error {
+	s.logger.Println("Updating organization with ID:", id)
+
+	organization, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Printf("Error getting organization to update: %v", err)
+		return fmt.Errorf("failed to get organization for update: %w", err)
+	}
+
+	// Update fields if they are provided
+	if req.Name != "" {
+		organization.Name = req.Name
+	}
+	if req.DisplayName != "" {
+		organization.DisplayName = req.DisplayName
+	}
+
+	if err := s.repo.Update(ctx, organization); err != nil {
+		s.logger.Printf("Error updating organization: %v", err)
+		return fmt.Errorf("failed to update organization: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes an organization with all of its users and data. Platform admins cannot
+// delete their own organization, which would delete their account.
+func (s *organizationService) Delete(ctx context.Context, id string) error {
+	s.logger.Println("Deleting organization with ID:", id)
+
+	if user, ok := auth.UserFromContext(ctx); ok && user.OrganizationID == id {
+		return ErrDeleteOwnOrganization
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		s.logger.Printf("Error deleting organization: %v", err)
+		return fmt.Errorf("failed to delete organization: %w", err)
+	}
+
+	return nil
+}
+
+// Helper function to map Organization to OrganizationResponse
+func (s *organizationService) mapOrganizationToResponse(organization models.Organization) models.OrganizationResponse {
+	return models.OrganizationResponse{
+		ID:          organization.ID,
+		Name:        organization.Name,
+		DisplayName: organization.DisplayName,
+		Subdomain:   organization.Subdomain,
+		CreatedAt:   organization.CreatedAt,
+		UpdatedAt:   organization.UpdatedAt,
+	}
+}
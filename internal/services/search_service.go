@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"apm/internal/apperr"
+	"apm/internal/db/repository"
+	"apm/internal/models"
+	"apm/internal/pagination"
+)
+
+// Ensure implementation satisfies the interface
+var _ SearchService = (*searchService)(nil)
+
+// ErrInvalidSearch is returned for a search without text, with too long a text or for an
+// unknown kind of record
+var ErrInvalidSearch = apperr.Validation("invalid search")
+
+// maxSearchLength is the longest text searched for, in characters
+const maxSearchLength = 200
+
+// searchService implements SearchService
+type searchService struct {
+	repo   repository.SearchRepository
+	logger *log.Logger
+}
+
+// NewSearchService creates a new service for searching records
+func NewSearchService(repo repository.SearchRepository, logger *log.Logger) SearchService {
+	return &searchService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Search retrieves a page of the records matching the text, best matches first. Without types,
+// all kinds of records are searched; the facets count the matches of every kind regardless.
+func (s *searchService) Search(ctx context.Context, query models.SearchQuery, page pagination.Request) (models.SearchResponse, error) {
+	query.Text = strings.TrimSpace(query.Text)
+	if query.Text == "" {
+		return models.SearchResponse{}, fmt.Errorf("%w: text is required", ErrInvalidSearch)
+	}
+	if utf8.RuneCountInString(query.Text) > maxSearchLength {
+		return models.SearchResponse{}, fmt.Errorf("%w: text must be at most %d characters", ErrInvalidSearch, maxSearchLength)
+	}
+
+	// Each kind of record is searched once, however many times it is asked for
+	var types []models.SearchResultType
+	for _, resultType := range query.Types {
+		if !slices.Contains(models.SearchResultTypes, resultType) {
+			return models.SearchResponse{}, fmt.Errorf("%w: unknown type %q", ErrInvalidSearch, resultType)
+		}
+		if !slices.Contains(types, resultType) {
+			types = append(types, resultType)
+		}
+	}
+	if len(types) == 0 {
+		types = models.SearchResultTypes
+	}
+	query.Types = types
+
+	s.logger.Printf("Searching for %q (limit: %d, offset: %d)", query.Text, page.Limit, page.Offset)
+
+	results, err := s.repo.Search(ctx, query, page)
+	if err != nil {
+		s.logger.Printf("Error searching: %v", err)
+		return models.SearchResponse{}, fmt.Errorf("failed to search: %w", err)
+	}
+
+	resp := models.SearchResponse{
+		Query:   query.Text,
+		Results: results.Results,
+		Facets:  make(map[models.SearchResultType]int, len(models.SearchResultTypes)),
+	}
+	if resp.Results == nil {
+		resp.Results = []models.SearchResult{}
+	}
+	for _, resultType := range models.SearchResultTypes {
+		resp.Facets[resultType] = results.Facets[resultType]
+	}
+	for _, resultType := range query.Types {
+		resp.Total += resp.Facets[resultType]
+	}
+
+	return resp, nil
+}
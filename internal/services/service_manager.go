@@ -1,14 +1,27 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"log"
+	"time"
 
+	"apm/internal/accesslog"
+	"apm/internal/archive"
+	"apm/internal/audit"
+	"apm/internal/classification"
+	"apm/internal/config"
 	"apm/internal/db"
 	"apm/internal/db/repository"
+	"apm/internal/mail"
 )
 
 // Services holds all service instances
 type Services struct {
+	AuthService                 AuthService
+	AccountService              AccountService
+	OrganizationService         OrganizationService
+	MFAService                  MFAService
 	UserService                 UserService
 	UserGroupService            UserGroupService
 	StakeholderService          StakeholderService
@@ -23,24 +36,102 @@ type Services struct {
 	MediaService                MediaService
 	ProductDocumentationService ProductDocumentationService
 	LogService                  LogService
+	ImportService               ImportService
+	ImportMappingProfileService ImportMappingProfileService
+	ClassificationService       ClassificationService
+	AuditService                AuditService
+	AccessLogService            AccessLogService
+	SearchService               SearchService
+
+	// AccessLog records logins and authenticated requests in the background until it is closed
+	AccessLog *accesslog.Recorder
+
+	// ArchivePurger purges archived records in the background until it is closed
+	ArchivePurger *archive.Purger
 }
 
 // NewServices creates a new services manager
-func NewServices(db *db.Database, logger *log.Logger) *Services {
+func NewServices(cfg config.Config, db *db.Database, logger *log.Logger) *Services {
 	// Instantiate repositories needed by services
 	softwareRepo := repository.NewPostgresSoftwareRepository(db.Pool)
-	// userRepo := repository.NewPostgresUserRepository(db.Pool)
+	importJobRepo := repository.NewPostgresImportJobRepository(db.Pool)
+	importMappingProfileRepo := repository.NewPostgresImportMappingProfileRepository(db.Pool)
+	userRepo := repository.NewPostgresUserRepository(db.Pool)
+	refreshTokenRepo := repository.NewPostgresRefreshTokenRepository(db.Pool)
+	mfaRecoveryCodeRepo := repository.NewPostgresMFARecoveryCodeRepository(db.Pool)
+	organizationRepo := repository.NewPostgresOrganizationRepository(db.Pool)
+	userGroupRepo := repository.NewPostgresUserGroupRepository(db.Pool)
+	stakeholderRepo := repository.NewPostgresStakeholderRepository(db.Pool)
+	editLogRepo := repository.NewPostgresEditLogRepository(db.Pool)
+	accessLogRepo := repository.NewPostgresAccessLogRepository(db.Pool)
 	// ... instantiate other repos ...
 
+	classificationSuggestionRepo := repository.NewPostgresClassificationSuggestionRepository(db.Pool)
+
+	// Services record the changes they make in the audit trail
+	trail := audit.NewTrail(editLogRepo, logger)
+
+	// Logins and authenticated requests are written to the access log in batches
+	accessLog := accesslog.NewRecorder(accessLogRepo, accesslog.Options{
+		BufferSize:    cfg.AccessLog.BufferSize,
+		BatchSize:     cfg.AccessLog.BatchSize,
+		FlushInterval: time.Duration(cfg.AccessLog.FlushInterval) * time.Second,
+	}, logger)
+
+	// Classifiers are chained in the order they are registered
+	classifiers := classification.NewRegistry()
+	if ruleClassifier, err := newRuleClassifier(cfg.Classification, logger); err != nil {
+		logger.Printf("Rule-based classifier disabled: %v", err)
+	} else {
+		classifiers.Register(ruleClassifier)
+	}
+	classificationService := NewClassificationService(classifiers, classificationSuggestionRepo, softwareRepo, trail, logger)
+
+	userService := NewUserService(userRepo, stakeholderRepo, refreshTokenRepo, trail, logger)
+	softwareService := NewSoftwareService(softwareRepo, stakeholderRepo, userService, classificationService, trail, logger)
+
+	mfaService := NewMFAService(userRepo, mfaRecoveryCodeRepo, logger)
+	authService := NewAuthService(
+		userService,
+		mfaService,
+		refreshTokenRepo,
+		cfg.Server.JWTSecret,
+		time.Duration(cfg.Server.AccessTokenTTL)*time.Second,
+		time.Duration(cfg.Server.RefreshTokenTTL)*time.Second,
+		accessLog,
+		logger,
+	)
+	accountService := NewAccountService(
+		userService,
+		userRepo,
+		stakeholderRepo,
+		organizationRepo,
+		refreshTokenRepo,
+		newMailer(cfg.Mail, logger),
+		cfg.Server.JWTSecret,
+		cfg.Mail.AppURL,
+		logger,
+	)
+
+	// Archived records are purged once the retention period has passed
+	archivePurger := archive.NewPurger(softwareRepo, stakeholderRepo, archive.Options{
+		Retention: time.Duration(cfg.Archive.RetentionDays) * 24 * time.Hour,
+		Interval:  time.Duration(cfg.Archive.PurgeInterval) * time.Hour,
+	}, logger)
+
 	// TODO: Uncomment and implement other service initializations as needed
 	return &Services{
-		// UserService: NewUserService(userRepo, logger),
-		// UserGroupService: NewUserGroupService(userRepo, logger),
-		// StakeholderService: NewStakeholderService(stakeholderRepo, logger),
+		AuthService:         authService,
+		AccountService:      accountService,
+		MFAService:          mfaService,
+		OrganizationService: NewOrganizationService(organizationRepo, logger),
+		UserService:         userService,
+		UserGroupService:    NewUserGroupService(userGroupRepo, userService, trail, logger),
+		StakeholderService:  NewStakeholderService(stakeholderRepo, softwareService, userService, trail, logger),
 		// EntityService: NewEntityService(entityRepo, logger),
 
 		// Initialize software service with the repository instance
-		SoftwareService: NewSoftwareService(softwareRepo, logger),
+		SoftwareService: softwareService,
 
 		// FunctionalCategoryService: NewFunctionalCategoryService(functionalCategoryRepo, logger),
 		// SoftwareGroupService: NewSoftwareGroupService(softwareGroupRepo, logger),
@@ -51,5 +142,66 @@ func NewServices(db *db.Database, logger *log.Logger) *Services {
 		// MediaService: NewMediaService(mediaRepo, logger),
 		// ProductDocumentationService: NewProductDocumentationService(productDocumentationRepo, logger),
 		// LogService: NewLogService(logRepo, logger),
+
+		// Imports create software through the software service
+		ImportService:               NewImportService(softwareService, importJobRepo, importMappingProfileRepo, logger),
+		ImportMappingProfileService: NewImportMappingProfileService(importMappingProfileRepo, trail, logger),
+
+		// Software is classified when it is created or imported
+		ClassificationService: classificationService,
+
+		AuditService:     NewAuditService(editLogRepo, logger),
+		AccessLogService: NewAccessLogService(accessLogRepo, logger),
+		SearchService:    NewSearchService(repository.NewPostgresSearchRepository(db.Pool), logger),
+		AccessLog:        accessLog,
+		ArchivePurger:    archivePurger,
 	}
 }
+
+// Close stops the background work of the services, writing the access logs still waiting,
+// unless the context ends first
+func (s *Services) Close(ctx context.Context) error {
+	return errors.Join(s.AccessLog.Close(ctx), s.ArchivePurger.Close(ctx))
+}
+
+// newRuleClassifier creates the rule-based classifier from the configured rules file, or the
+// built-in rules, extended with a rule per category of the configured category list
+func newRuleClassifier(cfg config.ClassificationConfig, logger *log.Logger) (*classification.RuleClassifier, error) {
+	var rules classification.RuleSet
+	var err error
+	if cfg.RulesFile != "" {
+		rules, err = classification.LoadRules(cfg.RulesFile)
+	} else {
+		rules, err = classification.DefaultRules()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var categories []string
+	if cfg.CategoriesFile != "" {
+		categories, err = classification.LoadCategories(cfg.CategoriesFile)
+		if err != nil {
+			// Without the category list the rules still classify, but categories are not checked
+			logger.Printf("Classifying without category list: %v", err)
+			categories = nil
+		}
+	}
+
+	return classification.NewRuleClassifier(append(rules.Rules, classification.CategoryRules(categories)...), categories)
+}
+
+// newMailer creates the mailer of the configured driver, falling back to logging emails
+func newMailer(cfg config.MailConfig, logger *log.Logger) mail.Mailer {
+	if cfg.Driver == "file" {
+		mailer, err := mail.NewFileMailer(cfg.Dir, cfg.From)
+		if err == nil {
+			return mailer
+		}
+		logger.Printf("Logging emails instead of writing them to files: %v", err)
+	} else if cfg.Driver != "log" {
+		logger.Printf("Unknown mail driver %q, logging emails instead", cfg.Driver)
+	}
+
+	return mail.NewLogMailer(logger)
+}
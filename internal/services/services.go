@@ -2,42 +2,91 @@ package services
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"apm/internal/models"
+	"apm/internal/pagination"
 )
 
+// AuthService defines the service for logging users in and out
+type AuthService interface {
+	Login(ctx context.Context, req models.LoginRequest) (models.LoginResponse, error)
+	Refresh(ctx context.Context, refreshToken string) (models.LoginResponse, error)
+	Logout(ctx context.Context, refreshToken string) error
+}
+
+// MFAService defines the service for TOTP multi-factor authentication
+type MFAService interface {
+	Enroll(ctx context.Context, userID string) (models.MFAEnrollment, error)
+	Confirm(ctx context.Context, userID string, req models.ConfirmMFARequest) (models.MFARecoveryCodesResponse, error)
+	Disable(ctx context.Context, userID string, req models.DisableMFARequest) error
+	Verify(ctx context.Context, userID string, code string) error
+}
+
+// AccountService defines the service for the self-service account flows: registration,
+// password reset and account deletion
+type AccountService interface {
+	Register(ctx context.Context, req models.RegisterRequest) (models.UserResponse, error)
+	RequestPasswordReset(ctx context.Context, req models.ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, req models.ResetPasswordRequest) error
+	RequestAccountDeletion(ctx context.Context, userID string, req models.DeleteAccountRequest) error
+	ConfirmAccountDeletion(ctx context.Context, req models.ConfirmAccountDeletionRequest) error
+	Invite(ctx context.Context, req models.CreateUserRequest) (models.UserResponse, error)
+}
+
+// OrganizationService defines the service for organization-related operations
+type OrganizationService interface {
+	Create(ctx context.Context, req models.CreateOrganizationRequest) (models.OrganizationResponse, error)
+	GetByID(ctx context.Context, id string) (models.OrganizationResponse, error)
+	GetBySubdomain(ctx context.Context, subdomain string) (models.OrganizationResponse, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.OrganizationResponse], error)
+	Update(ctx context.Context, id string, req models.UpdateOrganizationRequest) error
+	Delete(ctx context.Context, id string) error
+}
+
 // UserService defines the service for user-related operations
 type UserService interface {
 	Create(ctx context.Context, req models.CreateUserRequest) (models.UserResponse, error)
 	GetByID(ctx context.Context, id string) (models.UserResponse, error)
-	List(ctx context.Context, limit, offset int) ([]models.UserResponse, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.UserResponse], error)
 	Update(ctx context.Context, id string, req models.UpdateUserRequest) error
 	Delete(ctx context.Context, id string) error
+	Deactivate(ctx context.Context, id string) error
+	Activate(ctx context.Context, id string) error
+	Authenticate(ctx context.Context, email, password string) (models.UserResponse, error)
+	ChangePassword(ctx context.Context, id string, req models.ChangePasswordRequest) error
 }
 
 // UserGroupService defines the service for user group-related operations
 type UserGroupService interface {
 	Create(ctx context.Context, req models.CreateUserGroupRequest) (models.UserGroupResponse, error)
 	GetByID(ctx context.Context, id string) (models.UserGroupResponse, error)
-	List(ctx context.Context, limit, offset int) ([]models.UserGroupResponse, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.UserGroupResponse], error)
 	Update(ctx context.Context, id string, req models.UpdateUserGroupRequest) error
 	Delete(ctx context.Context, id string) error
+	AddMember(ctx context.Context, groupID string, req models.AddUserGroupMemberRequest) error
+	RemoveMember(ctx context.Context, groupID, userID string) error
+	ListMembers(ctx context.Context, groupID string, page pagination.Request) (pagination.Page[models.UserResponse], error)
+	ListByUser(ctx context.Context, userID string) ([]models.UserGroupResponse, error)
 }
 
 // StakeholderService defines the service for stakeholder-related operations
 type StakeholderService interface {
 	Create(ctx context.Context, req models.CreateStakeholderRequest) (models.StakeholderResponse, error)
 	GetByID(ctx context.Context, id string) (models.StakeholderResponse, error)
-	List(ctx context.Context, limit, offset int) ([]models.StakeholderResponse, error)
+	List(ctx context.Context, includeArchived bool, page pagination.Request) (pagination.Page[models.StakeholderResponse], error)
 	Update(ctx context.Context, id string, req models.UpdateStakeholderRequest) error
 	Delete(ctx context.Context, id string) error
+	ListBySoftware(ctx context.Context, softwareID string) ([]models.StakeholderResponse, error)
+	ListByUser(ctx context.Context, userID string) ([]models.StakeholderResponse, error)
 }
 
 // EntityService defines the service for entity-related operations
 type EntityService interface {
 	Create(ctx context.Context, req models.CreateEntityRequest) (models.EntityResponse, error)
 	GetByID(ctx context.Context, id string) (models.EntityResponse, error)
-	List(ctx context.Context, limit, offset int) ([]models.EntityResponse, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.EntityResponse], error)
 	Update(ctx context.Context, id string, req models.UpdateEntityRequest) error
 	Delete(ctx context.Context, id string) error
 }
@@ -46,16 +95,25 @@ type EntityService interface {
 type SoftwareService interface {
 	Create(ctx context.Context, req models.CreateSoftwareRequest) (models.SoftwareResponse, error)
 	GetByID(ctx context.Context, id string) (models.SoftwareResponse, error)
-	List(ctx context.Context, limit, offset int) ([]models.SoftwareResponse, error)
+	GetByIDIncludingArchived(ctx context.Context, id string) (models.SoftwareResponse, error)
+	List(ctx context.Context, filter models.SoftwareFilter, page pagination.Request) (pagination.Page[models.SoftwareResponse], error)
+	FindDuplicates(ctx context.Context, req models.CreateSoftwareRequest) ([]models.SoftwareMatch, error)
 	Update(ctx context.Context, id string, req models.UpdateSoftwareRequest) error
 	Delete(ctx context.Context, id string) error
+	Undelete(ctx context.Context, id string) (models.SoftwareResponse, error)
+	ListVersions(ctx context.Context, id string, page pagination.Request) (pagination.Page[models.SoftwareVersionResponse], error)
+	GetVersion(ctx context.Context, id string, version int) (models.SoftwareVersionResponse, error)
+	GetAsOf(ctx context.Context, id string, at time.Time) (models.SoftwareVersionResponse, error)
+	DiffVersions(ctx context.Context, id string, from, to int) (models.SoftwareVersionDiff, error)
+	RestoreVersion(ctx context.Context, id string, version int) (models.SoftwareResponse, error)
+	Restore(ctx context.Context, id string) (models.SoftwareResponse, error)
 }
 
 // FunctionalCategoryService defines the service for functional category-related operations
 type FunctionalCategoryService interface {
 	Create(ctx context.Context, req models.CreateFunctionalCategoryRequest) (models.FunctionalCategoryResponse, error)
 	GetByID(ctx context.Context, id string) (models.FunctionalCategoryResponse, error)
-	List(ctx context.Context, limit, offset int) ([]models.FunctionalCategoryResponse, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.FunctionalCategoryResponse], error)
 	Update(ctx context.Context, id string, req models.UpdateFunctionalCategoryRequest) error
 	Delete(ctx context.Context, id string) error
 }
@@ -64,7 +122,7 @@ type FunctionalCategoryService interface {
 type SoftwareGroupService interface {
 	Create(ctx context.Context, req models.CreateSoftwareGroupRequest) (models.SoftwareGroupResponse, error)
 	GetByID(ctx context.Context, id string) (models.SoftwareGroupResponse, error)
-	List(ctx context.Context, limit, offset int) ([]models.SoftwareGroupResponse, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.SoftwareGroupResponse], error)
 	Update(ctx context.Context, id string, req models.UpdateSoftwareGroupRequest) error
 	Delete(ctx context.Context, id string) error
 }
@@ -73,7 +131,7 @@ type SoftwareGroupService interface {
 type StatusService interface {
 	Create(ctx context.Context, req models.CreateStatusRequest) (models.StatusResponse, error)
 	GetByID(ctx context.Context, id string) (models.StatusResponse, error)
-	List(ctx context.Context, limit, offset int) ([]models.StatusResponse, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.StatusResponse], error)
 	Update(ctx context.Context, id string, req models.UpdateStatusRequest) error
 	Delete(ctx context.Context, id string) error
 }
@@ -82,7 +140,7 @@ type StatusService interface {
 type StatusLogService interface {
 	Create(ctx context.Context, req models.CreateStatusLogRequest) (models.StatusLogResponse, error)
 	GetByID(ctx context.Context, id string) (models.StatusLogResponse, error)
-	List(ctx context.Context, limit, offset int) ([]models.StatusLogResponse, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.StatusLogResponse], error)
 	Update(ctx context.Context, id string, req models.UpdateStatusLogRequest) error
 	Delete(ctx context.Context, id string) error
 }
@@ -91,7 +149,7 @@ type StatusLogService interface {
 type RankService interface {
 	Create(ctx context.Context, req models.CreateRankRequest) (models.RankResponse, error)
 	GetByID(ctx context.Context, id string) (models.RankResponse, error)
-	List(ctx context.Context, limit, offset int) ([]models.RankResponse, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.RankResponse], error)
 	Update(ctx context.Context, id string, req models.UpdateRankRequest) error
 	Delete(ctx context.Context, id string) error
 }
@@ -100,7 +158,7 @@ type RankService interface {
 type NewsArticleService interface {
 	Create(ctx context.Context, req models.CreateNewsArticleRequest) (models.NewsArticleResponse, error)
 	GetByID(ctx context.Context, id string) (models.NewsArticleResponse, error)
-	List(ctx context.Context, limit, offset int) ([]models.NewsArticleResponse, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.NewsArticleResponse], error)
 	Update(ctx context.Context, id string, req models.UpdateNewsArticleRequest) error
 	Delete(ctx context.Context, id string) error
 }
@@ -109,7 +167,7 @@ type NewsArticleService interface {
 type MediaService interface {
 	Create(ctx context.Context, req models.CreateMediaRequest) (models.MediaResponse, error)
 	GetByID(ctx context.Context, id string) (models.MediaResponse, error)
-	List(ctx context.Context, limit, offset int) ([]models.MediaResponse, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.MediaResponse], error)
 	Update(ctx context.Context, id string, req models.UpdateMediaRequest) error
 	Delete(ctx context.Context, id string) error
 }
@@ -118,7 +176,7 @@ type MediaService interface {
 type ProductDocumentationService interface {
 	Create(ctx context.Context, req models.CreateProductDocumentationRequest) (models.ProductDocumentationResponse, error)
 	GetByID(ctx context.Context, id string) (models.ProductDocumentationResponse, error)
-	List(ctx context.Context, limit, offset int) ([]models.ProductDocumentationResponse, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.ProductDocumentationResponse], error)
 	Update(ctx context.Context, id string, req models.UpdateProductDocumentationRequest) error
 	Delete(ctx context.Context, id string) error
 }
@@ -127,6 +185,52 @@ type ProductDocumentationService interface {
 type LogService interface {
 	Create(ctx context.Context, req models.CreateLogRequest) (models.LogResponse, error)
 	GetByID(ctx context.Context, id string) (models.LogResponse, error)
-	List(ctx context.Context, limit, offset int) ([]models.LogResponse, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.LogResponse], error)
+	Delete(ctx context.Context, id string) error
+}
+
+// AuditService defines the service for browsing the audit trail
+type AuditService interface {
+	ListByEntity(ctx context.Context, entityType, entityID string, page pagination.Request) (pagination.Page[models.EditLogResponse], error)
+	ListByUser(ctx context.Context, userID string, page pagination.Request) (pagination.Page[models.EditLogResponse], error)
+}
+
+// AccessLogService defines the service for querying the access history
+type AccessLogService interface {
+	List(ctx context.Context, filter models.AccessLogFilter, page pagination.Request) (pagination.Page[models.AccessLogResponse], error)
+}
+
+// SearchService defines the service for searching software, entities and categories
+type SearchService interface {
+	Search(ctx context.Context, query models.SearchQuery, page pagination.Request) (models.SearchResponse, error)
+}
+
+// ImportService defines the service for bulk import operations
+type ImportService interface {
+	ImportSoftware(ctx context.Context, r io.Reader, opts models.ImportOptions) (models.ImportReport, error)
+	PreviewImport(ctx context.Context, r io.Reader, mappingProfileID string, limit int) (models.ImportPreview, error)
+	CreateJob(ctx context.Context, req models.CreateImportJobRequest) (models.ImportJobResponse, error)
+	GetJob(ctx context.Context, id string) (models.ImportJobResponse, error)
+	ListJobs(ctx context.Context, page pagination.Request) (pagination.Page[models.ImportJobResponse], error)
+	GetJobFile(ctx context.Context, id string) (models.ImportFile, error)
+	RerunJob(ctx context.Context, id string, uploadedBy string, duplicateAction models.DuplicateAction) (models.ImportJobResponse, error)
+}
+
+// ImportMappingProfileService defines the service for import mapping profile-related operations
+type ImportMappingProfileService interface {
+	Create(ctx context.Context, req models.CreateImportMappingProfileRequest) (models.ImportMappingProfileResponse, error)
+	GetByID(ctx context.Context, id string) (models.ImportMappingProfileResponse, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[models.ImportMappingProfileResponse], error)
+	Update(ctx context.Context, id string, req models.UpdateImportMappingProfileRequest) error
 	Delete(ctx context.Context, id string) error
 }
+
+// ClassificationService defines the service for classifying software and reviewing the suggestions
+type ClassificationService interface {
+	Classify(ctx context.Context, softwareID string) ([]models.ClassificationSuggestionResponse, error)
+	ClassifySoftware(ctx context.Context, software models.Software) ([]models.ClassificationSuggestionResponse, error)
+	ListBySoftware(ctx context.Context, softwareID string) ([]models.ClassificationSuggestionResponse, error)
+	ListByStatus(ctx context.Context, status models.SuggestionStatus, page pagination.Request) (pagination.Page[models.ClassificationSuggestionResponse], error)
+	Accept(ctx context.Context, id string, reviewedBy string) error
+	Reject(ctx context.Context, id string, reviewedBy string) error
+}
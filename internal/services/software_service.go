@@ -2,31 +2,72 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
+	"apm/internal/apperr"
+	"apm/internal/audit"
 	"apm/internal/db/repository"
 	"apm/internal/models"
+	"apm/internal/pagination"
+
+	"github.com/jackc/pgx/v4"
 )
 
 // Ensure implementation satisfies the interface
 var _ SoftwareService = (*softwareService)(nil)
 
+// duplicateSimilarityThreshold is the minimum trigram similarity of display name and vendor
+// for existing software to be considered a duplicate
+const duplicateSimilarityThreshold = 0.6
+
+// Errors returned by software operations
+var (
+	ErrSoftwareNotFound        = apperr.NotFound("software not found")
+	ErrBusinessOwnerRequired   = apperr.Conflict("active software must have a business owner")
+	ErrSoftwareVersionNotFound = apperr.NotFound("software version not found")
+	ErrSoftwareNotDeleted      = apperr.Conflict("software is not deleted")
+	ErrInvalidSoftwareFilter   = apperr.Validation("invalid software filter")
+)
+
 // softwareService implements SoftwareService
 type softwareService struct {
-	repo   repository.SoftwareRepository
-	logger *log.Logger
+	repo           repository.SoftwareRepository
+	stakeholders   repository.StakeholderRepository
+	users          UserService
+	classification ClassificationService
+	audit          *audit.Trail
+	logger         *log.Logger
 }
 
-// NewSoftwareService creates a new software service
-func NewSoftwareService(repo repository.SoftwareRepository, logger *log.Logger) SoftwareService {
+// NewSoftwareService creates a new software service; newly created software is classified
+// by the classification service, if one is given. Software only becomes active once one of
+// its stakeholders is its business owner, or is created active with a business owner of the
+// same organization, looked up through the user service. Changes are recorded in the audit
+// trail, and every change stores a full snapshot of the software as its next version.
+func NewSoftwareService(
+	repo repository.SoftwareRepository,
+	stakeholders repository.StakeholderRepository,
+	users UserService,
+	classification ClassificationService,
+	trail *audit.Trail,
+	logger *log.Logger,
+) SoftwareService {
 	return &softwareService{
-		repo:   repo,
-		logger: logger,
+		repo:           repo,
+		stakeholders:   stakeholders,
+		users:          users,
+		classification: classification,
+		audit:          trail,
+		logger:         logger,
 	}
 }
 
-// Create creates a new software entity
+// Create creates a new software entity. Active software must be created with a business
+// owner, who is assigned to it in the same step.
 func (s *softwareService) Create(ctx context.Context, req models.CreateSoftwareRequest) (models.SoftwareResponse, error) {
 	s.logger.Println("Creating new software:", req.DisplayName)
 
@@ -46,13 +87,38 @@ func (s *softwareService) Create(ctx context.Context, req models.CreateSoftwareR
 		ImplementationStatus: req.ImplementationStatus,
 	}
 
+	var stakeholders []models.Stakeholder
+	if req.BusinessOwnerID != "" {
+		if _, err := s.users.GetByID(ctx, req.BusinessOwnerID); err != nil {
+			return models.SoftwareResponse{}, err
+		}
+		stakeholders = append(stakeholders, models.Stakeholder{
+			UserID: req.BusinessOwnerID,
+			Role:   models.StakeholderRoleBusinessOwner,
+		})
+	} else if isActiveSoftware(req.LifecycleStatus) {
+		return models.SoftwareResponse{}, ErrBusinessOwnerRequired
+	}
+
 	// Create the software entity using the repository
-	createdSoftware, err := s.repo.Create(ctx, software)
+	createdSoftware, err := s.repo.Create(ctx, software, stakeholders...)
 	if err != nil {
 		s.logger.Printf("Error creating software: %v", err)
 		return models.SoftwareResponse{}, fmt.Errorf("failed to create software: %w", err)
 	}
 
+	// The change is made even if recording it fails
+	if err := s.audit.RecordCreate(ctx, audit.EntitySoftware, createdSoftware.ID, createdSoftware); err != nil {
+		s.logger.Printf("Error recording created software: %v", err)
+	}
+
+	// Suggest a classification for review; the software is created even if this fails
+	if s.classification != nil {
+		if _, err := s.classification.ClassifySoftware(ctx, createdSoftware); err != nil {
+			s.logger.Printf("Error classifying created software: %v", err)
+		}
+	}
+
 	// Convert created software to response model
 	return s.mapSoftwareToResponse(createdSoftware), nil
 }
@@ -62,6 +128,9 @@ func (s *softwareService) GetByID(ctx context.Context, id string) (models.Softwa
 	s.logger.Println("Getting software by ID:", id)
 
 	software, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.SoftwareResponse{}, fmt.Errorf("%w: %s", ErrSoftwareNotFound, id)
+	}
 	if err != nil {
 		s.logger.Printf("Error getting software by ID: %v", err)
 		return models.SoftwareResponse{}, fmt.Errorf("failed to get software: %w", err)
@@ -70,35 +139,57 @@ func (s *softwareService) GetByID(ctx context.Context, id string) (models.Softwa
 	return s.mapSoftwareToResponse(software), nil
 }
 
-// List retrieves a list of software entities with pagination
-func (s *softwareService) List(ctx context.Context, limit, offset int) ([]models.SoftwareResponse, error) {
-	s.logger.Printf("Listing software (limit: %d, offset: %d)", limit, offset)
+// GetByIDIncludingArchived retrieves a software entity by ID, whether it is archived or not
+func (s *softwareService) GetByIDIncludingArchived(ctx context.Context, id string) (models.SoftwareResponse, error) {
+	s.logger.Println("Getting software by ID, including archived software:", id)
 
-	// Validate pagination parameters
-	if limit <= 0 {
-		limit = 10 // Default limit
-	}
-	if limit > 100 {
-		limit = 100 // Max limit
+	software, err := s.repo.GetByIDIncludingArchived(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.SoftwareResponse{}, fmt.Errorf("%w: %s", ErrSoftwareNotFound, id)
 	}
-	if offset < 0 {
-		offset = 0
+	if err != nil {
+		s.logger.Printf("Error getting software by ID: %v", err)
+		return models.SoftwareResponse{}, fmt.Errorf("failed to get software: %w", err)
 	}
 
-	// Get software list from repository
-	softwareList, err := s.repo.List(ctx, limit, offset)
+	return s.mapSoftwareToResponse(software), nil
+}
+
+// List retrieves a list of software entities matching the filter with pagination
+func (s *softwareService) List(ctx context.Context, filter models.SoftwareFilter, page pagination.Request) (pagination.Page[models.SoftwareResponse], error) {
+	s.logger.Printf("Listing software (limit: %d, offset: %d, archived: %t)", page.Limit, page.Offset, filter.IncludeArchived)
+
+	// Get software list from repository, which rejects fields software cannot be sorted by
+	softwareList, err := s.repo.List(ctx, filter, page)
+	if errors.Is(err, repository.ErrUnknownSortField) {
+		return pagination.Page[models.SoftwareResponse]{}, fmt.Errorf("%w: %w", ErrInvalidSoftwareFilter, err)
+	}
 	if err != nil {
 		s.logger.Printf("Error listing software: %v", err)
-		return nil, fmt.Errorf("failed to list software: %w", err)
+		return pagination.Page[models.SoftwareResponse]{}, fmt.Errorf("failed to list software: %w", err)
 	}
 
 	// Map software entities to response models
-	var responseList []models.SoftwareResponse
-	for _, software := range softwareList {
-		responseList = append(responseList, s.mapSoftwareToResponse(software))
+	return pagination.Map(softwareList, s.mapSoftwareToResponse), nil
+}
+
+// FindDuplicates retrieves existing software that the requested software may duplicate,
+// matching on foreign key or on a similar display name and vendor
+func (s *softwareService) FindDuplicates(ctx context.Context, req models.CreateSoftwareRequest) ([]models.SoftwareMatch, error) {
+	s.logger.Println("Finding duplicates of software:", req.DisplayName)
+
+	matches, err := s.repo.FindDuplicates(ctx,
+		strings.TrimSpace(req.ForeignKey),
+		normalizeName(req.DisplayName),
+		normalizeName(req.Vendor),
+		duplicateSimilarityThreshold,
+	)
+	if err != nil {
+		s.logger.Printf("Error finding duplicate software: %v", err)
+		return nil, fmt.Errorf("failed to find duplicate software: %w", err)
 	}
 
-	return responseList, nil
+	return matches, nil
 }
 
 // Update updates an existing software entity
@@ -111,6 +202,7 @@ func (s *softwareService) Update(ctx context.Context, id string, req models.Upda
 		s.logger.Printf("Error getting software to update: %v", err)
 		return fmt.Errorf("failed to get software for update: %w", err)
 	}
+	before := existingSoftware
 
 	// Update fields if they are provided
 	if req.DisplayName != "" {
@@ -141,6 +233,11 @@ func (s *softwareService) Update(ctx context.Context, id string, req models.Upda
 		existingSoftware.Context = req.Context
 	}
 	if req.LifecycleStatus != "" {
+		if isActiveSoftware(req.LifecycleStatus) && !isActiveSoftware(existingSoftware.LifecycleStatus) {
+			if err := s.ensureBusinessOwner(ctx, id); err != nil {
+				return err
+			}
+		}
 		existingSoftware.LifecycleStatus = req.LifecycleStatus
 	}
 	if req.ImplementationStatus != "" {
@@ -157,20 +254,255 @@ func (s *softwareService) Update(ctx context.Context, id string, req models.Upda
 		return fmt.Errorf("failed to update software: %w", err)
 	}
 
+	if err := s.audit.RecordUpdate(ctx, audit.EntitySoftware, id, before, existingSoftware); err != nil {
+		s.logger.Printf("Error recording updated software: %v", err)
+	}
+
 	return nil
 }
 
-// Delete removes a software entity
+// Delete archives a software entity along with its stakeholders; archived software is hidden
+// until it is undeleted, or purged once the retention period has passed
 func (s *softwareService) Delete(ctx context.Context, id string) error {
 	s.logger.Println("Deleting software with ID:", id)
 
+	software, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		s.logger.Printf("Error getting software to delete: %v", err)
+		return fmt.Errorf("failed to get software for deletion: %w", err)
+	}
+
 	// Delete the software entity using the repository
-	err := s.repo.Delete(ctx, id)
+	err = s.repo.Delete(ctx, id)
 	if err != nil {
 		s.logger.Printf("Error deleting software: %v", err)
 		return fmt.Errorf("failed to delete software: %w", err)
 	}
 
+	if err := s.audit.RecordDelete(ctx, audit.EntitySoftware, id, software); err != nil {
+		s.logger.Printf("Error recording deleted software: %v", err)
+	}
+
+	return nil
+}
+
+// Undelete restores archived software along with the stakeholders archived with it
+func (s *softwareService) Undelete(ctx context.Context, id string) (models.SoftwareResponse, error) {
+	s.logger.Println("Undeleting software with ID:", id)
+
+	archived, err := s.repo.GetByIDIncludingArchived(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.SoftwareResponse{}, fmt.Errorf("%w: %s", ErrSoftwareNotFound, id)
+	}
+	if err != nil {
+		s.logger.Printf("Error getting software to undelete: %v", err)
+		return models.SoftwareResponse{}, fmt.Errorf("failed to get software for undelete: %w", err)
+	}
+	if archived.DeletedAt == nil {
+		return models.SoftwareResponse{}, fmt.Errorf("%w: %s", ErrSoftwareNotDeleted, id)
+	}
+
+	restored, err := s.repo.Undelete(ctx, id)
+	if err != nil {
+		s.logger.Printf("Error undeleting software: %v", err)
+		return models.SoftwareResponse{}, fmt.Errorf("failed to undelete software: %w", err)
+	}
+
+	if err := s.audit.RecordUpdate(ctx, audit.EntitySoftware, id, archived, restored); err != nil {
+		s.logger.Printf("Error recording undeleted software: %v", err)
+	}
+
+	return s.mapSoftwareToResponse(restored), nil
+}
+
+// ListVersions retrieves the versions of software with pagination, newest first
+func (s *softwareService) ListVersions(ctx context.Context, id string, page pagination.Request) (pagination.Page[models.SoftwareVersionResponse], error) {
+	s.logger.Printf("Listing versions of software %s (limit: %d, offset: %d)", id, page.Limit, page.Offset)
+
+	versions, err := s.repo.ListVersions(ctx, id, page)
+	if err != nil {
+		s.logger.Printf("Error listing software versions: %v", err)
+		return pagination.Page[models.SoftwareVersionResponse]{}, fmt.Errorf("failed to list software versions: %w", err)
+	}
+
+	return pagination.Map(versions, s.mapSoftwareVersionToResponse), nil
+}
+
+// GetVersion retrieves a version of software by its number
+func (s *softwareService) GetVersion(ctx context.Context, id string, version int) (models.SoftwareVersionResponse, error) {
+	s.logger.Printf("Getting version %d of software %s", version, id)
+
+	softwareVersion, err := s.getVersion(ctx, id, version)
+	if err != nil {
+		return models.SoftwareVersionResponse{}, err
+	}
+
+	return s.mapSoftwareVersionToResponse(softwareVersion), nil
+}
+
+// GetAsOf retrieves the version of software that was current at a time. Software that did
+// not exist at the time, not yet or no longer, has no version then.
+func (s *softwareService) GetAsOf(ctx context.Context, id string, at time.Time) (models.SoftwareVersionResponse, error) {
+	s.logger.Printf("Getting software %s as of %s", id, at.Format(time.RFC3339))
+
+	version, err := s.repo.GetVersionAt(ctx, id, at)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && version.Action == models.SoftwareVersionActionDelete) {
+		return models.SoftwareVersionResponse{}, fmt.Errorf("%w: %s as of %s", ErrSoftwareVersionNotFound, id, at.Format(time.RFC3339))
+	}
+	if err != nil {
+		s.logger.Printf("Error getting software version at time: %v", err)
+		return models.SoftwareVersionResponse{}, fmt.Errorf("failed to get software version: %w", err)
+	}
+
+	return s.mapSoftwareVersionToResponse(version), nil
+}
+
+// DiffVersions compares two versions of software, returning the changes of its fields from
+// the one version to the other
+func (s *softwareService) DiffVersions(ctx context.Context, id string, from, to int) (models.SoftwareVersionDiff, error) {
+	s.logger.Printf("Comparing versions %d and %d of software %s", from, to, id)
+
+	fromVersion, err := s.getVersion(ctx, id, from)
+	if err != nil {
+		return models.SoftwareVersionDiff{}, err
+	}
+	toVersion, err := s.getVersion(ctx, id, to)
+	if err != nil {
+		return models.SoftwareVersionDiff{}, err
+	}
+
+	changes := []models.FieldChange{}
+	for _, change := range audit.Diff(fromVersion.Snapshot, toVersion.Snapshot) {
+		changes = append(changes, models.FieldChange{
+			Field:    change.Field,
+			OldValue: change.OldValue,
+			NewValue: change.NewValue,
+		})
+	}
+
+	return models.SoftwareVersionDiff{
+		SoftwareID:  id,
+		FromVersion: from,
+		ToVersion:   to,
+		Changes:     changes,
+	}, nil
+}
+
+// RestoreVersion brings software back to the state of a previous version, recreating it if
+// it was deleted in the meantime
+func (s *softwareService) RestoreVersion(ctx context.Context, id string, version int) (models.SoftwareResponse, error) {
+	s.logger.Printf("Restoring version %d of software %s", version, id)
+
+	softwareVersion, err := s.getVersion(ctx, id, version)
+	if err != nil {
+		return models.SoftwareResponse{}, err
+	}
+
+	return s.restore(ctx, id, softwareVersion.Snapshot)
+}
+
+// Restore brings back deleted software as it was when it was deleted. Archived software is
+// undeleted along with its stakeholders; software that was purged is recreated from its last
+// version, without them.
+func (s *softwareService) Restore(ctx context.Context, id string) (models.SoftwareResponse, error) {
+	s.logger.Println("Restoring deleted software:", id)
+
+	software, err := s.repo.GetByIDIncludingArchived(ctx, id)
+	if err == nil {
+		if software.DeletedAt == nil {
+			return models.SoftwareResponse{}, fmt.Errorf("%w: %s", ErrSoftwareNotDeleted, id)
+		}
+		return s.Undelete(ctx, id)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		s.logger.Printf("Error getting software to restore: %v", err)
+		return models.SoftwareResponse{}, fmt.Errorf("failed to get software for restore: %w", err)
+	}
+
+	version, err := s.repo.GetLatestVersion(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.SoftwareResponse{}, fmt.Errorf("%w: %s", ErrSoftwareNotFound, id)
+	}
+	if err != nil {
+		s.logger.Printf("Error getting last version of software: %v", err)
+		return models.SoftwareResponse{}, fmt.Errorf("failed to get software version: %w", err)
+	}
+
+	return s.restore(ctx, id, version.Snapshot)
+}
+
+// restore brings software back to a snapshot. Like updates, restoring active software over
+// software that is not active requires a business owner. Purged software has none, as its
+// stakeholders were purged with it, so it cannot be restored as active software.
+func (s *softwareService) restore(ctx context.Context, id string, snapshot models.Software) (models.SoftwareResponse, error) {
+	existing, err := s.repo.GetByID(ctx, id)
+	exists := err == nil
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		s.logger.Printf("Error getting software to restore: %v", err)
+		return models.SoftwareResponse{}, fmt.Errorf("failed to get software for restore: %w", err)
+	}
+
+	if exists && isActiveSoftware(snapshot.LifecycleStatus) && !isActiveSoftware(existing.LifecycleStatus) {
+		if err := s.ensureBusinessOwner(ctx, id); err != nil {
+			return models.SoftwareResponse{}, err
+		}
+	}
+	if !exists && isActiveSoftware(snapshot.LifecycleStatus) {
+		_, err := s.repo.GetByIDIncludingArchived(ctx, id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.SoftwareResponse{}, ErrBusinessOwnerRequired
+		}
+		if err != nil {
+			s.logger.Printf("Error getting software to restore: %v", err)
+			return models.SoftwareResponse{}, fmt.Errorf("failed to get software for restore: %w", err)
+		}
+	}
+
+	snapshot.ID = id
+	restored, err := s.repo.Restore(ctx, snapshot)
+	if err != nil {
+		s.logger.Printf("Error restoring software: %v", err)
+		return models.SoftwareResponse{}, fmt.Errorf("failed to restore software: %w", err)
+	}
+
+	if exists {
+		err = s.audit.RecordUpdate(ctx, audit.EntitySoftware, id, existing, restored)
+	} else {
+		err = s.audit.RecordCreate(ctx, audit.EntitySoftware, id, restored)
+	}
+	if err != nil {
+		s.logger.Printf("Error recording restored software: %v", err)
+	}
+
+	return s.mapSoftwareToResponse(restored), nil
+}
+
+// getVersion retrieves a version of software, returning ErrSoftwareVersionNotFound if there is none
+func (s *softwareService) getVersion(ctx context.Context, id string, version int) (models.SoftwareVersion, error) {
+	softwareVersion, err := s.repo.GetVersion(ctx, id, version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.SoftwareVersion{}, fmt.Errorf("%w: version %d of %s", ErrSoftwareVersionNotFound, version, id)
+	}
+	if err != nil {
+		s.logger.Printf("Error getting software version: %v", err)
+		return models.SoftwareVersion{}, fmt.Errorf("failed to get software version: %w", err)
+	}
+	return softwareVersion, nil
+}
+
+// ensureBusinessOwner returns ErrBusinessOwnerRequired if the software has no business owner
+func (s *softwareService) ensureBusinessOwner(ctx context.Context, id string) error {
+	count, err := s.stakeholders.CountBySoftwareAndRole(ctx, id, models.StakeholderRoleBusinessOwner)
+	if err != nil {
+		s.logger.Printf("Error counting business owners of software: %v", err)
+		return fmt.Errorf("failed to count business owners: %w", err)
+	}
+	if count == 0 {
+		return ErrBusinessOwnerRequired
+	}
 	return nil
 }
 
@@ -192,5 +524,30 @@ func (s *softwareService) mapSoftwareToResponse(software models.Software) models
 		ImplementationStatus: software.ImplementationStatus,
 		CreatedAt:            software.CreatedAt,
 		UpdatedAt:            software.UpdatedAt,
+		DeletedAt:            software.DeletedAt,
+		DeletedBy:            software.DeletedBy,
 	}
 }
+
+// mapSoftwareVersionToResponse maps a version of software to its response
+func (s *softwareService) mapSoftwareVersionToResponse(version models.SoftwareVersion) models.SoftwareVersionResponse {
+	return models.SoftwareVersionResponse{
+		ID:         version.ID,
+		SoftwareID: version.SoftwareID,
+		Version:    version.Version,
+		Action:     version.Action,
+		Snapshot:   s.mapSoftwareToResponse(version.Snapshot),
+		UserID:     version.UserID,
+		CreatedAt:  version.CreatedAt,
+	}
+}
+
+// isActiveSoftware reports whether a lifecycle status is that of software in use
+func isActiveSoftware(lifecycleStatus string) bool {
+	return strings.EqualFold(strings.TrimSpace(lifecycleStatus), models.LifecycleStatusActive)
+}
+
+// normalizeName lower-cases a name and collapses its whitespace for comparison
+func normalizeName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
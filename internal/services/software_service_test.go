@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+
+	"apm/internal/apperr"
+	"apm/internal/db/repository"
+	"apm/internal/models"
+	"apm/internal/pagination"
+	"apm/internal/tenant"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// memorySoftwareRepository keeps software in memory, scoping every operation to the
+// organization of the context like the Postgres repository does
+type memorySoftwareRepository struct {
+	repository.SoftwareRepository
+	software      map[string]models.Software
+	organizations map[string]string // Organization of each software ID
+}
+
+func newMemorySoftwareRepository() *memorySoftwareRepository {
+	return &memorySoftwareRepository{
+		software:      map[string]models.Software{},
+		organizations: map[string]string{},
+	}
+}
+
+func (r *memorySoftwareRepository) Create(ctx context.Context, software models.Software, stakeholders ...models.Stakeholder) (models.Software, error) {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return models.Software{}, err
+	}
+	software.ID = "software-" + software.DisplayName
+	r.software[software.ID] = software
+	r.organizations[software.ID] = organizationID
+	return software, nil
+}
+
+func (r *memorySoftwareRepository) GetByID(ctx context.Context, id string) (models.Software, error) {
+	if !r.visible(ctx, id) {
+		return models.Software{}, apperr.Wrap(apperr.KindNotFound, "record not found", pgx.ErrNoRows)
+	}
+	return r.software[id], nil
+}
+
+func (r *memorySoftwareRepository) List(ctx context.Context, filter models.SoftwareFilter, page pagination.Request) (pagination.Page[models.Software], error) {
+	var result pagination.Page[models.Software]
+	for id, software := range r.software {
+		if r.visible(ctx, id) {
+			result.Items = append(result.Items, software)
+		}
+	}
+	return result, nil
+}
+
+func (r *memorySoftwareRepository) Update(ctx context.Context, software models.Software) error {
+	if !r.visible(ctx, software.ID) {
+		return apperr.Wrap(apperr.KindNotFound, "record not found", pgx.ErrNoRows)
+	}
+	r.software[software.ID] = software
+	return nil
+}
+
+func (r *memorySoftwareRepository) Delete(ctx context.Context, id string) error {
+	if r.visible(ctx, id) {
+		delete(r.software, id)
+	}
+	return nil
+}
+
+// visible reports whether the software exists in the organization of the context
+func (r *memorySoftwareRepository) visible(ctx context.Context, id string) bool {
+	organizationID, err := tenant.OrganizationID(ctx)
+	if err != nil {
+		return false
+	}
+	_, ok := r.software[id]
+	return ok && r.organizations[id] == organizationID
+}
+
+func TestSoftwareServiceTenantIsolation(t *testing.T) {
+	repo := newMemorySoftwareRepository()
+	service := NewSoftwareService(repo, nil, nil, nil, nil, log.New(io.Discard, "", 0))
+
+	orgA := tenant.WithOrganizationID(context.Background(), "org-a")
+	orgB := tenant.WithOrganizationID(context.Background(), "org-b")
+
+	created, err := service.Create(orgA, models.CreateSoftwareRequest{
+		DisplayName:  "Ledger",
+		SoftwareType: models.SoftwareType("web"),
+		Vendor:       "Acme",
+	})
+	if err != nil {
+		t.Fatalf("Create() in organization A = %v", err)
+	}
+
+	t.Run("read", func(t *testing.T) {
+		_, err := service.GetByID(orgB, created.ID)
+		if apperr.KindOf(err) != apperr.KindNotFound {
+			t.Errorf("GetByID() in organization B = %v, want not found", err)
+		}
+
+		page, err := service.List(orgB, models.SoftwareFilter{}, pagination.Request{Limit: 10})
+		if err != nil {
+			t.Fatalf("List() in organization B = %v", err)
+		}
+		if len(page.Items) != 0 {
+			t.Errorf("List() in organization B returned %d software, want none", len(page.Items))
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		err := service.Update(orgB, created.ID, models.UpdateSoftwareRequest{DisplayName: "Taken over"})
+		if apperr.KindOf(err) != apperr.KindNotFound {
+			t.Errorf("Update() in organization B = %v, want not found", err)
+		}
+		if got := repo.software[created.ID].DisplayName; got != "Ledger" {
+			t.Errorf("display name after update in organization B = %q, want %q", got, "Ledger")
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if err := service.Delete(orgB, created.ID); err != nil {
+			t.Fatalf("Delete() in organization B = %v", err)
+		}
+		if _, err := service.GetByID(orgA, created.ID); err != nil {
+			t.Errorf("GetByID() in organization A after delete in organization B = %v", err)
+		}
+	})
+}
@@ -0,0 +1,297 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"apm/internal/apperr"
+	"apm/internal/audit"
+	"apm/internal/db/repository"
+	"apm/internal/models"
+	"apm/internal/pagination"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// Ensure implementation satisfies the interface
+var _ StakeholderService = (*stakeholderService)(nil)
+
+// Errors returned by stakeholder operations
+var (
+	ErrStakeholderNotFound = apperr.NotFound("stakeholder not found")
+	ErrStakeholderExists   = apperr.Conflict("the user already has this role for the software")
+)
+
+// stakeholderService implements StakeholderService
+type stakeholderService struct {
+	repo     repository.StakeholderRepository
+	software SoftwareService
+	users    UserService
+	audit    *audit.Trail
+	logger   *log.Logger
+}
+
+// NewStakeholderService creates a new stakeholder service; software and users are looked up
+// through their services, so only those of the same organization can be assigned. Changes
+// to assignments are recorded in the audit trail.
+func NewStakeholderService(
+	repo repository.StakeholderRepository,
+	software SoftwareService,
+	users UserService,
+	trail *audit.Trail,
+	logger *log.Logger,
+) StakeholderService {
+	return &stakeholderService{
+		repo:     repo,
+		software: software,
+		users:    users,
+		audit:    trail,
+		logger:   logger,
+	}
+}
+
+// Create assigns a user to software in a stakeholder role
+func (s *stakeholderService) Create(ctx context.Context, req models.CreateStakeholderRequest) (models.StakeholderResponse, error) {
+	s.logger.Printf("Assigning user %s to software %s as %s", req.UserID, req.SoftwareID, req.Role)
+
+	if err := validate.Struct(req); err != nil {
+		return models.StakeholderResponse{}, validationError("stakeholder", err)
+	}
+
+	if _, err := s.software.GetByID(ctx, req.SoftwareID); err != nil {
+		return models.StakeholderResponse{}, err
+	}
+	user, err := s.users.GetByID(ctx, req.UserID)
+	if err != nil {
+		return models.StakeholderResponse{}, err
+	}
+
+	existing, err := s.repo.GetBySoftwareID(ctx, req.SoftwareID)
+	if err != nil {
+		s.logger.Printf("Error getting stakeholders of software: %v", err)
+		return models.StakeholderResponse{}, fmt.Errorf("failed to get stakeholders: %w", err)
+	}
+	for _, stakeholder := range existing {
+		if stakeholder.UserID == req.UserID && stakeholder.Role == req.Role {
+			return models.StakeholderResponse{}, ErrStakeholderExists
+		}
+	}
+
+	stakeholder, err := s.repo.Create(ctx, models.Stakeholder{
+		ForeignKey: req.ForeignKey,
+		SoftwareID: req.SoftwareID,
+		UserID:     req.UserID,
+		Role:       req.Role,
+	})
+	if err != nil {
+		s.logger.Printf("Error creating stakeholder: %v", err)
+		return models.StakeholderResponse{}, fmt.Errorf("failed to create stakeholder: %w", err)
+	}
+
+	// The change is made even if recording it fails
+	if err := s.audit.RecordCreate(ctx, audit.EntityStakeholder, stakeholder.ID, stakeholder); err != nil {
+		s.logger.Printf("Error recording created stakeholder: %v", err)
+	}
+
+	resp := s.mapStakeholderToResponse(stakeholder)
+	resp.User = &user
+	return resp, nil
+}
+
+// GetByID retrieves a stakeholder by ID
+func (s *stakeholderService) GetByID(ctx context.Context, id string) (models.StakeholderResponse, error) {
+	s.logger.Println("Getting stakeholder by ID:", id)
+
+	stakeholder, err := s.getStakeholder(ctx, id)
+	if err != nil {
+		s.logger.Printf("Error getting stakeholder by ID: %v", err)
+		return models.StakeholderResponse{}, fmt.Errorf("failed to get stakeholder: %w", err)
+	}
+
+	return s.mapStakeholderToResponse(stakeholder), nil
+}
+
+// List retrieves a list of stakeholders with pagination, including archived ones if asked to
+func (s *stakeholderService) List(ctx context.Context, includeArchived bool, page pagination.Request) (pagination.Page[models.StakeholderResponse], error) {
+	s.logger.Printf("Listing stakeholders (limit: %d, offset: %d, archived: %t)", page.Limit, page.Offset, includeArchived)
+
+	stakeholders, err := s.repo.List(ctx, includeArchived, page)
+	if err != nil {
+		s.logger.Printf("Error listing stakeholders: %v", err)
+		return pagination.Page[models.StakeholderResponse]{}, fmt.Errorf("failed to list stakeholders: %w", err)
+	}
+
+	return pagination.Map(stakeholders, s.mapStakeholderToResponse), nil
+}
+
+// Update updates the role or foreign key of a stakeholder
+func (s *stakeholderService) Update(ctx context.Context, id string, req models.UpdateStakeholderRequest) error {
+	s.logger.Println("Updating stakeholder with ID:", id)
+
+	if err := validate.Struct(req); err != nil {
+		return validationError("stakeholder", err)
+	}
+
+	stakeholder, err := s.getStakeholder(ctx, id)
+	if err != nil {
+		s.logger.Printf("Error getting stakeholder to update: %v", err)
+		return fmt.Errorf("failed to get stakeholder for update: %w", err)
+	}
+	before := stakeholder
+
+	// Update fields if they are provided
+	if req.Role != "" && req.Role != stakeholder.Role {
+		if err := s.ensureNotLastBusinessOwner(ctx, stakeholder); err != nil {
+			return err
+		}
+		stakeholder.Role = req.Role
+	}
+	if req.ForeignKey != "" {
+		stakeholder.ForeignKey = req.ForeignKey
+	}
+
+	if err := s.repo.Update(ctx, stakeholder); err != nil {
+		s.logger.Printf("Error updating stakeholder: %v", err)
+		return fmt.Errorf("failed to update stakeholder: %w", err)
+	}
+
+	if err := s.audit.RecordUpdate(ctx, audit.EntityStakeholder, id, before, stakeholder); err != nil {
+		s.logger.Printf("Error recording updated stakeholder: %v", err)
+	}
+
+	return nil
+}
+
+// Delete archives a stakeholder, unless they are the last business owner of active software
+func (s *stakeholderService) Delete(ctx context.Context, id string) error {
+	s.logger.Println("Deleting stakeholder with ID:", id)
+
+	stakeholder, err := s.getStakeholder(ctx, id)
+	if err != nil {
+		s.logger.Printf("Error getting stakeholder to delete: %v", err)
+		return fmt.Errorf("failed to get stakeholder for deletion: %w", err)
+	}
+	if err := s.ensureNotLastBusinessOwner(ctx, stakeholder); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		s.logger.Printf("Error deleting stakeholder: %v", err)
+		return fmt.Errorf("failed to delete stakeholder: %w", err)
+	}
+
+	if err := s.audit.RecordDelete(ctx, audit.EntityStakeholder, id, stakeholder); err != nil {
+		s.logger.Printf("Error recording deleted stakeholder: %v", err)
+	}
+
+	return nil
+}
+
+// ListBySoftware retrieves the stakeholders of software, including their users
+func (s *stakeholderService) ListBySoftware(ctx context.Context, softwareID string) ([]models.StakeholderResponse, error) {
+	s.logger.Println("Listing stakeholders of software:", softwareID)
+
+	if _, err := s.software.GetByID(ctx, softwareID); err != nil {
+		return nil, err
+	}
+
+	stakeholders, err := s.repo.GetBySoftwareID(ctx, softwareID)
+	if err != nil {
+		s.logger.Printf("Error listing stakeholders of software: %v", err)
+		return nil, fmt.Errorf("failed to list stakeholders of software: %w", err)
+	}
+
+	var responseList []models.StakeholderResponse
+	for _, stakeholder := range stakeholders {
+		user, err := s.users.GetByID(ctx, stakeholder.UserID)
+		if err != nil {
+			return nil, err
+		}
+		resp := s.mapStakeholderToResponse(stakeholder)
+		resp.User = &user
+		responseList = append(responseList, resp)
+	}
+
+	return responseList, nil
+}
+
+// ListByUser retrieves the stakeholder assignments of a user, including their software
+func (s *stakeholderService) ListByUser(ctx context.Context, userID string) ([]models.StakeholderResponse, error) {
+	s.logger.Println("Listing software of stakeholder:", userID)
+
+	if _, err := s.users.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	stakeholders, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Printf("Error listing stakeholder assignments of user: %v", err)
+		return nil, fmt.Errorf("failed to list stakeholder assignments of user: %w", err)
+	}
+
+	var responseList []models.StakeholderResponse
+	for _, stakeholder := range stakeholders {
+		software, err := s.software.GetByID(ctx, stakeholder.SoftwareID)
+		if err != nil {
+			return nil, err
+		}
+		resp := s.mapStakeholderToResponse(stakeholder)
+		resp.Software = &software
+		responseList = append(responseList, resp)
+	}
+
+	return responseList, nil
+}
+
+// getStakeholder retrieves a stakeholder by ID, returning ErrStakeholderNotFound if there is
+// no such stakeholder
+func (s *stakeholderService) getStakeholder(ctx context.Context, id string) (models.Stakeholder, error) {
+	stakeholder, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Stakeholder{}, fmt.Errorf("%w: %s", ErrStakeholderNotFound, id)
+	}
+	return stakeholder, err
+}
+
+// ensureNotLastBusinessOwner returns ErrBusinessOwnerRequired if the stakeholder is the only
+// business owner of active software, which may not be left without one
+func (s *stakeholderService) ensureNotLastBusinessOwner(ctx context.Context, stakeholder models.Stakeholder) error {
+	if stakeholder.Role != models.StakeholderRoleBusinessOwner {
+		return nil
+	}
+
+	software, err := s.software.GetByID(ctx, stakeholder.SoftwareID)
+	if err != nil {
+		return err
+	}
+	if !isActiveSoftware(software.LifecycleStatus) {
+		return nil
+	}
+
+	count, err := s.repo.CountBySoftwareAndRole(ctx, stakeholder.SoftwareID, models.StakeholderRoleBusinessOwner)
+	if err != nil {
+		s.logger.Printf("Error counting business owners of software: %v", err)
+		return fmt.Errorf("failed to count business owners: %w", err)
+	}
+	if count <= 1 {
+		return ErrBusinessOwnerRequired
+	}
+	return nil
+}
+
+// Helper function to map Stakeholder to StakeholderResponse
+func (s *stakeholderService) mapStakeholderToResponse(stakeholder models.Stakeholder) models.StakeholderResponse {
+	return models.StakeholderResponse{
+		ID:         stakeholder.ID,
+		ForeignKey: stakeholder.ForeignKey,
+		SoftwareID: stakeholder.SoftwareID,
+		UserID:     stakeholder.UserID,
+		Role:       stakeholder.Role,
+		CreatedAt:  stakeholder.CreatedAt,
+		UpdatedAt:  stakeholder.UpdatedAt,
+		DeletedAt:  stakeholder.DeletedAt,
+		DeletedBy:  stakeholder.DeletedBy,
+	}
+}
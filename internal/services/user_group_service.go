@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"apm/internal/apperr"
+	"apm/internal/audit"
+	"apm/internal/db/repository"
+	"apm/internal/models"
+	"apm/internal/pagination"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// Ensure implementation satisfies the interface
+var _ UserGroupService = (*userGroupService)(nil)
+
+// Errors returned by user group operations
+var (
+	ErrUserGroupNotFound = apperr.NotFound("user group not found")
+	ErrNotGroupMember    = apperr.NotFound("user is not a member of the group")
+)
+
+// userGroupService implements UserGroupService
+type userGroupService struct {
+	repo   repository.UserGroupRepository
+	users  UserService
+	audit  *audit.Trail
+	logger *log.Logger
+}
+
+// NewUserGroupService creates a new user group service; members are looked up through the
+// user service, so only users of the same organization can join a group. Changes to groups
+// and their members are recorded in the audit trail.
+func NewUserGroupService(repo repository.UserGroupRepository, users UserService, trail *audit.Trail, logger *log.Logger) UserGroupService {
+	return &userGroupService{
+		repo:   repo,
+		users:  users,
+		audit:  trail,
+		logger: logger,
+	}
+}
+
+// Create creates a new user group
+func (s *userGroupService) Create(ctx context.Context, req models.CreateUserGroupRequest) (models.UserGroupResponse, error) {
+	s.logger.Println("Creating new user group:", req.DisplayName)
+
+	if err := validate.Struct(req); err != nil {
+		return models.UserGroupResponse{}, validationError("user group", err)
+	}
+
+	group, err := s.repo.Create(ctx, models.UserGroup{DisplayName: req.DisplayName})
+	if err != nil {
+		s.logger.Printf("Error creating user group: %v", err)
+		return models.UserGroupResponse{}, fmt.Errorf("failed to create user group: %w", err)
+	}
+
+	// The change is made even if recording it fails
+	if err := s.audit.RecordCreate(ctx, audit.EntityUserGroup, group.ID, group); err != nil {
+		s.logger.Printf("Error recording created user group: %v", err)
+	}
+
+	return s.mapUserGroupToResponse(group), nil
+}
+
+// GetByID retrieves a user group by ID
+func (s *userGroupService) GetByID(ctx context.Context, id string) (models.UserGroupResponse, error) {
+	s.logger.Println("Getting user group by ID:", id)
+
+	group, err := s.getGroup(ctx, id)
+	if err != nil {
+		s.logger.Printf("Error getting user group by ID: %v", err)
+		return models.UserGroupResponse{}, fmt.Errorf("failed to get user group: %w", err)
+	}
+
+	return s.mapUserGroupToResponse(group), nil
+}
+
+// List retrieves a list of user groups with pagination
+func (s *userGroupService) List(ctx context.Context, page pagination.Request) (pagination.Page[models.UserGroupResponse], error) {
+	s.logger.Printf("Listing user groups (limit: %d, offset: %d)", page.Limit, page.Offset)
+
+	groups, err := s.repo.List(ctx, page)
+	if err != nil {
+		s.logger.Printf("Error listing user groups: %v", err)
+		return pagination.Page[models.UserGroupResponse]{}, fmt.Errorf("failed to list user groups: %w", err)
+	}
+
+	return pagination.Map(groups, s.mapUserGroupToResponse), nil
+}
+
+// Update updates an existing user group
+func (s *userGroupService) Update(ctx context.Context, id string, req models.UpdateUserGroupRequest) error {
+	s.logger.Println("Updating user group with ID:", id)
+
+	group, err := s.getGroup(ctx, id)
+	if err != nil {
+		s.logger.Printf("Error getting user group to update: %v", err)
+		return fmt.Errorf("failed to get user group for update: %w", err)
+	}
+	before := group
+
+	// Update fields if they are provided
+	if req.DisplayName != "" {
+		group.DisplayName = req.DisplayName
+	}
+
+	if err := s.repo.Update(ctx, group); err != nil {
+		s.logger.Printf("Error updating user group: %v", err)
+		return fmt.Errorf("failed to update user group: %w", err)
+	}
+
+	if err := s.audit.RecordUpdate(ctx, audit.EntityUserGroup, id, before, group); err != nil {
+		s.logger.Printf("Error recording updated user group: %v", err)
+	}
+
+	return nil
+}
+
+// Delete removes a user group; its members remain
+func (s *userGroupService) Delete(ctx context.Context, id string) error {
+	s.logger.Println("Deleting user group with ID:", id)
+
+	group, err := s.getGroup(ctx, id)
+	if errors.Is(err, ErrUserGroupNotFound) {
+		return nil
+	}
+	if err != nil {
+		s.logger.Printf("Error getting user group to delete: %v", err)
+		return fmt.Errorf("failed to get user group for deletion: %w", err)
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		s.logger.Printf("Error deleting user group: %v", err)
+		return fmt.Errorf("failed to delete user group: %w", err)
+	}
+
+	if err := s.audit.RecordDelete(ctx, audit.EntityUserGroup, id, group); err != nil {
+		s.logger.Printf("Error recording deleted user group: %v", err)
+	}
+
+	return nil
+}
+
+// AddMember adds a user to a user group; adding a member again has no effect
+func (s *userGroupService) AddMember(ctx context.Context, groupID string, req models.AddUserGroupMemberRequest) error {
+	s.logger.Printf("Adding user %s to user group %s", req.UserID, groupID)
+
+	if err := validate.Struct(req); err != nil {
+		return validationError("member", err)
+	}
+
+	if _, err := s.getGroup(ctx, groupID); err != nil {
+		s.logger.Printf("Error getting user group to add member: %v", err)
+		return fmt.Errorf("failed to get user group: %w", err)
+	}
+	if _, err := s.users.GetByID(ctx, req.UserID); err != nil {
+		return err
+	}
+
+	if err := s.repo.AddMember(ctx, groupID, req.UserID); err != nil {
+		s.logger.Printf("Error adding user group member: %v", err)
+		return fmt.Errorf("failed to add user group member: %w", err)
+	}
+
+	s.recordMembership(ctx, groupID, audit.Change{Field: "member", NewValue: req.UserID})
+	return nil
+}
+
+// RemoveMember removes a user from a user group, returning ErrNotGroupMember if they are not
+// a member
+func (s *userGroupService) RemoveMember(ctx context.Context, groupID, userID string) error {
+	s.logger.Printf("Removing user %s from user group %s", userID, groupID)
+
+	removed, err := s.repo.RemoveMember(ctx, groupID, userID)
+	if err != nil {
+		s.logger.Printf("Error removing user group member: %v", err)
+		return fmt.Errorf("failed to remove user group member: %w", err)
+	}
+	if !removed {
+		return ErrNotGroupMember
+	}
+
+	s.recordMembership(ctx, groupID, audit.Change{Field: "member", OldValue: userID})
+	return nil
+}
+
+// ListMembers retrieves the members of a user group with pagination
+func (s *userGroupService) ListMembers(ctx context.Context, groupID string, page pagination.Request) (pagination.Page[models.UserResponse], error) {
+	s.logger.Printf("Listing members of user group %s (limit: %d, offset: %d)", groupID, page.Limit, page.Offset)
+
+	if _, err := s.getGroup(ctx, groupID); err != nil {
+		s.logger.Printf("Error getting user group to list members: %v", err)
+		return pagination.Page[models.UserResponse]{}, fmt.Errorf("failed to get user group: %w", err)
+	}
+
+	users, err := s.repo.ListMembers(ctx, groupID, page)
+	if err != nil {
+		s.logger.Printf("Error listing user group members: %v", err)
+		return pagination.Page[models.UserResponse]{}, fmt.Errorf("failed to list user group members: %w", err)
+	}
+
+	return pagination.Map(users, mapUserToResponse), nil
+}
+
+// ListByUser retrieves the user groups a user is a member of
+func (s *userGroupService) ListByUser(ctx context.Context, userID string) ([]models.UserGroupResponse, error) {
+	s.logger.Println("Listing user groups of user:", userID)
+
+	if _, err := s.users.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	groups, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		s.logger.Printf("Error listing user groups of user: %v", err)
+		return nil, fmt.Errorf("failed to list user groups of user: %w", err)
+	}
+
+	return s.mapUserGroupsToResponse(groups), nil
+}
+
+// recordMembership records a member joining or leaving a user group in the audit trail; the
+// change is made even if recording it fails
+func (s *userGroupService) recordMembership(ctx context.Context, groupID string, change audit.Change) {
+	if err := s.audit.Record(ctx, audit.EntityUserGroup, groupID, models.EditActionUpdate, []audit.Change{change}); err != nil {
+		s.logger.Printf("Error recording user group membership: %v", err)
+	}
+}
+
+// getGroup retrieves a user group by ID, returning ErrUserGroupNotFound if there is no such group
+func (s *userGroupService) getGroup(ctx context.Context, id string) (models.UserGroup, error) {
+	group, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.UserGroup{}, fmt.Errorf("%w: %s", ErrUserGroupNotFound, id)
+	}
+	return group, err
+}
+
+// Helper function to map UserGroup to UserGroupResponse
+func (s *userGroupService) mapUserGroupToResponse(group models.UserGroup) models.UserGroupResponse {
+	return models.UserGroupResponse{
+		ID:          group.ID,
+		DisplayName: group.DisplayName,
+		CreatedAt:   group.CreatedAt,
+		UpdatedAt:   group.UpdatedAt,
+	}
+}
+
+// mapUserGroupsToResponse maps a list of user groups to their responses
+func (s *userGroupService) mapUserGroupsToResponse(groups []models.UserGroup) []models.UserGroupResponse {
+	var responseList []models.UserGroupResponse
+	for _, group := range groups {
+		responseList = append(responseList, s.mapUserGroupToResponse(group))
+	}
+	return responseList
+}
@@ -0,0 +1,441 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"apm/internal/apperr"
+	"apm/internal/audit"
+	"apm/internal/db/repository"
+	"apm/internal/models"
+	"apm/internal/pagination"
+	"apm/internal/tenant"
+
+	"github.com/jackc/pgx/v4"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Ensure implementation satisfies the interface
+var _ UserService = (*userService)(nil)
+
+// Errors returned when a user cannot log in
+var (
+	ErrInvalidCredentials = apperr.Unauthorized("invalid credentials")
+	ErrLoginNotAllowed    = apperr.Forbidden("user is not allowed to log in")
+)
+
+// Errors returned when managing users
+var (
+	ErrUserNotFound = apperr.NotFound("user not found")
+	ErrEmailTaken   = apperr.Conflict("a user with this email already exists")
+	ErrLastAdmin    = apperr.Conflict("the last administrator of an organization cannot be removed or demoted")
+
+	ErrSoleBusinessOwner = apperr.Conflict("the only business owner of active software cannot be removed or deactivated")
+)
+
+// dummyPasswordHash is compared against when no user has the email, so that a login for an
+// unknown email takes as long as one for a known email
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
+// userService implements UserService
+type userService struct {
+	repo         repository.UserRepository
+	stakeholders repository.StakeholderRepository
+	tokens       repository.RefreshTokenRepository
+	audit        *audit.Trail
+	logger       *log.Logger
+}
+
+// NewUserService creates a new user service; the sessions of a user, whose refresh tokens are
+// kept in the token repository, are revoked when their password or role changes. Users who
+// are the only business owner of active software, by their stakeholder assignments, cannot
+// be removed or deactivated. Changes to users are recorded in the audit trail.
+func NewUserService(
+	repo repository.UserRepository,
+	stakeholders repository.StakeholderRepository,
+	tokens repository.RefreshTokenRepository,
+	trail *audit.Trail,
+	logger *log.Logger,
+) UserService {
+	return &userService{
+		repo:         repo,
+		stakeholders: stakeholders,
+		tokens:       tokens,
+		audit:        trail,
+		logger:       logger,
+	}
+}
+
+// Create creates a new user with a hashed password in the organization of the request, or
+// else of the context. Users created without a password get a random one, which nobody
+// knows, until they reset it.
+func (s *userService) Create(ctx context.Context, req models.CreateUserRequest) (models.UserResponse, error) {
+	s.logger.Println("Creating new user:", req.Email)
+
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	if err := validate.Struct(req); err != nil {
+		return models.UserResponse{}, validationError("user", err)
+	}
+
+	if _, err := s.repo.GetByEmail(ctx, req.Email); err == nil {
+		return models.UserResponse{}, fmt.Errorf("%w: %s", ErrEmailTaken, req.Email)
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		s.logger.Printf("Error checking user email: %v", err)
+		return models.UserResponse{}, fmt.Errorf("failed to check user email: %w", err)
+	}
+
+	if req.OrganizationID == "" {
+		organizationID, err := tenant.OrganizationID(ctx)
+		if err != nil {
+			return models.UserResponse{}, err
+		}
+		req.OrganizationID = organizationID
+	}
+
+	password := req.Password
+	if password == "" {
+		password = generateSessionID()
+	}
+	passwordHash, err := hashPassword(password)
+	if err != nil {
+		s.logger.Printf("Error hashing password: %v", err)
+		return models.UserResponse{}, err
+	}
+
+	user, err := s.repo.Create(ctx, models.User{
+		OrganizationID: req.OrganizationID,
+		Email:          req.Email,
+		PasswordHash:   passwordHash,
+		FirstName:      req.FirstName,
+		LastName:       req.LastName,
+		Role:           req.Role,
+	})
+	if err != nil {
+		s.logger.Printf("Error creating user: %v", err)
+		return models.UserResponse{}, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	// The change is made even if recording it fails
+	if err := s.audit.RecordCreate(ctx, audit.EntityUser, user.ID, user); err != nil {
+		s.logger.Printf("Error recording created user: %v", err)
+	}
+
+	return mapUserToResponse(user), nil
+}
+
+// GetByID retrieves a user by ID
+func (s *userService) GetByID(ctx context.Context, id string) (models.UserResponse, error) {
+	s.logger.Println("Getting user by ID:", id)
+
+	user, err := s.getUser(ctx, id)
+	if err != nil {
+		s.logger.Printf("Error getting user by ID: %v", err)
+		return models.UserResponse{}, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return mapUserToResponse(user), nil
+}
+
+// List retrieves a list of users with pagination
+func (s *userService) List(ctx context.Context, page pagination.Request) (pagination.Page[models.UserResponse], error) {
+	s.logger.Printf("Listing users (limit: %d, offset: %d)", page.Limit, page.Offset)
+
+	users, err := s.repo.List(ctx, page)
+	if err != nil {
+		s.logger.Printf("Error listing users: %v", err)
+		return pagination.Page[models.UserResponse]{}, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	return pagination.Map(users, mapUserToResponse), nil
+}
+
+// Update updates the profile and role of an existing user
+func (s *userService) Update(ctx context.Context, id string, req models.UpdateUserRequest) error {
+	s.logger.Println("Updating user with ID:", id)
+
+	if err := validate.Struct(req); err != nil {
+		return validationError("user", err)
+	}
+
+	user, err := s.getUser(ctx, id)
+	if err != nil {
+		s.logger.Printf("Error getting user to update: %v", err)
+		return fmt.Errorf("failed to get user for update: %w", err)
+	}
+	before := user
+
+	// Update fields if they are provided
+	if req.FirstName != "" {
+		user.FirstName = req.FirstName
+	}
+	if req.LastName != "" {
+		user.LastName = req.LastName
+	}
+	roleChanged := req.Role != "" && req.Role != user.Role
+	if roleChanged {
+		if err := s.ensureNotLastAdmin(ctx, user); err != nil {
+			return err
+		}
+		user.Role = req.Role
+	}
+	if req.AvatarURL != "" {
+		user.AvatarURL = req.AvatarURL
+	}
+
+	if err := s.repo.Update(ctx, user); err != nil {
+		s.logger.Printf("Error updating user: %v", err)
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	s.recordUpdate(ctx, before, user)
+
+	// Sessions carry the role in their access tokens, so they must start over with the new role
+	if roleChanged {
+		return s.revokeSessions(ctx, user.ID)
+	}
+
+	return nil
+}
+
+// Delete removes a user
+func (s *userService) Delete(ctx context.Context, id string) error {
+	s.logger.Println("Deleting user with ID:", id)
+
+	user, err := s.getUser(ctx, id)
+	if err != nil {
+		s.logger.Printf("Error getting user to delete: %v", err)
+		return fmt.Errorf("failed to get user for deletion: %w", err)
+	}
+	if err := s.ensureNotLastAdmin(ctx, user); err != nil {
+		return err
+	}
+	if err := s.ensureNotSoleBusinessOwner(ctx, user); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		s.logger.Printf("Error deleting user: %v", err)
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	if err := s.audit.RecordDelete(ctx, audit.EntityUser, id, user); err != nil {
+		s.logger.Printf("Error recording deleted user: %v", err)
+	}
+
+	return nil
+}
+
+// Deactivate stops a user from logging in and ends all their sessions, keeping their account
+func (s *userService) Deactivate(ctx context.Context, id string) error {
+	s.logger.Println("Deactivating user with ID:", id)
+
+	user, err := s.getUser(ctx, id)
+	if err != nil {
+		s.logger.Printf("Error getting user to deactivate: %v", err)
+		return fmt.Errorf("failed to get user for deactivation: %w", err)
+	}
+	if user.DeactivatedAt != nil {
+		return nil
+	}
+	if err := s.ensureNotLastAdmin(ctx, user); err != nil {
+		return err
+	}
+	if err := s.ensureNotSoleBusinessOwner(ctx, user); err != nil {
+		return err
+	}
+	before := user
+
+	now := time.Now().UTC()
+	user.DeactivatedAt = &now
+	if err := s.repo.Update(ctx, user); err != nil {
+		s.logger.Printf("Error deactivating user: %v", err)
+		return fmt.Errorf("failed to deactivate user: %w", err)
+	}
+	s.recordUpdate(ctx, before, user)
+
+	return s.revokeSessions(ctx, user.ID)
+}
+
+// Activate lets a deactivated user log in again
+func (s *userService) Activate(ctx context.Context, id string) error {
+	s.logger.Println("Activating user with ID:", id)
+
+	user, err := s.getUser(ctx, id)
+	if err != nil {
+		s.logger.Printf("Error getting user to activate: %v", err)
+		return fmt.Errorf("failed to get user for activation: %w", err)
+	}
+	if user.DeactivatedAt == nil {
+		return nil
+	}
+	before := user
+
+	user.DeactivatedAt = nil
+	if err := s.repo.Update(ctx, user); err != nil {
+		s.logger.Printf("Error activating user: %v", err)
+		return fmt.Errorf("failed to activate user: %w", err)
+	}
+	s.recordUpdate(ctx, before, user)
+
+	return nil
+}
+
+// Authenticate verifies the credentials of a user. It returns ErrInvalidCredentials for an
+// unknown email or a wrong password, and ErrLoginNotAllowed for deactivated users and for
+// stakeholders, who are registered in the portfolio but do not use the application.
+func (s *userService) Authenticate(ctx context.Context, email, password string) (models.UserResponse, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	user, err := s.repo.GetByEmail(ctx, email)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			s.logger.Printf("Error getting user to authenticate: %v", err)
+			return models.UserResponse{}, fmt.Errorf("failed to get user: %w", err)
+		}
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return models.UserResponse{}, ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		s.logger.Println("Failed login for user:", user.ID)
+		return models.UserResponse{}, ErrInvalidCredentials
+	}
+
+	if user.Role == models.RoleStakeholder || user.DeactivatedAt != nil {
+		return models.UserResponse{}, ErrLoginNotAllowed
+	}
+
+	return mapUserToResponse(user), nil
+}
+
+// ChangePassword replaces the password of a user after verifying their current password, and
+// ends all their sessions
+func (s *userService) ChangePassword(ctx context.Context, id string, req models.ChangePasswordRequest) error {
+	s.logger.Println("Changing password of user:", id)
+
+	if err := validate.Struct(req); err != nil {
+		return validationError("password change", err)
+	}
+
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Printf("Error getting user to change password: %v", err)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.CurrentPassword)); err != nil {
+		return ErrInvalidCredentials
+	}
+
+	passwordHash, err := hashPassword(req.NewPassword)
+	if err != nil {
+		s.logger.Printf("Error hashing password: %v", err)
+		return err
+	}
+	user.PasswordHash = passwordHash
+
+	if err := s.repo.Update(ctx, user); err != nil {
+		s.logger.Printf("Error updating password: %v", err)
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	return s.revokeSessions(ctx, user.ID)
+}
+
+// getUser retrieves a user by ID, returning ErrUserNotFound if there is no such user. When the
+// context acts for an organization, users of other organizations are not found either.
+func (s *userService) getUser(ctx context.Context, id string) (models.User, error) {
+	user, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.User{}, fmt.Errorf("%w: %s", ErrUserNotFound, id)
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if organizationID, err := tenant.OrganizationID(ctx); err == nil && organizationID != user.OrganizationID {
+		return models.User{}, fmt.Errorf("%w: %s", ErrUserNotFound, id)
+	}
+	return user, nil
+}
+
+// ensureNotLastAdmin returns ErrLastAdmin if the user is the last active administrator of
+// their organization, who may not be demoted, deactivated or removed
+func (s *userService) ensureNotLastAdmin(ctx context.Context, user models.User) error {
+	if user.Role != models.RoleOrganizationAdmin || user.DeactivatedAt != nil {
+		return nil
+	}
+
+	counts, err := s.repo.CountByRole(ctx, user.OrganizationID)
+	if err != nil {
+		s.logger.Printf("Error counting users of organization: %v", err)
+		return fmt.Errorf("failed to count users: %w", err)
+	}
+	if counts[models.RoleOrganizationAdmin] <= 1 {
+		return ErrLastAdmin
+	}
+	return nil
+}
+
+// ensureNotSoleBusinessOwner returns ErrSoleBusinessOwner if the user is the only business
+// owner of active software, which would be left without one
+func (s *userService) ensureNotSoleBusinessOwner(ctx context.Context, user models.User) error {
+	count, err := s.stakeholders.CountSoleBusinessOwnerships(tenant.WithOrganizationID(ctx, user.OrganizationID), user.ID)
+	if err != nil {
+		s.logger.Printf("Error counting business ownerships of user: %v", err)
+		return fmt.Errorf("failed to count business ownerships: %w", err)
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: %d software", ErrSoleBusinessOwner, count)
+	}
+	return nil
+}
+
+// recordUpdate records the changes to a user in the audit trail; the change is made even if
+// recording it fails
+func (s *userService) recordUpdate(ctx context.Context, before, after models.User) {
+	if err := s.audit.RecordUpdate(ctx, audit.EntityUser, after.ID, before, after); err != nil {
+		s.logger.Printf("Error recording updated user: %v", err)
+	}
+}
+
+// revokeSessions ends all sessions of a user
+func (s *userService) revokeSessions(ctx context.Context, userID string) error {
+	if err := s.tokens.RevokeAllForUser(ctx, userID); err != nil {
+		s.logger.Printf("Error revoking sessions of user: %v", err)
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	s.logger.Println("Revoked all sessions of user:", userID)
+	return nil
+}
+
+// hashPassword hashes a password with bcrypt
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// Helper function to map User to UserResponse
+func mapUserToResponse(user models.User) models.UserResponse {
+	return models.UserResponse{
+		ID:             user.ID,
+		OrganizationID: user.OrganizationID,
+		Email:          user.Email,
+		FirstName:      user.FirstName,
+		LastName:       user.LastName,
+		Role:           user.Role,
+		AvatarURL:      user.AvatarURL,
+		MFAEnabled:     user.MFAEnabled,
+		Active:         user.DeactivatedAt == nil,
+		DeactivatedAt:  user.DeactivatedAt,
+		CreatedAt:      user.CreatedAt,
+		UpdatedAt:      user.UpdatedAt,
+	}
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+
+	"apm/internal/apperr"
+	"apm/internal/db/repository"
+	"apm/internal/models"
+	"apm/internal/tenant"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// memoryUserRepository keeps users in memory. Like the Postgres repository, it looks users up
+// by ID across organizations, leaving the check of the organization to the service.
+type memoryUserRepository struct {
+	repository.UserRepository
+	users map[string]models.User
+}
+
+func (r *memoryUserRepository) GetByID(ctx context.Context, id string) (models.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return models.User{}, apperr.Wrap(apperr.KindNotFound, "record not found", pgx.ErrNoRows)
+	}
+	return user, nil
+}
+
+func (r *memoryUserRepository) Update(ctx context.Context, user models.User) error {
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *memoryUserRepository) Delete(ctx context.Context, id string) error {
+	delete(r.users, id)
+	return nil
+}
+
+func (r *memoryUserRepository) CountAll(ctx context.Context, organizationID string) (int, error) {
+	count := 0
+	for _, user := range r.users {
+		if user.OrganizationID == organizationID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryUserRepository) CountByRole(ctx context.Context, organizationID string) (map[models.UserRole]int, error) {
+	counts := make(map[models.UserRole]int)
+	for _, user := range r.users {
+		if user.OrganizationID == organizationID && user.DeactivatedAt == nil {
+			counts[user.Role]++
+		}
+	}
+	return counts, nil
+}
+
+func TestUserServiceTenantIsolation(t *testing.T) {
+	repo := &memoryUserRepository{users: map[string]models.User{
+		"user-a": {
+			ID:             "user-a",
+			OrganizationID: "org-a",
+			Email:          "ada@a.example",
+			FirstName:      "Ada",
+			Role:           models.RoleApplicationPortfolioManager,
+		},
+	}}
+	service := NewUserService(repo, nil, nil, nil, log.New(io.Discard, "", 0))
+
+	orgA := tenant.WithOrganizationID(context.Background(), "org-a")
+	orgB := tenant.WithOrganizationID(context.Background(), "org-b")
+
+	if _, err := service.GetByID(orgA, "user-a"); err != nil {
+		t.Fatalf("GetByID() in organization A = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		call func(ctx context.Context) error
+	}{
+		{"read", func(ctx context.Context) error {
+			_, err := service.GetByID(ctx, "user-a")
+			return err
+		}},
+		{"update", func(ctx context.Context) error {
+			return service.Update(ctx, "user-a", models.UpdateUserRequest{FirstName: "Mallory"})
+		}},
+		{"deactivate", func(ctx context.Context) error {
+			return service.Deactivate(ctx, "user-a")
+		}},
+		{"delete", func(ctx context.Context) error {
+			return service.Delete(ctx, "user-a")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(orgB); !errors.Is(err, ErrUserNotFound) {
+				t.Errorf("%s in organization B = %v, want %v", tt.name, err, ErrUserNotFound)
+			}
+		})
+	}
+
+	user, ok := repo.users["user-a"]
+	if !ok {
+		t.Fatal("user of organization A was deleted from organization B")
+	}
+	if user.FirstName != "Ada" || user.DeactivatedAt != nil {
+		t.Errorf("user of organization A was changed from organization B: %+v", user)
+	}
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"apm/internal/apperr"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// validate is the shared validator used for the `validate` struct tags on request models
+var validate = newValidator()
+
+// newValidator creates a validator that reports fields by their JSON names
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
+
+// validationError creates the validation error of an invalid subject, such as a request,
+// listing what is wrong with it
+func validationError(subject string, err error) error {
+	return apperr.Validation(fmt.Sprintf("invalid %s: %s", subject, strings.Join(validationMessages(err), ", ")))
+}
+
+// validationMessages converts a validation error into human readable messages
+func validationMessages(err error) []string {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return []string{err.Error()}
+	}
+
+	messages := make([]string, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		switch fieldErr.Tag() {
+		case "required":
+			messages = append(messages, fmt.Sprintf("%s is required", fieldErr.Field()))
+		case "oneof":
+			messages = append(messages, fmt.Sprintf("%s must be one of [%s], got %q", fieldErr.Field(), fieldErr.Param(), fieldErr.Value()))
+		case "email":
+			messages = append(messages, fmt.Sprintf("%s must be a valid email address", fieldErr.Field()))
+		case "url":
+			messages = append(messages, fmt.Sprintf("%s must be a valid URL", fieldErr.Field()))
+		case "min", "max":
+			messages = append(messages, fmt.Sprintf("%s must have %s length %s", fieldErr.Field(), fieldErr.Tag(), fieldErr.Param()))
+		default:
+			messages = append(messages, fmt.Sprintf("%s failed %s validation", fieldErr.Field(), fieldErr.Tag()))
+		}
+	}
+	return messages
+}
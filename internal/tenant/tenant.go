@@ -0,0 +1,30 @@
+// Package tenant carries the organization a request acts for through its context, so that
+// repositories can scope every query to the data of that organization.
+package tenant
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoOrganization is returned when tenant-owned data is accessed without an organization
+// in the context; it points at a route or job that skipped setting one
+var ErrNoOrganization = errors.New("no organization in context")
+
+// contextKey is the type of the context key the organization ID is stored under
+type contextKey struct{}
+
+// WithOrganizationID returns a copy of the context acting for the organization
+func WithOrganizationID(ctx context.Context, organizationID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, organizationID)
+}
+
+// OrganizationID returns the ID of the organization the context acts for, or
+// ErrNoOrganization if it does not act for one
+func OrganizationID(ctx context.Context) (string, error) {
+	organizationID, ok := ctx.Value(contextKey{}).(string)
+	if !ok || organizationID == "" {
+		return "", ErrNoOrganization
+	}
+	return organizationID, nil
+}
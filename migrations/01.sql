@@ -1,4 +1,4 @@
--- migrations/1.sql
+-- migrations/01.sql
 -- Application Portfolio Management (APM) Database Initialization
 
 -- Enable UUID extension
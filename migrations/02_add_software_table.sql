@@ -1,4 +1,4 @@
--- migrations/2_add_software_table.sql
+-- migrations/02_add_software_table.sql
 -- Add the 'software' table for storing software applications
 
 -- Create software table
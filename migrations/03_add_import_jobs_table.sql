@@ -0,0 +1,35 @@
+-- migrations/03_add_import_jobs_table.sql
+-- Add the 'import_jobs' table for tracking bulk imports
+
+-- Create import_jobs table
+CREATE TABLE import_jobs (
+    id VARCHAR(255) PRIMARY KEY,
+    file_name VARCHAR(255) NOT NULL,
+    file_content BYTEA NOT NULL,
+    uploaded_by VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- 'pending', 'running', 'done', 'failed'
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    created_rows INTEGER NOT NULL DEFAULT 0,
+    skipped_rows INTEGER NOT NULL DEFAULT 0,
+    failed_rows INTEGER NOT NULL DEFAULT 0,
+    report JSONB,
+    error_message TEXT,
+    rerun_of VARCHAR(255) REFERENCES import_jobs(id) ON DELETE SET NULL,
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create index for listing the most recent imports
+CREATE INDEX idx_import_jobs_created_at ON import_jobs(created_at DESC);
+CREATE INDEX idx_import_jobs_status ON import_jobs(status);
+
+-- Create updated_at trigger for import_jobs table
+CREATE TRIGGER update_import_jobs_timestamp
+BEFORE UPDATE ON import_jobs
+FOR EACH ROW
+EXECUTE FUNCTION update_timestamp();
+
+-- Add a comment to document the table
+COMMENT ON TABLE import_jobs IS 'Uploaded import files with their processing status and per-row report';
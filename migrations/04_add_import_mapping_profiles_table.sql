@@ -0,0 +1,26 @@
+-- migrations/04_add_import_mapping_profiles_table.sql
+-- Add the 'import_mapping_profiles' table for translating source files onto software fields
+
+-- Create import_mapping_profiles table
+CREATE TABLE import_mapping_profiles (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    description TEXT,
+    column_mapping JSONB NOT NULL DEFAULT '{}', -- source column -> software field
+    value_mapping JSONB NOT NULL DEFAULT '{}', -- software field -> source value -> field value
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Remember which profile an import job was mapped with, so it can be re-run the same way
+ALTER TABLE import_jobs
+    ADD COLUMN mapping_profile_id VARCHAR(255) REFERENCES import_mapping_profiles(id) ON DELETE SET NULL;
+
+-- Create updated_at trigger for import_mapping_profiles table
+CREATE TRIGGER update_import_mapping_profiles_timestamp
+BEFORE UPDATE ON import_mapping_profiles
+FOR EACH ROW
+EXECUTE FUNCTION update_timestamp();
+
+-- Add a comment to document the table
+COMMENT ON TABLE import_mapping_profiles IS 'Saved column and value mappings for imports from different inventory tools';
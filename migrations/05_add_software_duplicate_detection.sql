@@ -0,0 +1,12 @@
+-- migrations/05_add_software_duplicate_detection.sql
+-- Support matching imported rows against existing software
+
+-- Create indexes for duplicate lookups by foreign key and fuzzy display name
+CREATE INDEX idx_software_foreign_key ON software(LOWER(foreign_key));
+CREATE INDEX idx_software_display_name_trgm ON software USING GIN (display_name gin_trgm_ops);
+
+-- Record how an import job handles duplicates and how many rows it updated or flagged
+ALTER TABLE import_jobs
+    ADD COLUMN duplicate_action VARCHAR(20) NOT NULL DEFAULT 'skip', -- 'skip', 'update', 'flag'
+    ADD COLUMN updated_rows INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN flagged_rows INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,30 @@
+-- migrations/06_add_classification_suggestions_table.sql
+-- Add the 'classification_suggestions' table for reviewing classifier output
+
+-- Create classification_suggestions table
+CREATE TABLE classification_suggestions (
+    id VARCHAR(255) PRIMARY KEY,
+    software_id VARCHAR(255) NOT NULL REFERENCES software(id) ON DELETE CASCADE,
+    classifier VARCHAR(100) NOT NULL,
+    field VARCHAR(50) NOT NULL, -- 'software_type', 'software_subtype', 'functional_category', 'vendor'
+    value VARCHAR(255) NOT NULL,
+    confidence DOUBLE PRECISION NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- 'pending', 'accepted', 'rejected'
+    reviewed_by VARCHAR(255),
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for the per-software list and the review queue
+CREATE INDEX idx_classification_suggestions_software ON classification_suggestions(software_id);
+CREATE INDEX idx_classification_suggestions_status ON classification_suggestions(status, created_at);
+
+-- Create updated_at trigger for classification_suggestions table
+CREATE TRIGGER update_classification_suggestions_timestamp
+BEFORE UPDATE ON classification_suggestions
+FOR EACH ROW
+EXECUTE FUNCTION update_timestamp();
+
+-- Add a comment to document the table
+COMMENT ON TABLE classification_suggestions IS 'Classifier suggestions awaiting review by a portfolio manager';
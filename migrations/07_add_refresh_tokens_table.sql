@@ -0,0 +1,21 @@
+-- migrations/07_add_refresh_tokens_table.sql
+-- Add the 'refresh_tokens' table for login sessions
+
+-- Create refresh_tokens table; only a SHA-256 hash of each token is stored
+CREATE TABLE refresh_tokens (
+    id VARCHAR(255) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    session_id VARCHAR(255) NOT NULL, -- shared by the tokens a login is rotated through
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for revoking the sessions of a user and purging expired tokens
+CREATE INDEX idx_refresh_tokens_user ON refresh_tokens(user_id);
+CREATE INDEX idx_refresh_tokens_session ON refresh_tokens(session_id);
+CREATE INDEX idx_refresh_tokens_expires_at ON refresh_tokens(expires_at);
+
+-- Add a comment to document the table
+COMMENT ON TABLE refresh_tokens IS 'Rotating refresh tokens of user sessions';
@@ -0,0 +1,17 @@
+-- migrations/08_add_mfa_recovery_codes_table.sql
+-- Add the 'mfa_recovery_codes' table for logging in without an authenticator app
+
+-- Create mfa_recovery_codes table; only a SHA-256 hash of each code is stored
+CREATE TABLE mfa_recovery_codes (
+    id VARCHAR(255) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create index for looking up the codes of a user
+CREATE INDEX idx_mfa_recovery_codes_user ON mfa_recovery_codes(user_id, code_hash);
+
+-- Add a comment to document the table
+COMMENT ON TABLE mfa_recovery_codes IS 'Single-use recovery codes of users with multi-factor authentication';
@@ -0,0 +1,49 @@
+-- Scope the portfolio data to organizations, so that each organization only sees its own
+
+-- Add organization_id to the tenant-owned tables
+ALTER TABLE software
+    ADD COLUMN organization_id UUID REFERENCES organizations(id) ON DELETE CASCADE;
+ALTER TABLE import_jobs
+    ADD COLUMN organization_id UUID REFERENCES organizations(id) ON DELETE CASCADE;
+ALTER TABLE import_mapping_profiles
+    ADD COLUMN organization_id UUID REFERENCES organizations(id) ON DELETE CASCADE;
+ALTER TABLE classification_suggestions
+    ADD COLUMN organization_id UUID REFERENCES organizations(id) ON DELETE CASCADE;
+
+-- Create a default organization for existing data if there is no organization yet, as the
+-- data could not be assigned to one otherwise
+INSERT INTO organizations (name, display_name, subdomain)
+SELECT 'default', 'Default organization', 'default'
+WHERE NOT EXISTS (SELECT 1 FROM organizations)
+    AND (
+        EXISTS (SELECT 1 FROM software)
+        OR EXISTS (SELECT 1 FROM import_jobs)
+        OR EXISTS (SELECT 1 FROM import_mapping_profiles)
+    );
+
+-- Assign existing data, created before there were tenants, to the oldest organization
+UPDATE software
+SET organization_id = (SELECT id FROM organizations ORDER BY created_at LIMIT 1);
+UPDATE import_jobs
+SET organization_id = (SELECT id FROM organizations ORDER BY created_at LIMIT 1);
+UPDATE import_mapping_profiles
+SET organization_id = (SELECT id FROM organizations ORDER BY created_at LIMIT 1);
+UPDATE classification_suggestions
+SET organization_id = software.organization_id
+FROM software
+WHERE software.id = classification_suggestions.software_id;
+
+ALTER TABLE software ALTER COLUMN organization_id SET NOT NULL;
+ALTER TABLE import_jobs ALTER COLUMN organization_id SET NOT NULL;
+ALTER TABLE import_mapping_profiles ALTER COLUMN organization_id SET NOT NULL;
+ALTER TABLE classification_suggestions ALTER COLUMN organization_id SET NOT NULL;
+
+-- Mapping profile names only need to be unique within an organization
+ALTER TABLE import_mapping_profiles DROP CONSTRAINT import_mapping_profiles_name_key;
+ALTER TABLE import_mapping_profiles
+    ADD CONSTRAINT unique_mapping_profile_name_per_org UNIQUE (organization_id, name);
+
+-- Create indexes for the per-organization lists
+CREATE INDEX idx_software_organization ON software(organization_id, created_at DESC);
+CREATE INDEX idx_import_jobs_organization ON import_jobs(organization_id, created_at DESC);
+CREATE INDEX idx_classification_suggestions_organization ON classification_suggestions(organization_id, status, created_at);
@@ -0,0 +1,2 @@
+-- Add the 'platform_admin' role for the operators of the platform, who manage organizations
+ALTER TYPE user_role ADD VALUE IF NOT EXISTS 'platform_admin';
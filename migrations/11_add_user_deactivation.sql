@@ -0,0 +1,2 @@
+-- Allow users to be deactivated, keeping their account but stopping them from logging in
+ALTER TABLE users ADD COLUMN deactivated_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,32 @@
+-- Add the 'user_groups' and 'user_to_groups' tables, so applications can be owned by teams
+
+-- Create user_groups table
+CREATE TABLE user_groups (
+    id VARCHAR(255) PRIMARY KEY,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    display_name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create user_to_groups table linking users to the groups they are members of
+CREATE TABLE user_to_groups (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_group_id VARCHAR(255) NOT NULL REFERENCES user_groups(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, user_group_id)
+);
+
+-- Create indexes for listing the groups of an organization and the members of a group
+CREATE INDEX idx_user_groups_organization ON user_groups(organization_id, display_name);
+CREATE INDEX idx_user_to_groups_group ON user_to_groups(user_group_id);
+
+-- Create updated_at trigger for user_groups table
+CREATE TRIGGER update_user_groups_timestamp
+BEFORE UPDATE ON user_groups
+FOR EACH ROW
+EXECUTE FUNCTION update_timestamp();
+
+-- Add comments to document the tables
+COMMENT ON TABLE user_groups IS 'Teams of users within an organization';
+COMMENT ON TABLE user_to_groups IS 'Memberships of users in user groups';
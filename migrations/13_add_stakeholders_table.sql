@@ -0,0 +1,29 @@
+-- Add the 'stakeholders' table assigning users to software in a stakeholder role
+
+-- Create stakeholders table
+CREATE TABLE stakeholders (
+    id VARCHAR(255) PRIMARY KEY,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    software_id VARCHAR(255) NOT NULL REFERENCES software(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(50) NOT NULL CHECK (role IN (
+        'business_owner', 'technical_owner', 'data_steward', 'product_owner', 'security_officer', 'support_contact'
+    )),
+    foreign_key VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (software_id, user_id, role)
+);
+
+-- Create indexes for listing the stakeholders of software and the assignments of a user
+CREATE INDEX idx_stakeholders_software ON stakeholders(organization_id, software_id);
+CREATE INDEX idx_stakeholders_user ON stakeholders(organization_id, user_id);
+
+-- Create updated_at trigger for stakeholders table
+CREATE TRIGGER update_stakeholders_timestamp
+BEFORE UPDATE ON stakeholders
+FOR EACH ROW
+EXECUTE FUNCTION update_timestamp();
+
+-- Add a comment to document the table
+COMMENT ON TABLE stakeholders IS 'Users responsible for software, such as its business and technical owners';
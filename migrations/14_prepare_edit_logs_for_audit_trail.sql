@@ -0,0 +1,27 @@
+-- Prepare the 'edit_logs' table for the audit trail: scope it to organizations, group the
+-- fields of a change, accept the IDs of all tables and keep the history of deleted users
+
+-- Add organization_id, taken from the user who made the change
+ALTER TABLE edit_logs ADD COLUMN organization_id UUID REFERENCES organizations(id) ON DELETE CASCADE;
+UPDATE edit_logs SET organization_id = users.organization_id FROM users WHERE users.id = edit_logs.user_id;
+ALTER TABLE edit_logs ALTER COLUMN organization_id SET NOT NULL;
+
+-- Group the field rows written for a single change
+ALTER TABLE edit_logs ADD COLUMN change_id VARCHAR(255);
+
+-- Software and newer tables use IDs that are not UUIDs
+ALTER TABLE edit_logs ALTER COLUMN entity_id TYPE VARCHAR(255);
+
+-- Changes outlive their users, and some are made without one, such as by an emailed link
+ALTER TABLE edit_logs ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE edit_logs DROP CONSTRAINT edit_logs_user_id_fkey;
+ALTER TABLE edit_logs ADD CONSTRAINT edit_logs_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
+
+-- Replace the entity index with one for browsing the history of a record within an organization
+DROP INDEX idx_edit_logs_entity;
+CREATE INDEX idx_edit_logs_entity ON edit_logs(organization_id, entity_type, entity_id, timestamp);
+CREATE INDEX idx_edit_logs_change ON edit_logs(change_id);
+
+-- Add a comment to document the table
+COMMENT ON TABLE edit_logs IS 'Audit trail of field-level changes to the records of an organization';
@@ -0,0 +1,26 @@
+-- Extend the 'access_logs' table to record logins, including failed ones of unknown users,
+-- and the authenticated requests made to the API
+
+-- Failed logins may be made with the email of no user, who then has no role either
+ALTER TABLE access_logs ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE access_logs ALTER COLUMN role DROP NOT NULL;
+
+-- Add organization_id, taken from the user who accessed the system
+ALTER TABLE access_logs ADD COLUMN organization_id UUID REFERENCES organizations(id) ON DELETE CASCADE;
+UPDATE access_logs SET organization_id = users.organization_id FROM users WHERE users.id = access_logs.user_id;
+
+-- Distinguish logins from requests, and record what was attempted and how it ended
+ALTER TABLE access_logs ADD COLUMN event VARCHAR(20) NOT NULL DEFAULT 'request'
+    CHECK (event IN ('login', 'request'));
+ALTER TABLE access_logs ADD COLUMN email VARCHAR(255);
+ALTER TABLE access_logs ADD COLUMN success BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE access_logs ADD COLUMN method VARCHAR(10);
+ALTER TABLE access_logs ADD COLUMN path TEXT;
+ALTER TABLE access_logs ADD COLUMN status INTEGER;
+
+-- Index the access history for querying it by time, user and IP address within an organization
+CREATE INDEX idx_access_logs_organization ON access_logs(organization_id, timestamp);
+CREATE INDEX idx_access_logs_ip_address ON access_logs(ip_address, timestamp);
+
+-- Add a comment to document the table
+COMMENT ON TABLE access_logs IS 'Log of logins and authenticated requests to the system';
@@ -0,0 +1,27 @@
+-- Add the 'software_versions' table keeping a full snapshot of software after every change,
+-- for browsing its history and restoring previous versions or deleted records
+
+-- Create software_versions table; versions outlive the software they are of
+CREATE TABLE software_versions (
+    id VARCHAR(255) PRIMARY KEY,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    software_id VARCHAR(255) NOT NULL,
+    version INTEGER NOT NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore')),
+    snapshot JSONB NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (software_id, version)
+);
+
+-- Create an index for browsing the versions of software within an organization by time
+CREATE INDEX idx_software_versions_software ON software_versions(organization_id, software_id, created_at);
+
+-- Start the history of existing software with its current state
+INSERT INTO software_versions (id, organization_id, software_id, version, action, snapshot, created_at)
+SELECT REPLACE(uuid_generate_v4()::text, '-', ''), organization_id, id, 1, 'create',
+    to_jsonb(software) - 'organization_id', updated_at
+FROM software;
+
+-- Add a comment to document the table
+COMMENT ON TABLE software_versions IS 'Snapshots of software after every change, for point-in-time history and restore';
@@ -0,0 +1,19 @@
+-- Archive software and stakeholders instead of deleting them, keeping them restorable until
+-- they are purged after the retention period
+
+-- Add deleted_at and deleted_by to software
+ALTER TABLE software ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE software ADD COLUMN deleted_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+-- Add deleted_at and deleted_by to stakeholders, which are archived along with their software
+ALTER TABLE stakeholders ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE stakeholders ADD COLUMN deleted_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+-- Archived assignments no longer stop users from being assigned in the same role again
+ALTER TABLE stakeholders DROP CONSTRAINT stakeholders_software_id_user_id_role_key;
+CREATE UNIQUE INDEX idx_stakeholders_assignment ON stakeholders(software_id, user_id, role)
+    WHERE deleted_at IS NULL;
+
+-- Create indexes for finding archived records to purge
+CREATE INDEX idx_software_deleted_at ON software(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_stakeholders_deleted_at ON stakeholders(deleted_at) WHERE deleted_at IS NOT NULL;
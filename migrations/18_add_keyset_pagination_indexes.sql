@@ -0,0 +1,13 @@
+-- Index lists in the order they are paged through with cursors, which ends with the ID to tell
+-- apart rows with the same sort values
+
+-- Recreate the indexes of lists sorted by time with the ID
+DROP INDEX idx_import_jobs_organization;
+CREATE INDEX idx_import_jobs_organization ON import_jobs(organization_id, created_at DESC, id DESC);
+
+DROP INDEX idx_access_logs_organization;
+CREATE INDEX idx_access_logs_organization ON access_logs(organization_id, timestamp DESC, id DESC);
+
+-- Create indexes for the lists of stakeholders and users
+CREATE INDEX idx_stakeholders_organization ON stakeholders(organization_id, created_at DESC, id DESC);
+CREATE INDEX idx_users_organization_name ON users(organization_id, last_name, first_name, id);
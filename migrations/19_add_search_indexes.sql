@@ -0,0 +1,29 @@
+-- Support ranked, typo-tolerant searching of software, entities and categories by their text
+
+-- Add search vectors weighting names above vendors above descriptions
+ALTER TABLE software ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', display_name), 'A') ||
+    setweight(to_tsvector('english', COALESCE(vendor, '')), 'B') ||
+    setweight(to_tsvector('english', COALESCE(description, '')), 'C')
+) STORED;
+
+ALTER TABLE master_entities ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', name), 'A') ||
+    setweight(to_tsvector('english', COALESCE(description, '')), 'C')
+) STORED;
+
+ALTER TABLE categories ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', name), 'A') ||
+    setweight(to_tsvector('english', COALESCE(description, '')), 'C')
+) STORED;
+
+-- Create indexes for full-text matches
+CREATE INDEX idx_software_search ON software USING GIN (search_vector);
+CREATE INDEX idx_master_entities_search ON master_entities USING GIN (search_vector);
+CREATE INDEX idx_categories_search ON categories USING GIN (search_vector);
+
+-- Create trigram indexes for misspelled names and vendors; software display names have one already
+CREATE INDEX idx_software_vendor_trgm ON software USING GIN (vendor gin_trgm_ops);
+CREATE INDEX idx_master_entities_name_trgm ON master_entities USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_organization_entities_custom_name_trgm ON organization_entities USING GIN (custom_name gin_trgm_ops);
+CREATE INDEX idx_categories_name_trgm ON categories USING GIN (name gin_trgm_ops);
@@ -0,0 +1,8 @@
+-- Track the use of TOTP codes, so that a code cannot be used twice and guessing codes locks
+-- the user out for a while
+
+-- Add the time step of the last accepted code, codes of which and of earlier steps are
+-- rejected, the failed attempts since the last success and the end of a lockout to users
+ALTER TABLE users ADD COLUMN mfa_last_step BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN mfa_failed_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN mfa_locked_until TIMESTAMP WITH TIME ZONE;